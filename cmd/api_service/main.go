@@ -35,13 +35,21 @@ func main() {
 	schedulerService, err := scheduler.NewService(
 		log,
 		httpServer.SubscriptionService.SubscriptionRepo,
-		publisher,
-		httpServer.WeatherService,
+		httpServer.Dispatcher,
 		ctx,
 	)
 	if err != nil {
 		log.Base().Fatal().Err(err).Msg("Failed to init scheduler")
 	}
+	schedulerService.WithRetention(httpServer.PrivacyService).
+		WithMaintenance(httpServer.Maintenance).
+		WithDigest(cfg.DigestEmails).
+		WithNotifications(cfg.SchedulerEmbedded).
+		WithRunHistory(httpServer.JobRuns, cfg.CatchUpWindow, cfg.JobRunTimeout).
+		WithPageSize(cfg.NotificationPageSize)
+	if httpServer.LeaderElector != nil {
+		schedulerService.WithLeaderElection(httpServer.LeaderElector)
+	}
 	if err := schedulerService.Start(); err != nil {
 		log.Base().Fatal().Err(err).Msg("Failed to start scheduler")
 	}
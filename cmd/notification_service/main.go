@@ -9,9 +9,15 @@ import (
 	"weatherApi/internal/config"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/suppression"
 	"weatherApi/internal/service/notification"
+	"weatherApi/internal/worker"
 
 	"github.com/rs/zerolog"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
 )
 
 func main() {
@@ -34,13 +40,30 @@ func main() {
 		log.Base().Fatal().Err(err).Msg("Subscriber error")
 	}
 
+	var suppressions worker.SuppressionCheckerInterface
+	var deliveries worker.DeliveryRecorderInterface
+	if cfg.DatabaseURL != "" {
+		gormDB, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+			Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+		})
+		if err != nil {
+			log.Base().Fatal().Err(err).Msg("Failed to connect to DB")
+		}
+		suppressions = suppression.NewSuppressionRepository(gormDB)
+		deliveries = delivery.NewDeliveryRepository(gormDB)
+	} else {
+		log.Base().Warn().Msg("DB_URL is not set, suppression list is not checked and deliveries are not logged")
+	}
+
 	err = notification.Run(ctx, notification.Service{
-		Log:        log,
-		Config:     cfg,
-		SMTPClient: smtpClient,
-		Publisher:  publisher,
-		Subscriber: subscriber,
-		SignalChan: sigChan,
+		Log:          log,
+		Config:       cfg,
+		SMTPClient:   smtpClient,
+		Publisher:    publisher,
+		Subscriber:   subscriber,
+		Suppressions: suppressions,
+		Deliveries:   deliveries,
+		SignalChan:   sigChan,
 	})
 	if err != nil {
 		log.Base().Fatal().Err(err).Msg("App stopped with error")
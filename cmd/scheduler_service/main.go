@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+	"weatherApi/internal/bootstrap"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/config"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/jobrun"
+	"weatherApi/internal/repository/observation"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/scheduler"
+	serviceHealthcheck "weatherApi/internal/service/healthcheck"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func main() {
+	log := logger.NewLogger("scheduler-service", zerolog.InfoLevel)
+	cfg := config.NewSchedulerServiceConfig(log.Base())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	gormDB, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to connect to DB")
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to get sql.DB")
+	}
+
+	publisher, err := broker.NewRabbitMQPublisher(cfg.BrokerURL, log)
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to connect to RabbitMQ for publisher")
+	}
+
+	rdb := bootstrap.NewRedisClient(&cfg.WeatherConfig)
+	subscriptionRepo := subscription.NewSubscriptionRepository(gormDB)
+	weatherStack := bootstrap.NewWeatherStack(log, &cfg.WeatherConfig, rdb, observation.NewObservationRepository(gormDB))
+	dispatcher := scheduler.NewDispatcher(
+		log,
+		subscriptionRepo,
+		publisher,
+		weatherStack.Weather,
+		weatherStack.AirQuality,
+		bootstrap.NewTokenSigner(log, &cfg.LinkTokenConfig),
+	)
+
+	schedulerService, err := scheduler.NewService(log, subscriptionRepo, dispatcher, ctx)
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to init scheduler")
+	}
+	schedulerService.WithDigest(cfg.DigestEmails).
+		WithRunHistory(jobrun.NewJobRunRepository(gormDB), cfg.CatchUpWindow, cfg.JobRunTimeout).
+		WithPageSize(cfg.NotificationPageSize)
+	var leaderElector *scheduler.LeaderElector
+	if cfg.SchedulerLeaderElection {
+		leaderElector = bootstrap.NewLeaderElector(log, &cfg.SchedulerConfig, rdb, bootstrap.NotificationsLeaseName)
+		schedulerService.WithLeaderElection(leaderElector)
+	}
+
+	healthcheck := serviceHealthcheck.New(log, sqlDB)
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           newHandler(healthcheck, leaderElector),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      5 * time.Second,
+	}
+	go func() {
+		log.Base().Info().Msgf("Starting health check server on :%d", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Base().Fatal().Err(err).Msg("Health server error")
+		}
+	}()
+
+	if err := schedulerService.Start(); err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to start scheduler")
+	}
+
+	<-ctx.Done()
+	log.Base().Warn().Msg("Shutting down gracefully, press Ctrl+C again to force")
+	stop()
+
+	// jobs in progress are finished before lease is released
+	if err := schedulerService.Stop(); err != nil {
+		log.Base().Error().Err(err).Msg("Scheduler forced to shutdown with error")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Base().Error().Err(err).Msg("Failed to shutdown health server")
+	}
+	if err := publisher.Close(); err != nil {
+		log.Base().Error().Err(err).Msg("Error while closing RabbitMQ publisher")
+	}
+	if err := healthcheck.Close(); err != nil {
+		log.Base().Error().Err(err).Msg("Failed to close DB connection")
+	}
+	log.Base().Info().Msg("Graceful shutdown complete.")
+}
+
+// newHandler serves /health with DB status and scheduler leadership and /metrics for Prometheus
+func newHandler(healthcheck serviceHealthcheck.HealthCheckService, leaderElector *scheduler.LeaderElector) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		stats := healthcheck.Health()
+		if leaderElector != nil {
+			stats["leader"] = fmt.Sprint(leaderElector.IsLeader())
+			stats["instance"] = leaderElector.Holder()
+		}
+		status := http.StatusOK
+		if stats["status"] != "up" {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(stats)
+	})
+	return mux
+}
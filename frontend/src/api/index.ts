@@ -8,7 +8,9 @@ export {OpenAPI} from './core/OpenAPI';
 export type {OpenAPIConfig} from './core/OpenAPI';
 
 export {Subscription} from './models/Subscription';
+export type {SubscriptionSettings} from './models/SubscriptionSettings';
 export type {Weather} from './models/Weather';
 
+export {PrivacyService} from './services/PrivacyService';
 export {SubscriptionService} from './services/SubscriptionService';
 export {WeatherService} from './services/WeatherService';
@@ -0,0 +1,22 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type SubscriptionSettings = {
+    city?: string;
+    frequency?: 'hourly' | 'daily';
+    units?: string;
+    lang?: string;
+    include_air_quality?: boolean;
+    aqi_alert_threshold?: number;
+    /**
+     * New address waiting for confirmation
+     */
+    pending_email?: string;
+    paused?: boolean;
+    pause_reason?: 'user' | 'bounced' | 'complaint';
+    /**
+     * When paused emails resume, absent for pause until resumed
+     */
+    resume_at?: string;
+};
@@ -0,0 +1,70 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type {CancelablePromise} from '../core/CancelablePromise';
+import {OpenAPI} from '../core/OpenAPI';
+import {request as __request} from '../core/request';
+export class PrivacyService {
+    /**
+     * Request data export or erasure
+     * Sends verification link to the email if it is registered.
+     * @param email Email address data belongs to
+     * @param action What to do with the data
+     * @returns any Verification link sent if the email is registered
+     * @throws ApiError
+     */
+    public static requestLink(email: string, action: 'export' | 'erase'): CancelablePromise<any> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: 'api/v1/privacy/requests',
+            mediaType: 'application/json',
+            body: {
+                email: email,
+                action: action,
+            },
+            errors: {
+                400: `Invalid input`,
+                429: `Too many privacy requests`,
+            },
+        });
+    }
+    /**
+     * Export my data
+     * Returns all data stored about the user as JSON.
+     * @param token Token from export link
+     * @returns any Data export
+     * @throws ApiError
+     */
+    public static exportData(token: string): CancelablePromise<any> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: 'api/v1/privacy/export/{token}',
+            path: {
+                token: token,
+            },
+            errors: {
+                404: `Link is invalid or expired`,
+            },
+        });
+    }
+    /**
+     * Delete my data
+     * Permanently deletes the user and all subscriptions.
+     * @param token Token from erase link
+     * @returns any Data deleted
+     * @throws ApiError
+     */
+    public static eraseData(token: string): CancelablePromise<any> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: 'api/v1/privacy/erase/{token}',
+            path: {
+                token: token,
+            },
+            errors: {
+                404: `Link is invalid or expired`,
+            },
+        });
+    }
+}
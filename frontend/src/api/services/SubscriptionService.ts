@@ -2,6 +2,7 @@
 /* istanbul ignore file */
 /* tslint:disable */
 /* eslint-disable */
+import type {SubscriptionSettings} from '../models/SubscriptionSettings';
 import type {CancelablePromise} from '../core/CancelablePromise';
 import {OpenAPI} from '../core/OpenAPI';
 import {request as __request} from '../core/request';
@@ -51,16 +52,36 @@ export class SubscriptionService {
             },
         });
     }
+    /**
+     * Get subscription to unsubscribe from
+     * Returns settings of subscription the unsubscribe token belongs to, nothing is changed.
+     * @param token Unsubscribe token
+     * @returns SubscriptionSettings Subscription settings
+     * @throws ApiError
+     */
+    public static getUnsubscribeInfo(token: string): CancelablePromise<SubscriptionSettings> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: 'api/v1/unsubscribe/{token}',
+            path: {
+                token: token,
+            },
+            errors: {
+                400: `Invalid token`,
+                404: `Token not found`,
+            },
+        });
+    }
     /**
      * Unsubscribe from weather updates
-     * Unsubscribes an email from weather updates using the token sent in emails.
+     * Unsubscribes an email from weather updates using the token sent in weather emails.
      * @param token Unsubscribe token
      * @returns any Unsubscribed successfully
      * @throws ApiError
      */
     public static unsubscribe(token: string): CancelablePromise<any> {
         return __request(OpenAPI, {
-            method: 'GET',
+            method: 'POST',
             url: 'api/v1/unsubscribe/{token}',
             path: {
                 token: token,
@@ -71,4 +92,98 @@ export class SubscriptionService {
             },
         });
     }
+    /**
+     * Get subscription settings
+     * Returns settings of subscription using the manage token sent in weather emails.
+     * @param token Manage token
+     * @returns SubscriptionSettings Subscription settings
+     * @throws ApiError
+     */
+    public static getSettings(token: string): CancelablePromise<SubscriptionSettings> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: 'api/v1/manage/{token}',
+            path: {
+                token: token,
+            },
+            errors: {
+                400: `Invalid token`,
+                404: `Token not found`,
+            },
+        });
+    }
+    /**
+     * Change subscription email
+     * Sends confirmation link to the new address. Weather emails keep going to the current address until the link is opened.
+     * @param token Signed manage token
+     * @param email New email address
+     * @returns any Confirmation email sent to the new address.
+     * @throws ApiError
+     */
+    public static requestEmailChange(token: string, email: string): CancelablePromise<any> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: 'api/v1/manage/{token}/email',
+            path: {
+                token: token,
+            },
+            body: {
+                email: email,
+            },
+            mediaType: 'application/json',
+            errors: {
+                400: `Invalid input, invalid token, disposable or unchanged email address`,
+                404: `Token not found`,
+                409: `New address already has a subscription`,
+                429: `Rate limit exceeded or too many confirmation emails requested`,
+            },
+        });
+    }
+    /**
+     * Pause weather emails
+     * Pauses subscription for given number of days, or until resumed when days are omitted.
+     * @param token Signed manage token
+     * @param days Number of days to pause for
+     * @returns SubscriptionSettings Subscription paused
+     * @throws ApiError
+     */
+    public static pause(token: string, days?: number): CancelablePromise<SubscriptionSettings> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: 'api/v1/manage/{token}/pause',
+            path: {
+                token: token,
+            },
+            body: {
+                days: days,
+            },
+            mediaType: 'application/json',
+            errors: {
+                400: `Invalid input or token`,
+                404: `Token not found`,
+                409: `Emails are suspended after bounces or spam reports`,
+            },
+        });
+    }
+    /**
+     * Resume weather emails
+     * Lifts pause requested by subscriber. Pause caused by bounces or spam reports can only be lifted by changing email.
+     * @param token Signed manage token
+     * @returns SubscriptionSettings Subscription resumed
+     * @throws ApiError
+     */
+    public static resume(token: string): CancelablePromise<SubscriptionSettings> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: 'api/v1/manage/{token}/resume',
+            path: {
+                token: token,
+            },
+            errors: {
+                400: `Invalid token`,
+                404: `Token not found`,
+                409: `Emails are suspended after bounces or spam reports`,
+            },
+        });
+    }
 }
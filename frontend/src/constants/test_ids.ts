@@ -21,6 +21,29 @@ export const CONFIRM_PAGE_IDS = {
 };
 
 export const UNSUBSCRIBE_PAGE_IDS = {
+    confirmButton: 'unsubscribe-confirm-btn',
     confirmation: 'unsubscribe-result-text',
     linkToMainPage: 'unsubscribe-link-to-main',
 };
+
+export const MANAGE_PAGE_IDS = {
+    settings: 'manage-settings',
+    pendingEmail: 'manage-pending-email',
+    emailInput: 'manage-email-input',
+    changeEmailButton: 'manage-change-email-btn',
+    pauseStatus: 'manage-pause-status',
+    snoozeButton: 'manage-snooze-btn',
+    pauseButton: 'manage-pause-btn',
+    resumeButton: 'manage-resume-btn',
+    error: 'manage-error-text',
+    linkToMainPage: 'manage-link-to-main',
+};
+
+export const PRIVACY_PAGE_IDS = {
+    emailInput: 'privacy-email-input',
+    exportButton: 'privacy-export-btn',
+    eraseButton: 'privacy-erase-btn',
+    confirmEraseButton: 'privacy-confirm-erase-btn',
+    result: 'privacy-result-text',
+    linkToMainPage: 'privacy-link-to-main',
+};
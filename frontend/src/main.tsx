@@ -4,8 +4,11 @@ import {createBrowserRouter, RouterProvider} from 'react-router';
 import App from './App';
 import Layout from './layouts/dashboard';
 import DashboardPage from './pages';
+import ManagePage from './pages/ManagePage/ManagePage';
 import ConfirmPage from './pages/ConfirmationPage/ConfirmationPage';
 import NotFound from './pages/NotFound/NotFound';
+import PrivacyPage from './pages/PrivacyPage/PrivacyPage';
+import PrivacyRequestPage from './pages/PrivacyPage/PrivacyRequestPage';
 import UnsubscribePage from './pages/UnsubscribePage/UnsubscribePage';
 
 const router = createBrowserRouter([
@@ -30,6 +33,18 @@ const router = createBrowserRouter([
                 path: '/unsubscribe/:token',
                 element: <UnsubscribePage />,
             },
+            {
+                path: '/manage/:token',
+                element: <ManagePage />,
+            },
+            {
+                path: '/privacy',
+                element: <PrivacyRequestPage />,
+            },
+            {
+                path: '/privacy/:action/:token',
+                element: <PrivacyPage />,
+            },
             {
                 path: '*',
                 Component: NotFound,
@@ -0,0 +1,129 @@
+import {useEffect, useState} from 'react';
+import {Box, Button, CircularProgress, TextField, Typography, Link as MuiLink} from '@mui/material';
+import {useNotifications} from '@toolpad/core';
+import {useParams, useSearchParams, Link} from 'react-router';
+import {SubscriptionService, SubscriptionSettings} from '../../api';
+import {MANAGE_PAGE_IDS} from '../../constants/test_ids';
+
+export default function ManagePage() {
+    const {token} = useParams<{token: string}>();
+    const [searchParams] = useSearchParams();
+    // snooze link from weather emails asks to confirm pause for given days
+    const snoozeDays = Number(searchParams.get('snooze')) || 0;
+    const [settings, setSettings] = useState<SubscriptionSettings>();
+    const [status, setStatus] = useState<'loading' | 'success' | 'error'>('loading');
+    const notifications = useNotifications();
+    const [email, setEmail] = useState('');
+    const [pending, setPending] = useState(false);
+
+    useEffect(() => {
+        if (!token) return;
+
+        SubscriptionService.getSettings(token)
+            .then(data => {
+                setSettings(data);
+                setStatus('success');
+            })
+            .catch(() => setStatus('error'));
+    }, [token]);
+
+    const changeEmail = async () => {
+        if (!token) return;
+        try {
+            setPending(true);
+            await SubscriptionService.requestEmailChange(token, email);
+            setSettings(current => ({...current, pending_email: email}));
+            setEmail('');
+            notifications.show('We sent a confirmation link to the new address.', {severity: 'success', autoHideDuration: 3000});
+        } catch (err: any) {
+            notifications.show(err?.body?.error || 'Request failed', {severity: 'error', autoHideDuration: 3000});
+        } finally {
+            setPending(false);
+        }
+    };
+
+    const updatePause = async (action: () => Promise<SubscriptionSettings>, message: string) => {
+        try {
+            setPending(true);
+            setSettings(await action());
+            notifications.show(message, {severity: 'success', autoHideDuration: 3000});
+        } catch (err: any) {
+            notifications.show(err?.body?.error || 'Request failed', {severity: 'error', autoHideDuration: 3000});
+        } finally {
+            setPending(false);
+        }
+    };
+
+    const pause = (days?: number) => token && updatePause(() => SubscriptionService.pause(token, days), 'Weather emails paused.');
+    const resume = () => token && updatePause(() => SubscriptionService.resume(token), 'Weather emails resumed.');
+
+    return (
+        <Box display="flex" justifyContent="center" alignItems="center" minHeight="80vh" flexDirection="column" gap={2}>
+            {status === 'loading' && <CircularProgress />}
+            {status === 'success' && settings && (
+                <Box data-testid={MANAGE_PAGE_IDS.settings}>
+                    <Typography variant="h5">Your subscription</Typography>
+                    <Typography>City: {settings.city}</Typography>
+                    <Typography>Frequency: {settings.frequency}</Typography>
+                    <Typography>Units: {settings.units}</Typography>
+                    <Typography>Language: {settings.lang}</Typography>
+                    <Typography>Air quality: {settings.include_air_quality ? 'included' : 'not included'}</Typography>
+                    {settings.aqi_alert_threshold && <Typography>AQI alert at: {settings.aqi_alert_threshold}</Typography>}
+                    <Typography data-testid={MANAGE_PAGE_IDS.pauseStatus}>
+                        Emails:{' '}
+                        {!settings.paused
+                            ? 'active'
+                            : settings.resume_at
+                              ? `paused until ${new Date(settings.resume_at).toLocaleDateString()}`
+                              : settings.pause_reason === 'user'
+                                ? 'paused'
+                                : 'suspended because emails to your address bounce or were reported as spam'}
+                    </Typography>
+                    <Box display="flex" gap={2} mt={1}>
+                        {snoozeDays > 0 && !settings.paused && (
+                            <Button variant="contained" disabled={pending} onClick={() => pause(snoozeDays)} data-testid={MANAGE_PAGE_IDS.snoozeButton}>
+                                Snooze for {snoozeDays} days
+                            </Button>
+                        )}
+                        {!settings.paused && (
+                            <Button variant="outlined" disabled={pending} onClick={() => pause()} data-testid={MANAGE_PAGE_IDS.pauseButton}>
+                                Pause
+                            </Button>
+                        )}
+                        {settings.paused && settings.pause_reason === 'user' && (
+                            <Button variant="outlined" disabled={pending} onClick={resume} data-testid={MANAGE_PAGE_IDS.resumeButton}>
+                                Resume
+                            </Button>
+                        )}
+                    </Box>
+                    {settings.pending_email && (
+                        <Typography color="text.secondary" data-testid={MANAGE_PAGE_IDS.pendingEmail}>
+                            Waiting for confirmation of {settings.pending_email}
+                        </Typography>
+                    )}
+                    <Box display="flex" gap={2} mt={2}>
+                        <TextField
+                            label="New email"
+                            type="email"
+                            size="small"
+                            value={email}
+                            onChange={e => setEmail(e.target.value)}
+                            data-testid={MANAGE_PAGE_IDS.emailInput}
+                        />
+                        <Button variant="outlined" disabled={pending || !email} onClick={changeEmail} data-testid={MANAGE_PAGE_IDS.changeEmailButton}>
+                            Change email
+                        </Button>
+                    </Box>
+                </Box>
+            )}
+            {status === 'error' && (
+                <Typography variant="h5" color="error" data-testid={MANAGE_PAGE_IDS.error}>
+                    Link is invalid or expired ❌
+                </Typography>
+            )}
+            <MuiLink component={Link} to="/" underline="hover" data-testid={MANAGE_PAGE_IDS.linkToMainPage}>
+                Back to main page
+            </MuiLink>
+        </Box>
+    );
+}
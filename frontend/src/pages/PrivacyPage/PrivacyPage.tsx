@@ -0,0 +1,76 @@
+import {useEffect, useState} from 'react';
+import {Box, Button, CircularProgress, Typography, Link as MuiLink} from '@mui/material';
+import {useNotifications} from '@toolpad/core';
+import {useParams, Link} from 'react-router';
+import {PrivacyService} from '../../api';
+import {PRIVACY_PAGE_IDS} from '../../constants/test_ids';
+
+const downloadJSON = (data: unknown) => {
+    const url = URL.createObjectURL(new Blob([JSON.stringify(data, null, 2)], {type: 'application/json'}));
+    const link = document.createElement('a');
+    link.href = url;
+    link.download = 'weather-subscription-data.json';
+    link.click();
+    URL.revokeObjectURL(url);
+};
+
+// erasure waits for explicit confirmation, so link prefetching by mail scanners doesn't delete anything
+export default function PrivacyPage() {
+    const {action, token} = useParams<{action: string; token: string}>();
+    const notifications = useNotifications();
+    const [status, setStatus] = useState<'idle' | 'loading' | 'success' | 'error'>(
+        action === 'export' ? 'loading' : action === 'erase' ? 'idle' : 'error',
+    );
+
+    useEffect(() => {
+        if (!token || action !== 'export') return;
+
+        PrivacyService.exportData(token)
+            .then(data => {
+                downloadJSON(data);
+                setStatus('success');
+            })
+            .catch(err => {
+                setStatus('error');
+                notifications.show(`Export failed: ${err.message}`, {severity: 'error', autoHideDuration: 3000});
+            });
+    }, [action, token]);
+
+    const erase = () => {
+        if (!token) return;
+        setStatus('loading');
+        PrivacyService.eraseData(token)
+            .then(() => setStatus('success'))
+            .catch(err => {
+                setStatus('error');
+                notifications.show(`Deletion failed: ${err.message}`, {severity: 'error', autoHideDuration: 3000});
+            });
+    };
+
+    return (
+        <Box display="flex" justifyContent="center" alignItems="center" minHeight="80vh" flexDirection="column" gap={2}>
+            {status === 'loading' && <CircularProgress />}
+            {status === 'idle' && action === 'erase' && (
+                <>
+                    <Typography variant="h5">Delete all your data and subscriptions? This can't be undone.</Typography>
+                    <Button variant="contained" color="error" onClick={erase} data-testid={PRIVACY_PAGE_IDS.confirmEraseButton}>
+                        Delete my data
+                    </Button>
+                </>
+            )}
+            {status === 'success' && (
+                <Typography variant="h5" data-testid={PRIVACY_PAGE_IDS.result}>
+                    {action === 'export' ? 'Your data has been downloaded ✅' : 'Your data has been deleted ✅'}
+                </Typography>
+            )}
+            {status === 'error' && (
+                <Typography variant="h5" color="error" data-testid={PRIVACY_PAGE_IDS.result}>
+                    Link is invalid or expired ❌
+                </Typography>
+            )}
+            <MuiLink component={Link} to="/" underline="hover" data-testid={PRIVACY_PAGE_IDS.linkToMainPage}>
+                Back to main page
+            </MuiLink>
+        </Box>
+    );
+}
@@ -0,0 +1,53 @@
+import {useState} from 'react';
+import {Box, Button, TextField, Typography, Link as MuiLink} from '@mui/material';
+import {useNotifications} from '@toolpad/core';
+import {Link} from 'react-router';
+import {PrivacyService} from '../../api';
+import {PRIVACY_PAGE_IDS} from '../../constants/test_ids';
+
+export default function PrivacyRequestPage() {
+    const notifications = useNotifications();
+    const [email, setEmail] = useState('');
+    const [pending, setPending] = useState(false);
+
+    const submit = async (action: 'export' | 'erase') => {
+        try {
+            setPending(true);
+            await PrivacyService.requestLink(email, action);
+            notifications.show('If this email is registered, we sent a verification link to it.', {
+                severity: 'success',
+                autoHideDuration: 3000,
+            });
+        } catch (err: any) {
+            notifications.show(err?.body?.error || 'Request failed', {severity: 'error', autoHideDuration: 3000});
+        } finally {
+            setPending(false);
+        }
+    };
+
+    return (
+        <Box display="flex" justifyContent="center" alignItems="center" minHeight="80vh" flexDirection="column" gap={2}>
+            <Typography variant="h5">Your data</Typography>
+            <Typography variant="body1">Get a copy of your data or delete it. We will email you a link to confirm.</Typography>
+            <TextField
+                label="Email"
+                type="email"
+                value={email}
+                onChange={e => setEmail(e.target.value)}
+                sx={{minWidth: 300}}
+                data-testid={PRIVACY_PAGE_IDS.emailInput}
+            />
+            <Box display="flex" gap={2}>
+                <Button variant="contained" disabled={pending || !email} onClick={() => submit('export')} data-testid={PRIVACY_PAGE_IDS.exportButton}>
+                    Export my data
+                </Button>
+                <Button variant="outlined" color="error" disabled={pending || !email} onClick={() => submit('erase')} data-testid={PRIVACY_PAGE_IDS.eraseButton}>
+                    Delete my data
+                </Button>
+            </Box>
+            <MuiLink component={Link} to="/" underline="hover" data-testid={PRIVACY_PAGE_IDS.linkToMainPage}>
+                Back to main page
+            </MuiLink>
+        </Box>
+    );
+}
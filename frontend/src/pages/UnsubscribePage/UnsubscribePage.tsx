@@ -1,32 +1,55 @@
 import {useEffect, useState} from 'react';
-import {Box, CircularProgress, Typography, Link as MuiLink} from '@mui/material';
+import {Box, Button, CircularProgress, Typography, Link as MuiLink} from '@mui/material';
 import {useNotifications} from '@toolpad/core';
 import {SubscriptionService} from '../../api';
 import {useParams, Link} from 'react-router';
 import {UNSUBSCRIBE_PAGE_IDS} from '../../constants/test_ids';
 
+// unsubscribe waits for explicit confirmation, so link prefetching by mail scanners doesn't unsubscribe anyone
 export default function UnsubscribePage() {
     const {token} = useParams<{token: string}>();
     const notifications = useNotifications();
-    const [status, setStatus] = useState<'loading' | 'success' | 'error'>('loading');
+    const [city, setCity] = useState<string>();
+    const [status, setStatus] = useState<'loading' | 'idle' | 'success' | 'error'>('loading');
+
+    const fail = (err: Error) => {
+        setStatus('error');
+        notifications.show(`Unsubscribe failed: ${err.message}`, {severity: 'error', autoHideDuration: 3000});
+    };
 
     useEffect(() => {
         if (!token) return;
 
+        SubscriptionService.getUnsubscribeInfo(token)
+            .then(settings => {
+                setCity(settings.city);
+                setStatus('idle');
+            })
+            .catch(fail);
+    }, [token]);
+
+    const unsubscribe = () => {
+        if (!token) return;
+        setStatus('loading');
         SubscriptionService.unsubscribe(token)
             .then(() => {
                 setStatus('success');
                 notifications.show('Succsessfuly unsubscribed!', {severity: 'success', autoHideDuration: 3000});
             })
-            .catch(err => {
-                setStatus('error');
-                notifications.show(`Unsubscribe failed: ${err.message}`, {severity: 'error', autoHideDuration: 3000});
-            });
-    }, [token]);
+            .catch(fail);
+    };
 
     return (
-        <Box display="flex" justifyContent="center" alignItems="center" minHeight="80vh" flexDirection="column">
+        <Box display="flex" justifyContent="center" alignItems="center" minHeight="80vh" flexDirection="column" gap={2}>
             {status === 'loading' && <CircularProgress />}
+            {status === 'idle' && (
+                <>
+                    <Typography variant="h5">Stop weather updates for {city}?</Typography>
+                    <Button variant="contained" onClick={unsubscribe} data-testid={UNSUBSCRIBE_PAGE_IDS.confirmButton}>
+                        Unsubscribe
+                    </Button>
+                </>
+            )}
             {status === 'success' && (
                 <Typography variant="h5" data-testid={UNSUBSCRIBE_PAGE_IDS.confirmation}>
                     Succsessfuly unsubscribed ✅
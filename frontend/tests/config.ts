@@ -4,11 +4,15 @@ import * as path from 'path';
 export class Config {
     public readonly dbUrl: string;
     public readonly baseUrl: string;
+    // first key of TOKEN_SIGNING_KEYS, the one API signs new tokens with
+    public readonly tokenSigningKey: [string, string];
 
     constructor() {
         this.loadEnv();
         this.dbUrl = this.requireEnv('DB_URL');
         this.baseUrl = this.requireEnv('BASE_URL');
+        const [keyId, ...secret] = this.requireEnv('TOKEN_SIGNING_KEYS').split(',')[0].trim().split(':');
+        this.tokenSigningKey = [keyId, secret.join(':')];
     }
 
     private requireEnv(key: string): string {
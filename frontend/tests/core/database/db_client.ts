@@ -54,13 +54,12 @@ export class DatabaseClient {
         if (!subs.length) return;
         const values: unknown[] = [];
         const placeholders = subs.map((s, i) => {
-            const idx = i * 10;
+            const idx = i * 9;
             values.push(
                 s.city,
                 s.frequency,
                 s.userId,
                 s.isConfirmed,
-                s.confirmToken,
                 s.tokenExpires,
                 s.confirmedAt ?? null,
                 s.createdAt,
@@ -68,12 +67,12 @@ export class DatabaseClient {
                 s.deletedAt ?? null,
             );
             return `($${idx + 1}, $${idx + 2}, $${idx + 3}, $${idx + 4}, $${idx + 5},
-                   $${idx + 6}, $${idx + 7}, $${idx + 8}, $${idx + 9}, $${idx + 10})`;
+                   $${idx + 6}, $${idx + 7}, $${idx + 8}, $${idx + 9})`;
         });
 
         const {rows} = await this.db.query(
             `INSERT INTO subscriptions
-             (city, frequency, user_id, is_confirmed, confirm_token,
+             (city, frequency, user_id, is_confirmed,
               token_expires, confirmed_at, created_at, updated_at, deleted_at)
            VALUES ${placeholders.join(',')}
            RETURNING id`,
@@ -1,4 +1,4 @@
-import {generateUUID} from '../utils/generate_utils';
+import {signToken} from '../utils/token_utils';
 
 const minute = 60 * 1000;
 
@@ -10,7 +10,6 @@ export type SubscriptionOptions = {
     frequency?: Frequency;
     userId?: number;
     isConfirmed?: boolean;
-    confirmToken?: string;
     tokenExpires?: Date;
     confirmedAt?: Date;
     createdAt?: Date;
@@ -24,7 +23,6 @@ export class Subscription {
     public frequency: Frequency;
     public userId?: number;
     public isConfirmed: boolean;
-    public confirmToken: string;
     public tokenExpires: Date;
     public confirmedAt?: Date;
     public createdAt: Date;
@@ -37,10 +35,18 @@ export class Subscription {
         this.frequency = options.frequency || 'daily';
         this.userId = options.userId;
         this.isConfirmed = options.isConfirmed || false;
-        this.confirmToken = options.confirmToken || generateUUID();
         this.tokenExpires = options.tokenExpires || new Date(Date.now() + 15 * minute);
         this.createdAt = options.createdAt || new Date(Date.now());
         this.updatedAt = options.updatedAt || new Date(Date.now());
         this.deletedAt = options.deletedAt;
     }
+
+    // tokens are bound to id, subscription not stored yet gets tokens of non-existent one
+    public get confirmToken(): string {
+        return signToken('confirm', this.id ?? 0, this.tokenExpires);
+    }
+
+    public get unsubscribeToken(): string {
+        return signToken('unsubscribe', this.id ?? 0, new Date(Date.now() + 60 * minute));
+    }
 }
@@ -0,0 +1,13 @@
+import {createHmac} from 'crypto';
+import {CONFIG} from '../../config';
+
+export type TokenPurpose = 'confirm' | 'unsubscribe' | 'manage';
+
+// signToken mirrors internal/common/tokens, so tests can open links from emails without reading the mailbox
+export function signToken(purpose: TokenPurpose, subscriptionId: number, expiresAt: Date): string {
+    const [keyId, secret] = CONFIG.tokenSigningKey;
+    const claims = JSON.stringify({p: purpose, sid: subscriptionId, exp: Math.floor(expiresAt.getTime() / 1000)});
+    const signed = `${keyId}.${Buffer.from(claims).toString('base64url')}`;
+    const signature = createHmac('sha256', secret).update(signed).digest('base64url');
+    return `${signed}.${signature}`;
+}
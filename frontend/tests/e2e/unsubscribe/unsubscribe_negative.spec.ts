@@ -39,17 +39,17 @@ test.describe('Unsubscribe from updates, negative flow', () => {
     const testData = [
         {
             description: 'Subscription not confirmed',
-            token: () => notConfirmedSubscription.confirmToken,
+            token: () => notConfirmedSubscription.unsubscribeToken,
             expectedText: 'Unsubscribe failed: Token not found',
         },
         {
             description: 'Subscription deleted',
-            token: () => deletedSubscription.confirmToken,
+            token: () => deletedSubscription.unsubscribeToken,
             expectedText: 'Unsubscribe failed: Token not found',
         },
         {
             description: 'Token does not exist',
-            token: () => notExistentSubscription.confirmToken,
+            token: () => notExistentSubscription.unsubscribeToken,
             expectedText: 'Unsubscribe failed: Token not found',
         },
     ];
@@ -32,7 +32,9 @@ test.describe('Unsubscribe from updates, positive flow', () => {
     });
 
     test(`Should unsubscribe with valid confirmed token`, async ({page}) => {
-        await page.goto(`/unsubscribe/${subscription.confirmToken}`);
+        await page.goto(`/unsubscribe/${subscription.unsubscribeToken}`);
+        await expect(page.getByText('Stop weather updates for Kyiv?')).toBeVisible();
+        await unsubscribePage.confirmButton.click();
 
         await expect(unsubscribePage.confirmationText).toBeVisible();
         await expect(unsubscribePage.linkToMainPage).toBeVisible();
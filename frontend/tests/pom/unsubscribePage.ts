@@ -2,6 +2,10 @@ import {BasePage} from './basePage';
 import {UNSUBSCRIBE_PAGE_IDS} from '../../src/constants/test_ids';
 
 export class UnsubscribePage extends BasePage {
+    public get confirmButton() {
+        return this.page.getByTestId(UNSUBSCRIBE_PAGE_IDS.confirmButton);
+    }
+
     public get confirmationText() {
         return this.page.getByTestId(UNSUBSCRIBE_PAGE_IDS.confirmation);
     }
@@ -0,0 +1,27 @@
+package bootstrap
+
+import (
+	"weatherApi/internal/config"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/metrics"
+	"weatherApi/internal/repository/leader"
+	"weatherApi/internal/scheduler"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// Weather emails are sent by holder of NotificationsLeaseName, api service with embedded scheduler and
+// scheduler service compete for the same lease, so emails are not sent twice while both are deployed.
+// Api service which leaves weather emails to scheduler service elects leader of its own jobs separately
+const (
+	NotificationsLeaseName = "scheduler"
+	MaintenanceLeaseName   = "api_maintenance"
+)
+
+// NewLeaderElector builds elector of lease named leaseName and registers its metrics
+func NewLeaderElector(log *logger.Logger, cfg *config.SchedulerConfig, rdb *redis.Client, leaseName string) *scheduler.LeaderElector {
+	schedulerMetrics := metrics.NewSchedulerMetrics()
+	schedulerMetrics.Register(prometheus.DefaultRegisterer)
+	return scheduler.NewLeaderElector(log, leader.NewLeaseRepository(rdb), leaseName, cfg.SchedulerInstanceID, cfg.SchedulerLeaseTTL, schedulerMetrics)
+}
@@ -0,0 +1,59 @@
+package bootstrap
+
+import (
+	"weatherApi/internal/config"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+)
+
+// newProviders builds weather and air quality provider chains, in fake mode upstream APIs are not called at all
+func newProviders(
+	log *logger.Logger,
+	cfg *config.WeatherConfig,
+	quotaGuard provider.QuotaGuardInterface,
+	httpClient *provider.HTTPClient,
+) ([]provider.WeatherProviderInterface, []provider.AirQualityProviderInterface) {
+	if cfg.ProviderMode == config.ProviderModeFake {
+		scenarios := provider.FakeScenarios{}
+		if cfg.FakeScenariosPath != "" {
+			loaded, err := provider.LoadFakeScenarios(cfg.FakeScenariosPath)
+			if err != nil {
+				log.Base().Fatal().Err(err).Msg("Failed to load fake provider scenarios")
+			}
+			scenarios = loaded
+		}
+		log.Base().Warn().Msgf("Fake providers mode, %d scenarios loaded", len(scenarios))
+		return []provider.WeatherProviderInterface{provider.NewFakeWeatherProvider(log, scenarios)},
+			[]provider.AirQualityProviderInterface{provider.NewFakeAirQualityProvider(log, scenarios)}
+	}
+
+	weatherProviders := []provider.WeatherProviderInterface{
+		provider.NewQuotaLimitedProvider(
+			log,
+			provider.NewOpenWeatherApiProvider(log, cfg.OpenWeatherAPIkey, cfg.OpenWeatherAPIEndpoint).WithHTTPClient(httpClient),
+			quotaGuard,
+			provider.OpenWeatherMapName,
+		),
+		provider.NewQuotaLimitedProvider(
+			log,
+			provider.NewWeatherApiProvider(log, cfg.WeatherApiAPIkey, cfg.WeatherApiAPIEndpoint).WithHTTPClient(httpClient),
+			quotaGuard,
+			provider.WeatherApiName,
+		),
+	}
+	airQualityProviders := []provider.AirQualityProviderInterface{
+		provider.NewQuotaLimitedAirQualityProvider(
+			log,
+			provider.NewOpenWeatherMapAirQualityProvider(log, cfg.OpenWeatherAPIkey, cfg.OpenWeatherAirEndpoint, cfg.OpenWeatherGeoEndpoint).WithHTTPClient(httpClient),
+			quotaGuard,
+			provider.OpenWeatherMapName,
+		),
+		provider.NewQuotaLimitedAirQualityProvider(
+			log,
+			provider.NewWeatherApiAirQualityProvider(log, cfg.WeatherApiAPIkey, cfg.WeatherApiAPIEndpoint).WithHTTPClient(httpClient),
+			quotaGuard,
+			provider.WeatherApiName,
+		),
+	}
+	return weatherProviders, airQualityProviders
+}
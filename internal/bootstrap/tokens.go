@@ -0,0 +1,25 @@
+package bootstrap
+
+import (
+	"time"
+	"weatherApi/internal/common/tokens"
+	"weatherApi/internal/config"
+	"weatherApi/internal/logger"
+)
+
+// NewTokenSigner builds signer for subscription links, confirm tokens expire with subscription's TokenExpires
+// so only long-lived purposes need lifetime
+func NewTokenSigner(log *logger.Logger, cfg *config.LinkTokenConfig) *tokens.Signer {
+	keyID, keys, err := tokens.ParseKeys(cfg.TokenSigningKeys)
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Invalid TOKEN_SIGNING_KEYS")
+	}
+	signer, err := tokens.NewSigner(keyID, keys, map[tokens.Purpose]time.Duration{
+		tokens.PurposeUnsubscribe: cfg.UnsubscribeTokenTTL,
+		tokens.PurposeManage:      cfg.ManageTokenTTL,
+	})
+	if err != nil {
+		log.Base().Fatal().Err(err).Msg("Failed to init token signer")
+	}
+	return signer
+}
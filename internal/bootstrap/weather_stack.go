@@ -0,0 +1,95 @@
+package bootstrap
+
+import (
+	"weatherApi/internal/config"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/metrics"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/airquality"
+	"weatherApi/internal/repository/cache"
+	"weatherApi/internal/repository/observation"
+	"weatherApi/internal/repository/quota"
+	"weatherApi/internal/repository/weather"
+	serviceAirQuality "weatherApi/internal/service/airquality"
+	serviceQuota "weatherApi/internal/service/quota"
+	serviceWeather "weatherApi/internal/service/weather"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// WeatherStack is weather and air quality services sharing provider quotas and Redis cache,
+// used by api service and scheduler service
+type WeatherStack struct {
+	Weather    *serviceWeather.Service
+	AirQuality *serviceAirQuality.Service
+	Quota      *serviceQuota.Service
+}
+
+func NewRedisClient(cfg *config.WeatherConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisURL,
+		Password: cfg.RedisPassword,
+		DB:       0,
+	})
+}
+
+// NewWeatherStack builds weather services and registers their metrics, observations are recorded into observationRepo
+func NewWeatherStack(log *logger.Logger, cfg *config.WeatherConfig, rdb *redis.Client, observationRepo *observation.ObservationRepository) *WeatherStack {
+	cacheMetrics := metrics.NewCacheMetrics()
+	cacheMetrics.Register(prometheus.DefaultRegisterer)
+	cacheRepo := weather.NewWeatherRepository(&weather.RepositoryOptions{
+		Client:       rdb,
+		CacheTTL:     cfg.CacheTTL,
+		LockTTL:      cfg.LockTTL,
+		LockRetryDur: cfg.LockRetryDur,
+		LockMaxWait:  cfg.LockMaxWait,
+		Metrics:      cacheMetrics,
+	})
+
+	// air quality calls are made with the same keys, so they are counted against weather providers accounts
+	quotaMetrics := metrics.NewQuotaMetrics()
+	quotaMetrics.Register(prometheus.DefaultRegisterer)
+	quotaService := serviceQuota.NewQuotaService(log, quota.NewQuotaRepository(rdb), map[string]int64{
+		provider.OpenWeatherMapName: int64(cfg.OpenWeatherDailyQuota),
+		provider.WeatherApiName:     int64(cfg.WeatherApiDailyQuota),
+	}, quotaMetrics)
+	providerMetrics := metrics.NewProviderMetrics()
+	providerMetrics.Register(prometheus.DefaultRegisterer)
+	providerHTTPClient := provider.NewHTTPClient(provider.HTTPClientOptions{
+		Timeout:             cfg.ProviderTimeout,
+		MaxRetries:          cfg.ProviderMaxRetries,
+		RetryBaseDelay:      cfg.ProviderRetryBaseDelay,
+		MaxBodyBytes:        int64(cfg.ProviderMaxBodyBytes),
+		MaxIdleConnsPerHost: cfg.ProviderMaxIdleConns,
+	}, providerMetrics)
+	weatherProviders, airQualityProviders := newProviders(log, cfg, quotaService, providerHTTPClient)
+
+	weatherService := serviceWeather.NewWeatherService(log, cacheRepo, weatherProviders...).
+		WithObservationRepo(observationRepo)
+	if cfg.ConsensusEnabled {
+		consensusMetrics := metrics.NewConsensusMetrics()
+		consensusMetrics.Register(prometheus.DefaultRegisterer)
+		weatherService.WithConsensus(serviceWeather.ConsensusOptions{
+			TemperatureTolerance: cfg.ConsensusTemperatureTolerance,
+			HumidityTolerance:    cfg.ConsensusHumidityTolerance,
+			PressureTolerance:    cfg.ConsensusPressureTolerance,
+			WindSpeedTolerance:   cfg.ConsensusWindSpeedTolerance,
+		}, consensusMetrics)
+	}
+	airQualityCacheRepo := airquality.NewAirQualityRepository(&cache.RepositoryOptions{
+		Client:       rdb,
+		CacheTTL:     cfg.AirQualityTTL,
+		LockTTL:      cfg.LockTTL,
+		LockRetryDur: cfg.LockRetryDur,
+		LockMaxWait:  cfg.LockMaxWait,
+		Metrics:      cacheMetrics,
+	})
+	airQualityService := serviceAirQuality.NewAirQualityService(log, airQualityCacheRepo, airQualityProviders...)
+
+	return &WeatherStack{
+		Weather:    weatherService,
+		AirQuality: airQualityService,
+		Quota:      quotaService,
+	}
+}
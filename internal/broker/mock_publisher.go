@@ -1,6 +1,9 @@
 package broker
 
+import "sync"
+
 type MockRabbitMQPublisher struct {
+	mu    sync.Mutex
 	Calls []PublishCall
 }
 
@@ -15,6 +18,8 @@ func NewMockRabbitMQPublisher() *MockRabbitMQPublisher {
 }
 
 func (m *MockRabbitMQPublisher) Publish(topic Topic, payload []byte, opts ...PublishOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Calls = append(m.Calls, PublishCall{
 		Topic:   topic,
 		Payload: payload,
@@ -7,6 +7,8 @@ type Topic string
 const (
 	SubscriptionConfirmationTasks Topic = "task.send_confirmation_token"
 	SendSubscriptionWeatherData   Topic = "task.send_sub_data"
+	PrivacyLinkTasks              Topic = "task.send_privacy_link"
+	SendDigestWeatherData         Topic = "task.send_digest_data"
 )
 
 func (t Topic) DLQ() Topic {
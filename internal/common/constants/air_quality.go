@@ -0,0 +1,13 @@
+package constants
+
+// AirQualityCategory is US EPA AQI level of concern
+type AirQualityCategory string
+
+const (
+	AirQualityGood                        AirQualityCategory = "good"
+	AirQualityModerate                    AirQualityCategory = "moderate"
+	AirQualityUnhealthyForSensitiveGroups AirQualityCategory = "unhealthy_for_sensitive_groups"
+	AirQualityUnhealthy                   AirQualityCategory = "unhealthy"
+	AirQualityVeryUnhealthy               AirQualityCategory = "very_unhealthy"
+	AirQualityHazardous                   AirQualityCategory = "hazardous"
+)
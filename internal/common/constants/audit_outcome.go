@@ -0,0 +1,9 @@
+package constants
+
+// AuditOutcome tells whether audited admin action succeeded
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeError   AuditOutcome = "error"
+)
@@ -0,0 +1,9 @@
+package constants
+
+// DeliveryKind is type of email recorded in delivery log
+type DeliveryKind string
+
+const (
+	DeliveryWeather DeliveryKind = "weather"
+	DeliveryDigest  DeliveryKind = "digest"
+)
@@ -0,0 +1,20 @@
+package constants
+
+// EmailEventType is delivery feedback reported by mail provider or bounce email
+type EmailEventType string
+
+const (
+	EmailHardBounce EmailEventType = "hard_bounce"
+	EmailSoftBounce EmailEventType = "soft_bounce"
+	EmailComplaint  EmailEventType = "complaint"
+)
+
+// PauseReason tells why subscription emails are not sent
+type PauseReason string
+
+const (
+	PauseBounced   PauseReason = "bounced"
+	PauseComplaint PauseReason = "complaint"
+	// PauseUser is pause requested by subscriber, only this one can be lifted by subscriber
+	PauseUser PauseReason = "user"
+)
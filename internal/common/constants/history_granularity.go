@@ -0,0 +1,12 @@
+package constants
+
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+func (g Granularity) IsValid() bool {
+	return g == GranularityHour || g == GranularityDay
+}
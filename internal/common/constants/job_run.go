@@ -0,0 +1,10 @@
+package constants
+
+// JobRunStatus is state of a scheduled job run
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
@@ -0,0 +1,13 @@
+package constants
+
+// PrivacyAction is data subject request verified by emailed link
+type PrivacyAction string
+
+const (
+	PrivacyExport PrivacyAction = "export"
+	PrivacyErase  PrivacyAction = "erase"
+)
+
+func (a PrivacyAction) IsValid() bool {
+	return a == PrivacyExport || a == PrivacyErase
+}
@@ -0,0 +1,12 @@
+package constants
+
+// SubscriptionStatus is derived from confirmation and deletion state, it is not stored
+type SubscriptionStatus string
+
+const (
+	SubscriptionPending      SubscriptionStatus = "pending"
+	SubscriptionExpired      SubscriptionStatus = "expired"
+	SubscriptionActive       SubscriptionStatus = "active"
+	SubscriptionPaused       SubscriptionStatus = "paused"
+	SubscriptionUnsubscribed SubscriptionStatus = "unsubscribed"
+)
@@ -0,0 +1,30 @@
+package constants
+
+import "regexp"
+
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+	UnitsStandard Units = "standard"
+)
+
+// DefaultLang is language providers respond with when no lang is requested
+const DefaultLang = "en"
+
+var langPattern = regexp.MustCompile(`^[a-z]{2}(_[a-z]{2,4})?$`)
+
+func (u Units) IsValid() bool {
+	switch u {
+	case UnitsMetric, UnitsImperial, UnitsStandard:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidLang checks lang has provider compatible format, e.g. "uk", "en" or "zh_tw"
+func IsValidLang(lang string) bool {
+	return langPattern.MatchString(lang)
+}
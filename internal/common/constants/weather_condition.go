@@ -0,0 +1,17 @@
+package constants
+
+// WeatherCondition is provider-agnostic weather condition code
+type WeatherCondition string
+
+const (
+	ConditionClear        WeatherCondition = "clear"
+	ConditionPartlyCloudy WeatherCondition = "partly_cloudy"
+	ConditionCloudy       WeatherCondition = "cloudy"
+	ConditionFog          WeatherCondition = "fog"
+	ConditionDrizzle      WeatherCondition = "drizzle"
+	ConditionRain         WeatherCondition = "rain"
+	ConditionSleet        WeatherCondition = "sleet"
+	ConditionSnow         WeatherCondition = "snow"
+	ConditionThunderstorm WeatherCondition = "thunderstorm"
+	ConditionUnknown      WeatherCondition = "unknown"
+)
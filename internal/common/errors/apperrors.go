@@ -13,6 +13,10 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 func New(code int, message string, err error) *AppError {
 	return &AppError{
 		Code:    code,
@@ -0,0 +1,138 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Purpose scopes token to a single action, token signed for one purpose is rejected for any other
+type Purpose string
+
+const (
+	PurposeConfirm     Purpose = "confirm"
+	PurposeUnsubscribe Purpose = "unsubscribe"
+	PurposeManage      Purpose = "manage"
+	PurposeEmailChange Purpose = "email_change"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+type Claims struct {
+	Purpose        Purpose `json:"p"`
+	SubscriptionID uint    `json:"sid"`
+	// Version is token version of subscription at issue time, it's bumped when subscription changes owner
+	// so links sent to previous owner stop working. Tokens issued before versioning have zero version
+	Version   uint  `json:"v,omitempty"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Signer issues and verifies subscription tokens without storing them.
+// Token is "<key id>.<base64 claims>.<base64 HMAC-SHA256 of key id and claims>", new tokens are signed
+// with current key, any known key is accepted on verification so keys can be rotated without breaking sent links
+type Signer struct {
+	keyID string
+	keys  map[string][]byte
+	ttls  map[Purpose]time.Duration
+	now   func() time.Time
+}
+
+func NewSigner(keyID string, keys map[string][]byte, ttls map[Purpose]time.Duration) (*Signer, error) {
+	if len(keys[keyID]) == 0 {
+		return nil, fmt.Errorf("signing key %q is not configured", keyID)
+	}
+	return &Signer{keyID: keyID, keys: keys, ttls: ttls, now: time.Now}, nil
+}
+
+// ParseKeys parses "kid:secret,kid:secret" list, the first key is used for signing
+func ParseKeys(spec string) (string, map[string][]byte, error) {
+	var current string
+	keys := make(map[string][]byte)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, found := strings.Cut(pair, ":")
+		if !found || kid == "" || strings.Contains(kid, ".") || secret == "" {
+			return "", nil, fmt.Errorf("invalid signing key %q, expected kid:secret", kid)
+		}
+		if _, exists := keys[kid]; exists {
+			return "", nil, fmt.Errorf("duplicate signing key %q", kid)
+		}
+		keys[kid] = []byte(secret)
+		if current == "" {
+			current = kid
+		}
+	}
+	if current == "" {
+		return "", nil, errors.New("no signing keys configured")
+	}
+	return current, keys, nil
+}
+
+// Issue signs token of subscription version valid for configured lifetime of purpose
+func (s *Signer) Issue(purpose Purpose, subscriptionID, version uint) (string, error) {
+	ttl, ok := s.ttls[purpose]
+	if !ok {
+		return "", fmt.Errorf("lifetime of %s tokens is not configured", purpose)
+	}
+	return s.Sign(purpose, subscriptionID, version, s.now().Add(ttl))
+}
+
+// Sign signs token of subscription version valid until expiresAt, second precision
+func (s *Signer) Sign(purpose Purpose, subscriptionID, version uint, expiresAt time.Time) (string, error) {
+	payload, err := json.Marshal(Claims{
+		Purpose:        purpose,
+		SubscriptionID: subscriptionID,
+		Version:        version,
+		ExpiresAt:      expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := s.keyID + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac(s.keys[s.keyID], signed)), nil
+}
+
+// Verify checks signature, purpose and expiry of token
+func (s *Signer) Verify(token string, purpose Purpose) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	key, ok := s.keys[parts[0]]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac(key, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
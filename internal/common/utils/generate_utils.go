@@ -8,8 +8,12 @@ import (
 
 func RandomOpenweatherMapAPIResponse() dto.OpenweatherMapAPIResponse {
 	// #nosec G404 -- not used for cryptographic purposes
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return SeededOpenweatherMapAPIResponse(rand.New(rand.NewSource(time.Now().UnixNano())), time.Now())
+}
 
+// SeededOpenweatherMapAPIResponse generates plausible OpenWeatherMap response, output depends only on
+// random source state and the day of now, so the same seed always produces the same reading
+func SeededOpenweatherMapAPIResponse(r *rand.Rand, now time.Time) dto.OpenweatherMapAPIResponse {
 	weatherConditions := []struct {
 		ID          int
 		Main        string
@@ -35,6 +39,12 @@ func RandomOpenweatherMapAPIResponse() dto.OpenweatherMapAPIResponse {
 	resp.Main.Temperature = temp
 	resp.Main.FeelsLike = feelsLike
 	resp.Main.Humidity = humidity
+	resp.Main.Pressure = float64(980 + r.Intn(60))
+	resp.Wind.Speed = r.Float64() * 15
+	resp.Wind.Deg = r.Intn(360)
+	resp.Visibility = 1000 + r.Intn(9000)
+	resp.Sys.Sunrise = now.Truncate(24 * time.Hour).Add(5 * time.Hour).Unix()
+	resp.Sys.Sunset = now.Truncate(24 * time.Hour).Add(19 * time.Hour).Unix()
 
 	resp.Weather = []struct {
 		ID          int    `json:"id"`
@@ -61,7 +71,17 @@ func RandomWeatherAPIResponse() dto.WeatherAPIResponse {
 	countries := []string{"UA", "UK", "DE", "JP", "US"}
 	regions := []string{"Region1", "Region2", "Region3", "Region4"}
 
-	descriptions := []string{"Sunny", "Cloudy", "Rainy", "Snowy", "Windy", "Foggy"}
+	conditions := []struct {
+		Code int
+		Text string
+	}{
+		{1000, "Sunny"},
+		{1006, "Cloudy"},
+		{1183, "Light rain"},
+		{1213, "Light snow"},
+		{1003, "Partly cloudy"},
+		{1135, "Fog"},
+	}
 
 	resp := dto.WeatherAPIResponse{}
 
@@ -69,9 +89,19 @@ func RandomWeatherAPIResponse() dto.WeatherAPIResponse {
 	resp.Location.Region = regions[r.Intn(len(regions))]
 	resp.Location.Country = countries[r.Intn(len(countries))]
 
+	condition := conditions[r.Intn(len(conditions))]
+
 	resp.Current.Temperature = r.Float64()*40 - 10
+	resp.Current.FeelsLike = resp.Current.Temperature + (r.Float64()*4 - 2)
 	resp.Current.Humidity = r.Intn(100)
-	resp.Current.Condition.Text = descriptions[r.Intn(len(descriptions))]
+	resp.Current.PressureMb = float64(980 + r.Intn(60))
+	resp.Current.WindKph = r.Float64() * 50
+	resp.Current.WindDegree = r.Intn(360)
+	resp.Current.VisKm = float64(1 + r.Intn(10))
+	resp.Current.UV = float64(r.Intn(11))
+	resp.Current.Condition.Text = condition.Text
+	resp.Current.Condition.Code = condition.Code
+	resp.Current.Condition.Icon = "//cdn.weatherapi.com/weather/64x64/day/116.png"
 
 	return resp
 }
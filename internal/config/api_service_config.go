@@ -9,8 +9,21 @@ import (
 	"github.com/joho/godotenv"
 )
 
+const (
+	ProviderModeLive = "live"
+	ProviderModeFake = "fake"
+
+	CaptchaModeOff        = "off"
+	CaptchaModeSiteVerify = "siteverify"
+	CaptchaModeLocal      = "local"
+)
+
 type ApiServiceConfig struct {
 	Config
+	WeatherConfig
+	LinkTokenConfig
+	SchedulerConfig
+
 	Host string
 	Port int
 
@@ -19,20 +32,41 @@ type ApiServiceConfig struct {
 	BrokerURL        string
 	BrokerMaxRetries int
 
-	OpenWeatherAPIEndpoint string
-	OpenWeatherAPIkey      string
-	WeatherApiAPIEndpoint  string
-	WeatherApiAPIkey       string
-	TokenLifetimeMinutes   int
+	TokenLifetimeMinutes int
+	AdminToken           string
+	GeoIPDatabasePath    string
 
-	RootDir string
+	APIKeysRequired       bool
+	RateLimitIPPerMinute  int
+	RateLimitKeyPerMinute int
+	TrustedProxies        string
+
+	CaptchaMode                string
+	CaptchaVerifyURL           string
+	CaptchaSecret              string
+	DisposableEmailDomains     string
+	SubscribeEmailSendsPerHour int
+	SubscribeIPSendsPerHour    int
+	ConfirmationResendCooldown time.Duration
+
+	PrivacyTokenTTL              time.Duration
+	PrivacyRequestsPerHour       int
+	UnconfirmedUserRetentionDays int
 
-	RedisURL      string
-	RedisPassword string
-	CacheTTL      time.Duration
-	LockTTL       time.Duration
-	LockRetryDur  time.Duration
-	LockMaxWait   time.Duration
+	// MaintenanceInterval is how often cleanup job runs, zero disables it
+	MaintenanceInterval        time.Duration
+	ExpiredSubscriptionGrace   time.Duration
+	ConfirmationReminderBefore time.Duration
+	MaintenanceBatchSize       int
+
+	EmailWebhookSecret       string
+	HardBouncePauseThreshold int
+
+	// SchedulerEmbedded runs weather email jobs inside api service, disable it when scheduler service is deployed.
+	// While enabled, api service competes with scheduler service for the same lease
+	SchedulerEmbedded bool
+
+	RootDir string
 }
 
 func NewApiServiceConfig(log *zerolog.Logger) *ApiServiceConfig {
@@ -41,24 +75,50 @@ func NewApiServiceConfig(log *zerolog.Logger) *ApiServiceConfig {
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to load .env file!")
 	}
+	// local mode accepts CAPTCHA_SECRET itself as solved token, so flows can be tested without captcha service
+	captchaMode := getWithDefault[string](log, "CAPTCHA_MODE", CaptchaModeOff)
+	switch captchaMode {
+	case CaptchaModeOff:
+	case CaptchaModeSiteVerify, CaptchaModeLocal:
+		mustGet[string](log, "CAPTCHA_SECRET")
+	default:
+		log.Fatal().Msgf("Invalid CAPTCHA_MODE %q, expected %s, %s or %s", captchaMode, CaptchaModeOff, CaptchaModeSiteVerify, CaptchaModeLocal)
+	}
+
 	return &ApiServiceConfig{
-		Host:                   mustGet[string](log, "HOST"),
-		Port:                   mustGet[int](log, "PORT"),
-		AppURL:                 mustGet[string](log, "APP_URL"),
-		DatabaseURL:            mustGet[string](log, "DB_URL"),
-		BrokerURL:              mustGet[string](log, "BROKER_URL"),
-		BrokerMaxRetries:       getWithDefault[int](log, "RMQ_MAX_RETRIES", 3),
-		OpenWeatherAPIEndpoint: mustGet[string](log, "OPENWEATHER_API_ENDPOINT"),
-		OpenWeatherAPIkey:      mustGet[string](log, "OPENWEATHER_API_KEY"),
-		WeatherApiAPIEndpoint:  mustGet[string](log, "WEATHER_API_API_ENDPOINT"),
-		WeatherApiAPIkey:       mustGet[string](log, "WEATHER_API_API_KEY"),
-		TokenLifetimeMinutes:   getWithDefault[int](log, "TOKEN_LIFETIME_MINUTES", 15),
-		RootDir:                rootDir,
-		RedisURL:               mustGet[string](log, "REDIS_URL"),
-		RedisPassword:          mustGet[string](log, "REDIS_PWD"),
-		CacheTTL:               getWithDefault[time.Duration](log, "CACHE_TTL", 5*time.Minute),
-		LockTTL:                getWithDefault[time.Duration](log, "LOCK_TTL", 3*time.Second),
-		LockRetryDur:           getWithDefault[time.Duration](log, "LOCK_RETRY_DUR", 100*time.Millisecond),
-		LockMaxWait:            getWithDefault[time.Duration](log, "LOCK_MAX_WAIT", 3*time.Second),
+		WeatherConfig:                newWeatherConfig(log),
+		LinkTokenConfig:              newLinkTokenConfig(log),
+		SchedulerConfig:              newSchedulerConfig(log),
+		Host:                         mustGet[string](log, "HOST"),
+		Port:                         mustGet[int](log, "PORT"),
+		AppURL:                       mustGet[string](log, "APP_URL"),
+		DatabaseURL:                  mustGet[string](log, "DB_URL"),
+		BrokerURL:                    mustGet[string](log, "BROKER_URL"),
+		BrokerMaxRetries:             getWithDefault[int](log, "RMQ_MAX_RETRIES", 3),
+		TokenLifetimeMinutes:         getWithDefault[int](log, "TOKEN_LIFETIME_MINUTES", 15),
+		AdminToken:                   getWithDefault[string](log, "ADMIN_TOKEN", ""),
+		GeoIPDatabasePath:            getWithDefault[string](log, "GEOIP_DB_PATH", ""),
+		APIKeysRequired:              getWithDefault[bool](log, "API_KEYS_REQUIRED", false),
+		RateLimitIPPerMinute:         getWithDefault[int](log, "RATE_LIMIT_IP_PER_MINUTE", 60),
+		RateLimitKeyPerMinute:        getWithDefault[int](log, "RATE_LIMIT_KEY_PER_MINUTE", 600),
+		TrustedProxies:               getWithDefault[string](log, "TRUSTED_PROXIES", ""),
+		CaptchaMode:                  captchaMode,
+		CaptchaVerifyURL:             getWithDefault[string](log, "CAPTCHA_VERIFY_URL", "https://challenges.cloudflare.com/turnstile/v0/siteverify"),
+		CaptchaSecret:                getWithDefault[string](log, "CAPTCHA_SECRET", ""),
+		DisposableEmailDomains:       getWithDefault[string](log, "DISPOSABLE_EMAIL_DOMAINS", ""),
+		SubscribeEmailSendsPerHour:   getWithDefault[int](log, "SUBSCRIBE_EMAIL_SENDS_PER_HOUR", 3),
+		SubscribeIPSendsPerHour:      getWithDefault[int](log, "SUBSCRIBE_IP_SENDS_PER_HOUR", 10),
+		ConfirmationResendCooldown:   getWithDefault[time.Duration](log, "CONFIRMATION_RESEND_COOLDOWN", 10*time.Minute),
+		PrivacyTokenTTL:              getWithDefault[time.Duration](log, "PRIVACY_TOKEN_TTL", time.Hour),
+		PrivacyRequestsPerHour:       getWithDefault[int](log, "PRIVACY_REQUESTS_PER_HOUR", 3),
+		UnconfirmedUserRetentionDays: getWithDefault[int](log, "UNCONFIRMED_USER_RETENTION_DAYS", 30),
+		MaintenanceInterval:          getWithDefault[time.Duration](log, "MAINTENANCE_INTERVAL", 15*time.Minute),
+		ExpiredSubscriptionGrace:     getWithDefault[time.Duration](log, "EXPIRED_SUBSCRIPTION_GRACE", 24*time.Hour),
+		ConfirmationReminderBefore:   getWithDefault[time.Duration](log, "CONFIRMATION_REMINDER_BEFORE", 0),
+		MaintenanceBatchSize:         getWithDefault[int](log, "MAINTENANCE_BATCH_SIZE", 500),
+		EmailWebhookSecret:           getWithDefault[string](log, "EMAIL_WEBHOOK_SECRET", ""),
+		HardBouncePauseThreshold:     getWithDefault[int](log, "HARD_BOUNCE_PAUSE_THRESHOLD", 3),
+		SchedulerEmbedded:            getWithDefault[bool](log, "SCHEDULER_EMBEDDED", true),
+		RootDir:                      rootDir,
 	}
 }
@@ -40,6 +40,18 @@ func castEnvValue[T any](log *zerolog.Logger, val string, key string) T {
 			log.Fatal().Err(err).Msgf("Invalid int value for %s", key)
 		}
 		return any(intVal).(T)
+	case float64:
+		floatVal, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Invalid float value for %s", key)
+		}
+		return any(floatVal).(T)
+	case bool:
+		boolVal, err := strconv.ParseBool(val)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Invalid bool value for %s", key)
+		}
+		return any(boolVal).(T)
 	case time.Duration:
 		dur, err := time.ParseDuration(val)
 		if err != nil {
@@ -20,6 +20,9 @@ type NotificationServiceConfig struct {
 	SmtpLogin    string
 	SmtpPassword string
 
+	// DatabaseURL is optional, suppression list isn't checked and sent emails aren't logged without it
+	DatabaseURL string
+
 	RootDir string
 }
 
@@ -38,6 +41,7 @@ func NewNotificationServiceConfig(log *zerolog.Logger) *NotificationServiceConfi
 		SmtpPort:         mustGet[int](log, "SMTP_PORT"),
 		SmtpLogin:        mustGet[string](log, "SMTP_USER"),
 		SmtpPassword:     mustGet[string](log, "SMTP_PASS"),
+		DatabaseURL:      getWithDefault[string](log, "DB_URL", ""),
 		RootDir:          rootDir,
 	}
 }
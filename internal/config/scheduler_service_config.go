@@ -0,0 +1,39 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+
+	"github.com/joho/godotenv"
+)
+
+type SchedulerServiceConfig struct {
+	Config
+	WeatherConfig
+	LinkTokenConfig
+	SchedulerConfig
+
+	Port        int
+	DatabaseURL string
+	BrokerURL   string
+
+	RootDir string
+}
+
+func NewSchedulerServiceConfig(log *zerolog.Logger) *SchedulerServiceConfig {
+	rootDir := getRootDir(log)
+	err := godotenv.Load(filepath.Join(rootDir, ".env.scheduler_service"))
+	if err != nil {
+		log.Warn().Msg("Failed to load .env file!")
+	}
+	return &SchedulerServiceConfig{
+		WeatherConfig:   newWeatherConfig(log),
+		LinkTokenConfig: newLinkTokenConfig(log),
+		SchedulerConfig: newSchedulerConfig(log),
+		Port:            mustGet[int](log, "PORT"),
+		DatabaseURL:     mustGet[string](log, "DB_URL"),
+		BrokerURL:       mustGet[string](log, "BROKER_URL"),
+		RootDir:         rootDir,
+	}
+}
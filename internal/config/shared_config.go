@@ -0,0 +1,129 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WeatherConfig configures weather and air quality providers with their Redis cache,
+// shared by services fetching weather
+type WeatherConfig struct {
+	ProviderMode      string
+	FakeScenariosPath string
+
+	OpenWeatherAPIEndpoint string
+	OpenWeatherAPIkey      string
+	WeatherApiAPIEndpoint  string
+	WeatherApiAPIkey       string
+	OpenWeatherAirEndpoint string
+	OpenWeatherGeoEndpoint string
+	OpenWeatherDailyQuota  int
+	WeatherApiDailyQuota   int
+
+	ProviderTimeout        time.Duration
+	ProviderMaxRetries     int
+	ProviderRetryBaseDelay time.Duration
+	ProviderMaxBodyBytes   int
+	ProviderMaxIdleConns   int
+
+	ConsensusEnabled              bool
+	ConsensusTemperatureTolerance float64
+	ConsensusHumidityTolerance    float64
+	ConsensusPressureTolerance    float64
+	ConsensusWindSpeedTolerance   float64
+
+	RedisURL      string
+	RedisPassword string
+	CacheTTL      time.Duration
+	AirQualityTTL time.Duration
+	LockTTL       time.Duration
+	LockRetryDur  time.Duration
+	LockMaxWait   time.Duration
+}
+
+// LinkTokenConfig configures signing of unsubscribe and manage links embedded into weather emails
+type LinkTokenConfig struct {
+	TokenSigningKeys    string
+	UnsubscribeTokenTTL time.Duration
+	ManageTokenTTL      time.Duration
+}
+
+// SchedulerConfig configures scheduled weather emails
+type SchedulerConfig struct {
+	DigestEmails bool
+
+	SchedulerLeaderElection bool
+	SchedulerInstanceID     string
+	SchedulerLeaseTTL       time.Duration
+
+	// CatchUpWindow is how old a missed notification slot may be to still be sent at startup, zero disables catch-up
+	CatchUpWindow time.Duration
+	// JobRunTimeout is how long notification run may stay running before it's taken for crashed and caught up
+	JobRunTimeout time.Duration
+	// NotificationPageSize is number of subscriptions loaded at once by notification jobs
+	NotificationPageSize int
+}
+
+func newWeatherConfig(log *zerolog.Logger) WeatherConfig {
+	// fake providers don't call upstream APIs, so their keys and endpoints are optional
+	providerMode := getWithDefault[string](log, "WEATHER_PROVIDER_MODE", ProviderModeLive)
+	providerSetting := mustGet[string]
+	if providerMode == ProviderModeFake {
+		providerSetting = func(log *zerolog.Logger, key string) string {
+			return getWithDefault[string](log, key, "")
+		}
+	} else if providerMode != ProviderModeLive {
+		log.Fatal().Msgf("Invalid WEATHER_PROVIDER_MODE %q, expected %s or %s", providerMode, ProviderModeLive, ProviderModeFake)
+	}
+
+	return WeatherConfig{
+		ProviderMode:                  providerMode,
+		FakeScenariosPath:             getWithDefault[string](log, "FAKE_SCENARIOS_PATH", ""),
+		OpenWeatherAPIEndpoint:        providerSetting(log, "OPENWEATHER_API_ENDPOINT"),
+		OpenWeatherAPIkey:             providerSetting(log, "OPENWEATHER_API_KEY"),
+		WeatherApiAPIEndpoint:         providerSetting(log, "WEATHER_API_API_ENDPOINT"),
+		WeatherApiAPIkey:              providerSetting(log, "WEATHER_API_API_KEY"),
+		OpenWeatherAirEndpoint:        getWithDefault[string](log, "OPENWEATHER_AIR_POLLUTION_ENDPOINT", "http://api.openweathermap.org/data/2.5/air_pollution"),
+		OpenWeatherGeoEndpoint:        getWithDefault[string](log, "OPENWEATHER_GEO_ENDPOINT", "http://api.openweathermap.org/geo/1.0/direct"),
+		OpenWeatherDailyQuota:         getWithDefault[int](log, "OPENWEATHER_DAILY_QUOTA", 0),
+		WeatherApiDailyQuota:          getWithDefault[int](log, "WEATHER_API_DAILY_QUOTA", 0),
+		ProviderTimeout:               getWithDefault[time.Duration](log, "PROVIDER_TIMEOUT", 5*time.Second),
+		ProviderMaxRetries:            getWithDefault[int](log, "PROVIDER_MAX_RETRIES", 2),
+		ProviderRetryBaseDelay:        getWithDefault[time.Duration](log, "PROVIDER_RETRY_BASE_DELAY", 100*time.Millisecond),
+		ProviderMaxBodyBytes:          getWithDefault[int](log, "PROVIDER_MAX_BODY_BYTES", 1<<20),
+		ProviderMaxIdleConns:          getWithDefault[int](log, "PROVIDER_MAX_IDLE_CONNS_PER_HOST", 10),
+		ConsensusEnabled:              getWithDefault[bool](log, "WEATHER_CONSENSUS_ENABLED", false),
+		ConsensusTemperatureTolerance: getWithDefault[float64](log, "CONSENSUS_TEMPERATURE_TOLERANCE", 3),
+		ConsensusHumidityTolerance:    getWithDefault[float64](log, "CONSENSUS_HUMIDITY_TOLERANCE", 15),
+		ConsensusPressureTolerance:    getWithDefault[float64](log, "CONSENSUS_PRESSURE_TOLERANCE", 5),
+		ConsensusWindSpeedTolerance:   getWithDefault[float64](log, "CONSENSUS_WIND_SPEED_TOLERANCE", 3),
+		RedisURL:                      mustGet[string](log, "REDIS_URL"),
+		RedisPassword:                 mustGet[string](log, "REDIS_PWD"),
+		CacheTTL:                      getWithDefault[time.Duration](log, "CACHE_TTL", 5*time.Minute),
+		AirQualityTTL:                 getWithDefault[time.Duration](log, "AIR_QUALITY_CACHE_TTL", 30*time.Minute),
+		LockTTL:                       getWithDefault[time.Duration](log, "LOCK_TTL", 3*time.Second),
+		LockRetryDur:                  getWithDefault[time.Duration](log, "LOCK_RETRY_DUR", 100*time.Millisecond),
+		LockMaxWait:                   getWithDefault[time.Duration](log, "LOCK_MAX_WAIT", 3*time.Second),
+	}
+}
+
+func newLinkTokenConfig(log *zerolog.Logger) LinkTokenConfig {
+	return LinkTokenConfig{
+		TokenSigningKeys:    mustGet[string](log, "TOKEN_SIGNING_KEYS"),
+		UnsubscribeTokenTTL: getWithDefault[time.Duration](log, "UNSUBSCRIBE_TOKEN_TTL", 365*24*time.Hour),
+		ManageTokenTTL:      getWithDefault[time.Duration](log, "MANAGE_TOKEN_TTL", 30*24*time.Hour),
+	}
+}
+
+func newSchedulerConfig(log *zerolog.Logger) SchedulerConfig {
+	return SchedulerConfig{
+		DigestEmails:            getWithDefault[bool](log, "DIGEST_EMAILS", false),
+		SchedulerLeaderElection: getWithDefault[bool](log, "SCHEDULER_LEADER_ELECTION", true),
+		SchedulerInstanceID:     getWithDefault[string](log, "SCHEDULER_INSTANCE_ID", ""),
+		SchedulerLeaseTTL:       getWithDefault[time.Duration](log, "SCHEDULER_LEASE_TTL", 15*time.Second),
+		CatchUpWindow:           getWithDefault[time.Duration](log, "CATCH_UP_WINDOW", 3*time.Hour),
+		JobRunTimeout:           getWithDefault[time.Duration](log, "JOB_RUN_TIMEOUT", 30*time.Minute),
+		NotificationPageSize:    getWithDefault[int](log, "NOTIFICATION_PAGE_SIZE", 500),
+	}
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// AdminActor identifies operator performing admin action for audit log
+type AdminActor struct {
+	Name     string
+	RemoteIP string
+}
+
+type AdminUser struct {
+	ID            uint                `json:"id"`
+	Email         string              `json:"email"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Subscriptions []AdminSubscription `json:"subscriptions"`
+}
+
+// AdminSubscription is subscription with confirmation and delivery state, LastSentAt is when
+// weather email was last queued for delivery
+type AdminSubscription struct {
+	ID                uint                         `json:"id"`
+	City              string                       `json:"city"`
+	Frequency         constants.Frequency          `json:"frequency"`
+	Units             constants.Units              `json:"units"`
+	Lang              string                       `json:"lang"`
+	IncludeAirQuality bool                         `json:"include_air_quality"`
+	AQIAlertThreshold *int                         `json:"aqi_alert_threshold,omitempty"`
+	Status            constants.SubscriptionStatus `json:"status"`
+	CreatedAt         time.Time                    `json:"created_at"`
+	TokenExpires      time.Time                    `json:"token_expires"`
+	ConfirmedAt       *time.Time                   `json:"confirmed_at,omitempty"`
+	LastSentAt        *time.Time                   `json:"last_sent_at,omitempty"`
+	UnsubscribedAt    *time.Time                   `json:"unsubscribed_at,omitempty"`
+	PausedAt          *time.Time                   `json:"paused_at,omitempty"`
+	PauseReason       *constants.PauseReason       `json:"pause_reason,omitempty"`
+	ResumeAt          *time.Time                   `json:"resume_at,omitempty"`
+}
+
+type AuditLogEntry struct {
+	ID             uint                   `json:"id"`
+	CreatedAt      time.Time              `json:"created_at"`
+	Actor          string                 `json:"actor"`
+	RemoteIP       string                 `json:"remote_ip,omitempty"`
+	Action         string                 `json:"action"`
+	UserID         *uint                  `json:"user_id,omitempty"`
+	SubscriptionID *uint                  `json:"subscription_id,omitempty"`
+	Details        string                 `json:"details,omitempty"`
+	Outcome        constants.AuditOutcome `json:"outcome,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+type JobRun struct {
+	ID         uint                   `json:"id"`
+	Job        string                 `json:"job"`
+	Slot       time.Time              `json:"slot"`
+	CatchUp    bool                   `json:"catch_up"`
+	Status     constants.JobRunStatus `json:"status"`
+	Instance   string                 `json:"instance,omitempty"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+	Cities     int                    `json:"cities"`
+	Recipients int                    `json:"recipients"`
+	Failures   int                    `json:"failures"`
+	Error      string                 `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "weatherApi/internal/common/constants"
+
+// AirQualityResponse is normalized air quality reading, pollutant concentrations are in μg/m³.
+// AQI is US EPA Air Quality Index (0-500) calculated from particulate matter concentrations.
+type AirQualityResponse struct {
+	AQI      int                          `json:"aqi"`
+	Category constants.AirQualityCategory `json:"category"`
+	PM25     float64                      `json:"pm2_5"`
+	PM10     float64                      `json:"pm10"`
+	O3       float64                      `json:"o3"`
+	NO2      float64                      `json:"no2"`
+}
+
+type WeatherAPIAirQualityResponse struct {
+	Current struct {
+		AirQuality struct {
+			CO       float64 `json:"co"`
+			NO2      float64 `json:"no2"`
+			O3       float64 `json:"o3"`
+			SO2      float64 `json:"so2"`
+			PM25     float64 `json:"pm2_5"`
+			PM10     float64 `json:"pm10"`
+			USEPAIdx int     `json:"us-epa-index"` //nolint:tagliatelle // external API field name
+		} `json:"air_quality"`
+	} `json:"current"`
+}
+
+type OpenweatherMapAirPollutionResponse struct {
+	List []struct {
+		Main struct {
+			AQI int `json:"aqi"`
+		} `json:"main"`
+		Components struct {
+			CO   float64 `json:"co"`
+			NO   float64 `json:"no"`
+			NO2  float64 `json:"no2"`
+			O3   float64 `json:"o3"`
+			SO2  float64 `json:"so2"`
+			PM25 float64 `json:"pm2_5"`
+			PM10 float64 `json:"pm10"`
+			NH3  float64 `json:"nh3"`
+		} `json:"components"`
+	} `json:"list"`
+}
+
+type OpenweatherMapGeocodingResponse []struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+}
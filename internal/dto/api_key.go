@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name               string `json:"name"                  binding:"required,max=64"`
+	RateLimitPerMinute int    `json:"rate_limit_per_minute" binding:"omitempty,min=1"`
+}
+
+// APIKey describes issued key without its secret part
+type APIKey struct {
+	ID                 uint       `json:"id"`
+	Name               string     `json:"name"`
+	Prefix             string     `json:"prefix"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	CreatedAt          time.Time  `json:"created_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKey is returned once on issuance, Key can't be retrieved later
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// RateLimit is outcome of taking a request from client bucket, Reset is time until bucket is full again
+// for allowed requests and until the next request is allowed otherwise
+type RateLimit struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
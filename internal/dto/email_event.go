@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// EmailEvent is delivery feedback for a single recipient
+type EmailEvent struct {
+	Email      string
+	Type       constants.EmailEventType
+	Source     string
+	Details    string
+	OccurredAt time.Time
+}
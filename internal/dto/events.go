@@ -4,14 +4,48 @@ type ConfirmationEmailTask struct {
 	Email string `json:"email"`
 	Token string `json:"token"`
 	City  string `json:"city"`
+	// EmailChange marks confirmation of new address for already confirmed subscription
+	EmailChange bool `json:"email_change,omitempty"`
 }
 
 type UserData struct {
-	Email string `json:"email"`
-	Token string `json:"token"`
+	Email            string `json:"email"`
+	UnsubscribeToken string `json:"unsubscribe_token"`
+	ManageToken      string `json:"manage_token"`
+	Lang             string `json:"lang,omitempty"`
+
+	IncludeAirQuality bool `json:"include_air_quality,omitempty"`
+	AQIAlertThreshold *int `json:"aqi_alert_threshold,omitempty"`
+}
+
+// WantsAirQuality reports whether air quality has to be fetched for this user
+func (u *UserData) WantsAirQuality() bool {
+	return u.IncludeAirQuality || u.AQIAlertThreshold != nil
+}
+
+// IsAirQualityAlert reports whether reading reaches user's alert threshold
+func (u *UserData) IsAirQualityAlert(airQuality *AirQualityResponse) bool {
+	return airQuality != nil && u.AQIAlertThreshold != nil && airQuality.AQI >= *u.AQIAlertThreshold
+}
+
+// DigestSection is weather of one subscribed city inside digest email, links manage this subscription only
+type DigestSection struct {
+	City       string              `json:"city"`
+	User       UserData            `json:"user"`
+	Weather    WeatherResponse     `json:"weather"`
+	AirQuality *AirQualityResponse `json:"air_quality,omitempty"`
+}
+
+// WeatherDigestTask is a single email with weather of all cities user is subscribed to
+type WeatherDigestTask struct {
+	Email    string          `json:"email"`
+	Lang     string          `json:"lang,omitempty"`
+	Sections []DigestSection `json:"sections"`
 }
 
 type WeatherSubData struct {
-	Users   []UserData      `json:"users"`
-	Weather WeatherResponse `json:"weather"`
+	City       string              `json:"city,omitempty"`
+	Users      []UserData          `json:"users"`
+	Weather    WeatherResponse     `json:"weather"`
+	AirQuality *AirQualityResponse `json:"air_quality,omitempty"`
 }
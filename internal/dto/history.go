@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+type HistoryQuery struct {
+	Location    Location
+	From        time.Time
+	To          time.Time
+	Granularity constants.Granularity
+}
+
+type HistoryStats struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+type HistoryPoint struct {
+	Time        time.Time    `json:"time"`
+	Samples     int          `json:"samples"`
+	Temperature HistoryStats `json:"temperature"`
+	Humidity    HistoryStats `json:"humidity"`
+	Pressure    HistoryStats `json:"pressure"`
+	WindSpeed   HistoryStats `json:"wind_speed"`
+}
+
+// HistoryResponse holds aggregated observations, values are in metric units
+type HistoryResponse struct {
+	Location    string                `json:"location"`
+	From        time.Time             `json:"from"`
+	To          time.Time             `json:"to"`
+	Granularity constants.Granularity `json:"granularity"`
+	Units       constants.Units       `json:"units"`
+	Points      []HistoryPoint        `json:"points"`
+}
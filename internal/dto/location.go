@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Location identifies the place weather is requested for: either a city name or a pair of coordinates.
+// When both are set (e.g. resolved from GeoIP), coordinates take precedence and the city is kept for display.
+type Location struct {
+	City        string       `json:"city,omitempty"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+}
+
+func NewCityLocation(city string) Location {
+	return Location{City: strings.TrimSpace(city)}
+}
+
+func NewCoordinatesLocation(lat, lon float64) Location {
+	return Location{Coordinates: &Coordinates{Lat: lat, Lon: lon}}
+}
+
+func (l Location) HasCoordinates() bool {
+	return l.Coordinates != nil
+}
+
+func (l Location) IsEmpty() bool {
+	return l.City == "" && l.Coordinates == nil
+}
+
+func (l Location) Validate() error {
+	if l.IsEmpty() {
+		return fmt.Errorf("location is empty")
+	}
+	if l.HasCoordinates() {
+		if !inRange(l.Coordinates.Lat, 90) {
+			return fmt.Errorf("latitude %f is out of range", l.Coordinates.Lat)
+		}
+		if !inRange(l.Coordinates.Lon, 180) {
+			return fmt.Errorf("longitude %f is out of range", l.Coordinates.Lon)
+		}
+	}
+	return nil
+}
+
+// inRange reports whether value is a finite number within [-limit, limit], NaN fails every comparison
+// so it's rejected explicitly
+func inRange(value, limit float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0) && value >= -limit && value <= limit
+}
+
+// Key returns location identifier used for cache keys.
+// Coordinates are rounded to 2 decimals (~1km), which is finer than providers resolution anyway.
+func (l Location) Key() string {
+	if l.HasCoordinates() {
+		return fmt.Sprintf("%.2f,%.2f", l.Coordinates.Lat, l.Coordinates.Lon)
+	}
+	return l.City
+}
+
+// StorageKey is case-insensitive Key used to persist readings of the same place under one name
+func (l Location) StorageKey() string {
+	return strings.ToLower(l.Key())
+}
+
+func (l Location) String() string {
+	if l.HasCoordinates() {
+		if l.City != "" {
+			return fmt.Sprintf("%s (%.4f,%.4f)", l.City, l.Coordinates.Lat, l.Coordinates.Lon)
+		}
+		return fmt.Sprintf("%.4f,%.4f", l.Coordinates.Lat, l.Coordinates.Lon)
+	}
+	return l.City
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+type PrivacyRequest struct {
+	Email  string                  `json:"email"  binding:"required,email,max=255"`
+	Action constants.PrivacyAction `json:"action" binding:"required,oneof=export erase"`
+}
+
+// PrivacyLinkTask asks notification service to email link verifying privacy request
+type PrivacyLinkTask struct {
+	Email  string                  `json:"email"`
+	Token  string                  `json:"token"`
+	Action constants.PrivacyAction `json:"action"`
+}
+
+// DataExport is everything stored about a user, LastSentAt is the delivery history kept per subscription
+type DataExport struct {
+	GeneratedAt   time.Time            `json:"generated_at"`
+	User          ExportUser           `json:"user"`
+	Subscriptions []ExportSubscription `json:"subscriptions"`
+	Deliveries    []ExportDelivery     `json:"deliveries"`
+}
+
+// ExportDelivery is a weather email sent to user
+type ExportDelivery struct {
+	Kind   constants.DeliveryKind `json:"kind"`
+	Cities []string               `json:"cities"`
+	SentAt time.Time              `json:"sent_at"`
+}
+
+type ExportUser struct {
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportSubscription struct {
+	City              string              `json:"city"`
+	Frequency         constants.Frequency `json:"frequency"`
+	Units             constants.Units     `json:"units"`
+	Lang              string              `json:"lang"`
+	IncludeAirQuality bool                `json:"include_air_quality"`
+	AQIAlertThreshold *int                `json:"aqi_alert_threshold,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	ConfirmedAt       *time.Time          `json:"confirmed_at,omitempty"`
+	LastSentAt        *time.Time          `json:"last_sent_at,omitempty"`
+	UnsubscribedAt    *time.Time          `json:"unsubscribed_at,omitempty"`
+}
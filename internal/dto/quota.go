@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// ProviderQuota is daily usage of provider account, Limit 0 means unlimited
+type ProviderQuota struct {
+	Provider  string    `json:"provider"`
+	Used      int64     `json:"used"`
+	Limit     int64     `json:"limit"`
+	Remaining *int64    `json:"remaining,omitempty"`
+	Exhausted bool      `json:"exhausted"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
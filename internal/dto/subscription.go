@@ -1,7 +1,55 @@
 package dto
 
+import (
+	"strings"
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
 type SubscribeRequest struct {
-	Email     string `json:"email"     binding:"required,email"`
+	Email     string `json:"email"     binding:"required,email,max=255"`
 	City      string `json:"city"      binding:"required"`
 	Frequency string `json:"frequency" binding:"required,oneof=hourly daily"`
+	Units     string `json:"units"     binding:"omitempty,oneof=metric imperial standard"`
+	Lang      string `json:"lang"      binding:"omitempty,max=8"`
+
+	IncludeAirQuality bool `json:"include_air_quality"`
+	AQIAlertThreshold *int `json:"aqi_alert_threshold" binding:"omitempty,min=1,max=500"`
+
+	CaptchaToken string `json:"captcha_token"`
+	// ClientIP is set by handler for abuse protection
+	ClientIP string `json:"-"`
+}
+
+func (r *SubscribeRequest) WeatherOptions() WeatherOptions {
+	return WeatherOptions{Units: constants.Units(r.Units), Lang: strings.ToLower(r.Lang)}.Normalize()
+}
+
+// EmailChangeRequest moves subscription to another address once the new address is confirmed
+type EmailChangeRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	// ClientIP is set by handler for abuse protection
+	ClientIP string `json:"-"`
+}
+
+// PauseRequest pauses weather emails for Days days, zero Days pauses until resumed
+type PauseRequest struct {
+	Days int `json:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// SubscriptionSettings is what subscriber sees by manage link
+type SubscriptionSettings struct {
+	City      string              `json:"city"`
+	Frequency constants.Frequency `json:"frequency"`
+	Units     constants.Units     `json:"units"`
+	Lang      string              `json:"lang"`
+
+	IncludeAirQuality bool `json:"include_air_quality"`
+	AQIAlertThreshold *int `json:"aqi_alert_threshold,omitempty"`
+	// PendingEmail is new address waiting for confirmation
+	PendingEmail *string `json:"pending_email,omitempty"`
+
+	Paused      bool                   `json:"paused"`
+	PauseReason *constants.PauseReason `json:"pause_reason,omitempty"`
+	ResumeAt    *time.Time             `json:"resume_at,omitempty"`
 }
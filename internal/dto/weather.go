@@ -1,9 +1,31 @@
 package dto
 
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// WeatherResponse is normalized weather reading. Providers always return metric units:
+// temperature in °C, pressure in hPa, wind speed in m/s, wind direction in degrees, visibility in km,
+// conversion to requested units is done by weather service.
 type WeatherResponse struct {
-	Temperature float64 `json:"temperature"`
-	Humidity    int     `json:"humidity"`
-	Description string  `json:"description"`
+	Temperature   float64                    `json:"temperature"`
+	FeelsLike     float64                    `json:"feels_like"`
+	Humidity      int                        `json:"humidity"`
+	Pressure      float64                    `json:"pressure"`
+	WindSpeed     float64                    `json:"wind_speed"`
+	WindDirection int                        `json:"wind_direction"`
+	Visibility    float64                    `json:"visibility"`
+	UVIndex       *float64                   `json:"uv_index,omitempty"`
+	Sunrise       *time.Time                 `json:"sunrise,omitempty"`
+	Sunset        *time.Time                 `json:"sunset,omitempty"`
+	Condition     constants.WeatherCondition `json:"condition"`
+	Icon          string                     `json:"icon"`
+	Description   string                     `json:"description"`
+	Units         constants.Units            `json:"units"`
+	Provider      string                     `json:"provider,omitempty"`
+	Sources       []string                   `json:"sources,omitempty"`
+	Confidence    *float64                   `json:"confidence,omitempty"`
 }
 
 type WeatherAPIResponse struct {
@@ -15,9 +37,17 @@ type WeatherAPIResponse struct {
 
 	Current struct {
 		Temperature float64 `json:"temp_c"`
+		FeelsLike   float64 `json:"feelslike_c"`
 		Humidity    int     `json:"humidity"`
+		PressureMb  float64 `json:"pressure_mb"`
+		WindKph     float64 `json:"wind_kph"`
+		WindDegree  int     `json:"wind_degree"`
+		VisKm       float64 `json:"vis_km"`
+		UV          float64 `json:"uv"`
 		Condition   struct {
 			Text string `json:"text"`
+			Icon string `json:"icon"`
+			Code int    `json:"code"`
 		} `json:"condition"`
 	} `json:"current"`
 }
@@ -27,6 +57,7 @@ type OpenweatherMapAPIResponse struct {
 		Temperature float64 `json:"temp"`
 		FeelsLike   float64 `json:"feels_like"`
 		Humidity    int     `json:"humidity"`
+		Pressure    float64 `json:"pressure"`
 	} `json:"main"`
 
 	Weather []struct {
@@ -35,4 +66,17 @@ type OpenweatherMapAPIResponse struct {
 		Description string `json:"description"`
 		Icon        string `json:"icon"`
 	} `json:"weather"`
+
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+	} `json:"wind"`
+
+	// Visibility in meters
+	Visibility int `json:"visibility"`
+
+	Sys struct {
+		Sunrise int64 `json:"sunrise"`
+		Sunset  int64 `json:"sunset"`
+	} `json:"sys"`
 }
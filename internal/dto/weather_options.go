@@ -0,0 +1,28 @@
+package dto
+
+import "weatherApi/internal/common/constants"
+
+// WeatherOptions describes how weather reading is presented to the client
+type WeatherOptions struct {
+	Units constants.Units `json:"units"`
+	Lang  string          `json:"lang"`
+}
+
+func DefaultWeatherOptions() WeatherOptions {
+	return WeatherOptions{Units: constants.UnitsMetric, Lang: constants.DefaultLang}
+}
+
+// Normalize fills empty values with defaults
+func (o WeatherOptions) Normalize() WeatherOptions {
+	if o.Units == "" {
+		o.Units = constants.UnitsMetric
+	}
+	if o.Lang == "" {
+		o.Lang = constants.DefaultLang
+	}
+	return o
+}
+
+func (o WeatherOptions) IsValid() bool {
+	return o.Units.IsValid() && constants.IsValidLang(o.Lang)
+}
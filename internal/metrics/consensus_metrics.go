@@ -0,0 +1,40 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type ConsensusMetrics struct {
+	disagreements *prometheus.CounterVec
+	confidence    prometheus.Histogram
+}
+
+func NewConsensusMetrics() *ConsensusMetrics {
+	return &ConsensusMetrics{
+		disagreements: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "weather_provider_disagreements_total",
+				Help: "Total number of provider readings deviating from consensus, labeled by provider and field",
+			},
+			[]string{"provider", "field"},
+		),
+		confidence: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "weather_consensus_confidence",
+			Help:    "Share of queried providers agreeing with consensus reading",
+			Buckets: []float64{0, 0.25, 0.5, 0.75, 1},
+		}),
+	}
+}
+
+func (m *ConsensusMetrics) Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		m.disagreements,
+		m.confidence,
+	)
+}
+
+func (m *ConsensusMetrics) IncDisagreement(provider, field string) {
+	m.disagreements.WithLabelValues(provider, field).Inc()
+}
+
+func (m *ConsensusMetrics) ObserveConfidence(confidence float64) {
+	m.confidence.Observe(confidence)
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type MaintenanceMetrics struct {
+	runs      *prometheus.CounterVec
+	deleted   *prometheus.CounterVec
+	reminders *prometheus.CounterVec
+}
+
+func NewMaintenanceMetrics() *MaintenanceMetrics {
+	return &MaintenanceMetrics{
+		runs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "maintenance_runs_total",
+				Help: "Total number of subscription maintenance runs",
+			},
+			[]string{"status"},
+		),
+		deleted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "maintenance_deleted_total",
+				Help: "Total number of expired unconfirmed subscriptions and orphaned users deleted",
+			},
+			[]string{"kind"},
+		),
+		reminders: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "maintenance_confirmation_reminders_total",
+				Help: "Total number of confirmation reminders sent before token expiry",
+			},
+			[]string{"status"},
+		),
+	}
+}
+
+func (m *MaintenanceMetrics) Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		m.runs,
+		m.deleted,
+		m.reminders,
+	)
+}
+
+func (m *MaintenanceMetrics) IncRun(status string) {
+	m.runs.WithLabelValues(status).Inc()
+}
+
+func (m *MaintenanceMetrics) AddDeleted(kind string, count int) {
+	m.deleted.WithLabelValues(kind).Add(float64(count))
+}
+
+func (m *MaintenanceMetrics) IncReminder(status string) {
+	m.reminders.WithLabelValues(status).Inc()
+}
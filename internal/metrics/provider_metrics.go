@@ -0,0 +1,56 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type ProviderMetrics struct {
+	requestDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+}
+
+func NewProviderMetrics() *ProviderMetrics {
+	return &ProviderMetrics{
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "provider_request_duration_seconds",
+				Help:    "Histogram of upstream provider request attempts duration",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"provider", "status"},
+		),
+		retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_request_retries_total",
+				Help: "Total number of retried upstream provider requests",
+			},
+			[]string{"provider"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_errors_total",
+				Help: "Total number of upstream provider failures by error kind",
+			},
+			[]string{"provider", "kind"},
+		),
+	}
+}
+
+func (m *ProviderMetrics) Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		m.requestDuration,
+		m.retries,
+		m.errors,
+	)
+}
+
+func (m *ProviderMetrics) ObserveRequestDuration(provider, status string, seconds float64) {
+	m.requestDuration.WithLabelValues(provider, status).Observe(seconds)
+}
+
+func (m *ProviderMetrics) IncRetry(provider string) {
+	m.retries.WithLabelValues(provider).Inc()
+}
+
+func (m *ProviderMetrics) IncError(provider, kind string) {
+	m.errors.WithLabelValues(provider, kind).Inc()
+}
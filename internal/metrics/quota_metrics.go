@@ -0,0 +1,65 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type QuotaMetrics struct {
+	calls     *prometheus.CounterVec
+	used      *prometheus.GaugeVec
+	limit     *prometheus.GaugeVec
+	exhausted *prometheus.CounterVec
+}
+
+func NewQuotaMetrics() *QuotaMetrics {
+	return &QuotaMetrics{
+		calls: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_calls_total",
+				Help: "Total number of upstream provider calls",
+			},
+			[]string{"provider"},
+		),
+		used: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_quota_used",
+				Help: "Number of provider calls made today (UTC)",
+			},
+			[]string{"provider"},
+		),
+		limit: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_quota_limit",
+				Help: "Configured daily provider calls limit, 0 means unlimited",
+			},
+			[]string{"provider"},
+		),
+		exhausted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_quota_exhausted_total",
+				Help: "Total number of provider calls skipped because daily quota is exhausted",
+			},
+			[]string{"provider"},
+		),
+	}
+}
+
+func (m *QuotaMetrics) Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		m.calls,
+		m.used,
+		m.limit,
+		m.exhausted,
+	)
+}
+
+func (m *QuotaMetrics) ObserveCall(provider string, usedToday int64) {
+	m.calls.WithLabelValues(provider).Inc()
+	m.used.WithLabelValues(provider).Set(float64(usedToday))
+}
+
+func (m *QuotaMetrics) SetLimit(provider string, limit int64) {
+	m.limit.WithLabelValues(provider).Set(float64(limit))
+}
+
+func (m *QuotaMetrics) IncExhausted(provider string) {
+	m.exhausted.WithLabelValues(provider).Inc()
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type SchedulerMetrics struct {
+	leader        prometheus.Gauge
+	leaderChanges *prometheus.CounterVec
+}
+
+func NewSchedulerMetrics() *SchedulerMetrics {
+	return &SchedulerMetrics{
+		leader: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "scheduler_leader",
+			Help: "1 when this instance holds scheduler lease and runs jobs, 0 otherwise",
+		}),
+		leaderChanges: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "scheduler_leader_changes_total",
+				Help: "Total number of times this instance gained or lost scheduler leadership",
+			},
+			[]string{"change"},
+		),
+	}
+}
+
+func (m *SchedulerMetrics) Register(reg prometheus.Registerer) {
+	reg.MustRegister(
+		m.leader,
+		m.leaderChanges,
+	)
+}
+
+func (m *SchedulerMetrics) SetLeader(leader bool) {
+	if leader {
+		m.leader.Set(1)
+		m.leaderChanges.WithLabelValues("acquired").Inc()
+		return
+	}
+	m.leader.Set(0)
+	m.leaderChanges.WithLabelValues("lost").Inc()
+}
@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 	"weatherApi/internal/common/constants"
 	"weatherApi/internal/metrics"
@@ -11,6 +14,8 @@ import (
 	"github.com/google/uuid"
 )
 
+const WebhookSecretHeader = "X-Webhook-Secret"
+
 func TraceMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		traceID := uuid.NewString()
@@ -41,3 +46,36 @@ func PrometheusMiddleware(httpMetrics *metrics.HTTPMetrics) gin.HandlerFunc {
 		httpMetrics.ObserveRequestDuration(c.Request.Method, route, strconv.Itoa(status), duration)
 	}
 }
+
+// AdminAuthMiddleware allows requests with "Authorization: Bearer <token>" header only,
+// admin API is disabled when token is not configured.
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin API is disabled"})
+			return
+		}
+		provided, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// WebhookSecretMiddleware allows requests carrying shared secret in "X-Webhook-Secret" header, secret is not
+// accepted in query so it doesn't end up in access logs. Webhooks are disabled when secret is not configured
+func WebhookSecretMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Webhooks are disabled"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(WebhookSecretHeader)), []byte(secret)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
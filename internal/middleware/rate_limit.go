@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"weatherApi/internal/common/errors"
+	"weatherApi/internal/dto"
+
+	"github.com/gin-gonic/gin"
+
+	serviceErrors "weatherApi/internal/service/apikey/errors"
+)
+
+const (
+	APIKeyHeader     = "X-API-Key"
+	apiKeyContextKey = "api_key"
+)
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*dto.APIKey, *errors.AppError)
+}
+
+type RateLimiter interface {
+	Allow(ctx context.Context, bucket string, perMinute int) dto.RateLimit
+	Peek(ctx context.Context, bucket string, perMinute int) dto.RateLimit
+}
+
+// APIKeyMiddleware authenticates key passed in X-API-Key header, requests without key
+// are let through anonymously unless keys are required. Failed lookups are limited per client IP
+// like anonymous requests, so a flood of made up keys is rejected before it reaches key storage
+func APIKeyMiddleware(authenticator APIKeyAuthenticator, required bool, limiter RateLimiter, ipPerMinute int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			if required {
+				c.AbortWithStatusJSON(serviceErrors.ErrAPIKeyRequired.Code, gin.H{"error": serviceErrors.ErrAPIKeyRequired.Message})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		failures := "keyfail:" + c.ClientIP()
+		if ipPerMinute > 0 {
+			if result := limiter.Peek(c.Request.Context(), failures, ipPerMinute); !result.Allowed {
+				abortRateLimited(c, result)
+				return
+			}
+		}
+		apiKey, err := authenticator.Authenticate(c.Request.Context(), key)
+		if err != nil {
+			if ipPerMinute > 0 {
+				limiter.Allow(c.Request.Context(), failures, ipPerMinute)
+			}
+			c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+			return
+		}
+		c.Set(apiKeyContextKey, apiKey)
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware limits requests per API key set by APIKeyMiddleware, anonymous requests
+// are limited per client IP. Limits are reported with RateLimit-* headers, 0 disables anonymous limit.
+func RateLimitMiddleware(limiter RateLimiter, ipPerMinute int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bucket, limit := "ip:"+c.ClientIP(), ipPerMinute
+		if value, ok := c.Get(apiKeyContextKey); ok {
+			apiKey := value.(*dto.APIKey)
+			bucket, limit = "key:"+strconv.FormatUint(uint64(apiKey.ID), 10), apiKey.RateLimitPerMinute
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result := limiter.Allow(c.Request.Context(), bucket, limit)
+		if !result.Allowed {
+			abortRateLimited(c, result)
+			return
+		}
+		setRateLimitHeaders(c, result)
+		c.Next()
+	}
+}
+
+func setRateLimitHeaders(c *gin.Context, result dto.RateLimit) string {
+	reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", reset)
+	return reset
+}
+
+func abortRateLimited(c *gin.Context, result dto.RateLimit) {
+	c.Header("Retry-After", setRateLimitHeaders(c, result))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"math"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+type aqiBreakpoint struct {
+	concLow, concHigh float64
+	idxLow, idxHigh   int
+}
+
+// US EPA breakpoints (2024 revision), see https://www.airnow.gov/aqi/aqi-calculator-concentration/
+var (
+	pm25Breakpoints = []aqiBreakpoint{
+		{0.0, 9.0, 0, 50},
+		{9.1, 35.4, 51, 100},
+		{35.5, 55.4, 101, 150},
+		{55.5, 125.4, 151, 200},
+		{125.5, 225.4, 201, 300},
+		{225.5, 325.4, 301, 500},
+	}
+	pm10Breakpoints = []aqiBreakpoint{
+		{0, 54, 0, 50},
+		{55, 154, 51, 100},
+		{155, 254, 101, 150},
+		{255, 354, 151, 200},
+		{355, 424, 201, 300},
+		{425, 604, 301, 500},
+	}
+)
+
+const maxAQI = 500
+
+// newAirQualityResponse builds provider-agnostic reading, AQI is calculated from particulate matter
+// instead of trusting provider indexes as each provider uses its own scale.
+func newAirQualityResponse(pm25, pm10, o3, no2 float64) *dto.AirQualityResponse {
+	aqi := max(
+		subIndex(math.Floor(pm25*10)/10, pm25Breakpoints),
+		subIndex(math.Floor(pm10), pm10Breakpoints),
+	)
+	return &dto.AirQualityResponse{
+		AQI:      aqi,
+		Category: airQualityCategory(aqi),
+		PM25:     pm25,
+		PM10:     pm10,
+		O3:       o3,
+		NO2:      no2,
+	}
+}
+
+func subIndex(concentration float64, breakpoints []aqiBreakpoint) int {
+	if concentration <= 0 {
+		return 0
+	}
+	// concentration is truncated by caller to breakpoints precision, so there are no gaps between ranges
+	for _, bp := range breakpoints {
+		if concentration <= bp.concHigh {
+			ratio := float64(bp.idxHigh-bp.idxLow) / (bp.concHigh - bp.concLow)
+			return int(math.Round(ratio*(concentration-bp.concLow))) + bp.idxLow
+		}
+	}
+	return maxAQI
+}
+
+func airQualityCategory(aqi int) constants.AirQualityCategory {
+	switch {
+	case aqi <= 50:
+		return constants.AirQualityGood
+	case aqi <= 100:
+		return constants.AirQualityModerate
+	case aqi <= 150:
+		return constants.AirQualityUnhealthyForSensitiveGroups
+	case aqi <= 200:
+		return constants.AirQualityUnhealthy
+	case aqi <= 300:
+		return constants.AirQualityVeryUnhealthy
+	default:
+		return constants.AirQualityHazardous
+	}
+}
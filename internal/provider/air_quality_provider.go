@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"context"
+	"weatherApi/internal/common/errors"
+	"weatherApi/internal/dto"
+
+	"github.com/rs/zerolog"
+)
+
+type AirQualityProviderInterface interface {
+	SetNext(next AirQualityProviderInterface)
+	GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError)
+	Name() string
+}
+
+func TryNextAirQuality(log *zerolog.Logger, ctx context.Context, current AirQualityProviderInterface, next AirQualityProviderInterface, location dto.Location, err error) (*dto.AirQualityResponse, *errors.AppError) {
+	log.Error().Err(err).Msgf("%s: Provider failed", current.Name())
+
+	if next != nil {
+		return next.GetAirQuality(ctx, location)
+	}
+
+	log.Error().Msgf("%s: no next provider available", current.Name())
+	return nil, ClientError(err)
+}
@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+const (
+	EmailEventSourceSES      = "ses"
+	EmailEventSourceSendGrid = "sendgrid"
+	EmailEventSourceDSN      = "dsn"
+)
+
+// maxDSNReportBytes bounds delivery-status part, bounce emails often quote the whole original message
+const maxDSNReportBytes = 64 << 10
+
+var ErrUnsupportedEmailEvent = errors.New("unsupported email event payload")
+
+type sendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           *struct {
+		BounceType        string         `json:"bounceType"`
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+		Timestamp         time.Time      `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+		ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+		Timestamp             time.Time      `json:"timestamp"`
+	} `json:"complaint"`
+}
+
+// ParseEmailEvents decodes bounce and complaint webhook payload, both SendGrid event list and
+// SES notification (raw or wrapped into SNS message) are accepted. Events not affecting deliverability
+// (opens, deliveries, SNS subscription confirmations) are skipped, OccurredAt is zero when payload has no time.
+// SNS subscription confirmation is read with SNSSubscribeURL
+func ParseEmailEvents(body []byte) ([]dto.EmailEvent, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, ErrUnsupportedEmailEvent
+	}
+	if body[0] == '[' {
+		return parseSendGridEvents(body)
+	}
+
+	var envelope snsEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+	}
+	switch envelope.Type {
+	case "":
+		return parseSESNotification(body)
+	case "Notification":
+		return parseSESNotification([]byte(envelope.Message))
+	default:
+		return nil, nil
+	}
+}
+
+// SNSSubscribeURL returns SubscribeURL of SNS subscription confirmation payload, false for any other payload
+func SNSSubscribeURL(body []byte) (string, bool) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(bytes.TrimSpace(body), &envelope); err != nil {
+		return "", false
+	}
+	if envelope.Type != "SubscriptionConfirmation" || envelope.SubscribeURL == "" {
+		return "", false
+	}
+	return envelope.SubscribeURL, true
+}
+
+func parseSendGridEvents(body []byte) ([]dto.EmailEvent, error) {
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+	}
+
+	var result []dto.EmailEvent
+	for _, event := range events {
+		var eventType constants.EmailEventType
+		switch {
+		case event.Event == "spamreport":
+			eventType = constants.EmailComplaint
+		case event.Event == "bounce" && event.Type == "blocked":
+			eventType = constants.EmailSoftBounce
+		case event.Event == "bounce":
+			eventType = constants.EmailHardBounce
+		default:
+			continue
+		}
+		var occurredAt time.Time
+		if event.Timestamp > 0 {
+			occurredAt = time.Unix(event.Timestamp, 0).UTC()
+		}
+		result = append(result, dto.EmailEvent{
+			Email:      event.Email,
+			Type:       eventType,
+			Source:     EmailEventSourceSendGrid,
+			Details:    strings.TrimSpace(event.Status + " " + event.Reason),
+			OccurredAt: occurredAt,
+		})
+	}
+	return result, nil
+}
+
+func parseSESNotification(body []byte) ([]dto.EmailEvent, error) {
+	var notification sesNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+	}
+
+	kind := notification.NotificationType
+	if kind == "" {
+		kind = notification.EventType
+	}
+
+	var result []dto.EmailEvent
+	switch {
+	case kind == "Bounce" && notification.Bounce != nil:
+		eventType := constants.EmailSoftBounce
+		if notification.Bounce.BounceType == "Permanent" {
+			eventType = constants.EmailHardBounce
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			details := recipient.DiagnosticCode
+			if details == "" {
+				details = notification.Bounce.BounceType + "/" + notification.Bounce.BounceSubType
+			}
+			result = append(result, dto.EmailEvent{
+				Email:      recipient.EmailAddress,
+				Type:       eventType,
+				Source:     EmailEventSourceSES,
+				Details:    details,
+				OccurredAt: notification.Bounce.Timestamp,
+			})
+		}
+	case kind == "Complaint" && notification.Complaint != nil:
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			result = append(result, dto.EmailEvent{
+				Email:      recipient.EmailAddress,
+				Type:       constants.EmailComplaint,
+				Source:     EmailEventSourceSES,
+				Details:    notification.Complaint.ComplaintFeedbackType,
+				OccurredAt: notification.Complaint.Timestamp,
+			})
+		}
+	}
+	return result, nil
+}
+
+// ParseDSN extracts failed recipients from RFC 3464 delivery status notification (bounce email).
+// Permanent failures (5.x.x status) are hard bounces, temporary failures and delays are soft ones
+func ParseDSN(r io.Reader) ([]dto.EmailEvent, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || params["boundary"] == "" {
+		return nil, fmt.Errorf("%w: not a multipart/report message", ErrUnsupportedEmailEvent)
+	}
+
+	var occurredAt time.Time
+	if date, err := message.Header.Date(); err == nil {
+		occurredAt = date.UTC()
+	}
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: delivery-status part is missing", ErrUnsupportedEmailEvent)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType == "message/delivery-status" || partType == "message/global-delivery-status" {
+			return parseDeliveryStatus(io.LimitReader(part, maxDSNReportBytes), occurredAt)
+		}
+	}
+}
+
+// parseDeliveryStatus reads per-message fields block followed by one block per recipient
+func parseDeliveryStatus(r io.Reader, occurredAt time.Time) ([]dto.EmailEvent, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	if _, err := reader.ReadMIMEHeader(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+	}
+
+	var result []dto.EmailEvent
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			if event, ok := dsnRecipientEvent(fields, occurredAt); ok {
+				result = append(result, event)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedEmailEvent, err)
+		}
+	}
+}
+
+func dsnRecipientEvent(fields textproto.MIMEHeader, occurredAt time.Time) (dto.EmailEvent, bool) {
+	_, recipient, found := strings.Cut(fields.Get("Final-Recipient"), ";")
+	recipient = strings.Trim(strings.TrimSpace(recipient), "<>")
+	if !found || recipient == "" {
+		return dto.EmailEvent{}, false
+	}
+
+	status := strings.TrimSpace(fields.Get("Status"))
+	var eventType constants.EmailEventType
+	switch strings.ToLower(strings.TrimSpace(fields.Get("Action"))) {
+	case "failed":
+		eventType = constants.EmailSoftBounce
+		if strings.HasPrefix(status, "5.") {
+			eventType = constants.EmailHardBounce
+		}
+	case "delayed":
+		eventType = constants.EmailSoftBounce
+	default:
+		return dto.EmailEvent{}, false
+	}
+
+	details := status
+	if diagnostic := strings.TrimSpace(fields.Get("Diagnostic-Code")); diagnostic != "" {
+		details += " " + diagnostic
+	}
+	return dto.EmailEvent{
+		Email:      recipient,
+		Type:       eventType,
+		Source:     EmailEventSourceDSN,
+		Details:    details,
+		OccurredAt: occurredAt,
+	}, true
+}
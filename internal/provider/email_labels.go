@@ -0,0 +1,102 @@
+package provider
+
+import "weatherApi/internal/common/constants"
+
+// emailLabels holds translated static parts of weather email
+type emailLabels struct {
+	Heading     string
+	Conditions  string
+	Temperature string
+	FeelsLike   string
+	Humidity    string
+	Wind        string
+	Pressure    string
+	Visibility  string
+	UVIndex     string
+	SunTimes    string
+	AirQuality  string
+	Footer      string
+	Unsubscribe string
+	Manage      string
+	Snooze      string
+
+	DigestHeading string
+	DigestSubject string
+
+	AirQualityAlert        string
+	AirQualityAlertSubject string
+}
+
+var labelsByLang = map[string]emailLabels{
+	"en": {
+		Heading:     "Weather Update",
+		Conditions:  "Conditions",
+		Temperature: "Temperature",
+		FeelsLike:   "feels like",
+		Humidity:    "Humidity",
+		Wind:        "Wind",
+		Pressure:    "Pressure",
+		Visibility:  "Visibility",
+		UVIndex:     "UV index",
+		SunTimes:    "Sunrise / Sunset",
+		AirQuality:  "Air quality",
+		Footer:      "You are receiving this weather update because you subscribed to weather notifications.",
+		Unsubscribe: "Unsubscribe from future updates",
+		Manage:      "Manage subscription",
+		Snooze:      "Snooze for 7 days",
+
+		DigestHeading: "Your Weather Digest",
+		DigestSubject: "Weather digest",
+
+		AirQualityAlert:        "Air quality has reached your alert level",
+		AirQualityAlertSubject: "Air quality alert",
+	},
+	"uk": {
+		Heading:     "Оновлення погоди",
+		Conditions:  "Умови",
+		Temperature: "Температура",
+		FeelsLike:   "відчувається як",
+		Humidity:    "Вологість",
+		Wind:        "Вітер",
+		Pressure:    "Тиск",
+		Visibility:  "Видимість",
+		UVIndex:     "УФ-індекс",
+		SunTimes:    "Схід / Захід сонця",
+		AirQuality:  "Якість повітря",
+		Footer:      "Ви отримали цей лист, тому що підписалися на оновлення погоди.",
+		Unsubscribe: "Відписатися від оновлень",
+		Manage:      "Керувати підпискою",
+		Snooze:      "Призупинити на 7 днів",
+
+		DigestHeading: "Ваш погодний дайджест",
+		DigestSubject: "Погодний дайджест",
+
+		AirQualityAlert:        "Якість повітря досягла вашого порогу сповіщення",
+		AirQualityAlertSubject: "Попередження про якість повітря",
+	},
+}
+
+// labelsFor returns labels and their language, falling back to English for untranslated languages
+func labelsFor(lang string) (emailLabels, string) {
+	if labels, ok := labelsByLang[lang]; ok {
+		return labels, lang
+	}
+	return labelsByLang[constants.DefaultLang], constants.DefaultLang
+}
+
+type unitSymbols struct {
+	Temperature string
+	Speed       string
+	Distance    string
+}
+
+func unitSymbolsFor(units constants.Units) unitSymbols {
+	switch units {
+	case constants.UnitsImperial:
+		return unitSymbols{Temperature: "°F", Speed: "mph", Distance: "mi"}
+	case constants.UnitsStandard:
+		return unitSymbols{Temperature: "K", Speed: "m/s", Distance: "km"}
+	default:
+		return unitSymbols{Temperature: "°C", Speed: "m/s", Distance: "km"}
+	}
+}
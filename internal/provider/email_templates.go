@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"time"
+	"weatherApi/internal/dto"
+)
+
+var compassPoints = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// Compass returns the nearest of 8 compass points of wind bearing in degrees, any bearing including
+// negative one is accepted
+func Compass(degrees int) string {
+	normalized := ((degrees % 360) + 360) % 360
+	idx := int(math.Round(float64(normalized)/45)) % len(compassPoints)
+	return compassPoints[idx]
+}
+
+var emailTemplateFuncs = template.FuncMap{
+	"compass": Compass,
+	"clock": func(t *time.Time) string {
+		return t.UTC().Format("15:04 UTC")
+	},
+	"deref": func(v *float64) float64 {
+		return *v
+	},
+}
+
+type weatherEmailData struct {
+	Weather        *dto.WeatherResponse
+	AirQuality     *dto.AirQualityResponse
+	AirAlert       bool
+	Labels         emailLabels
+	Units          unitSymbols
+	Lang           string
+	UnsubscribeURL string
+	ManageURL      string
+	SnoozeURL      string
+}
+
+// digestSectionData is one city of digest email, links manage subscription to this city only
+type digestSectionData struct {
+	City           string
+	Weather        *dto.WeatherResponse
+	AirQuality     *dto.AirQualityResponse
+	AirAlert       bool
+	Labels         emailLabels
+	Units          unitSymbols
+	UnsubscribeURL string
+	ManageURL      string
+	SnoozeURL      string
+}
+
+type digestEmailData struct {
+	Sections []digestSectionData
+	Labels   emailLabels
+	Lang     string
+}
+
+// weatherPartials are shared by single city and digest emails, both of them render
+// "weatherDetails" and "links" with data having fields of digestSectionData
+var weatherPartials = template.Must(template.New("partials").Funcs(emailTemplateFuncs).Parse(`
+{{- define "styles" }}
+  <style>
+    body {
+      font-family: Arial, sans-serif;
+      background-color: #f4f6f8;
+      padding: 20px;
+      color: #333;
+    }
+    .container {
+      background-color: #ffffff;
+      border-radius: 8px;
+      padding: 24px;
+      max-width: 500px;
+      margin: auto;
+      box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+    }
+    .heading {
+      font-size: 22px;
+      font-weight: bold;
+      margin-bottom: 16px;
+      text-align: center;
+    }
+    .section {
+      border-top: 1px solid #e0e0e0;
+      padding-top: 16px;
+      margin-top: 16px;
+    }
+    .city {
+      font-size: 18px;
+      font-weight: bold;
+      margin-bottom: 12px;
+    }
+    .info {
+      font-size: 16px;
+      margin-bottom: 10px;
+    }
+    .alert {
+      background-color: #fdecea;
+      color: #b71c1c;
+      border-radius: 4px;
+      padding: 10px;
+      margin-bottom: 16px;
+      text-align: center;
+    }
+    .footer {
+      margin-top: 20px;
+      font-size: 12px;
+      color: #888;
+      text-align: center;
+    }
+  </style>
+{{- end }}
+{{- define "weatherDetails" }}
+    {{- if .AirAlert }}
+    <div class="alert">⚠️ {{ .Labels.AirQualityAlert }}: AQI {{ .AirQuality.AQI }}</div>
+    {{- end }}
+    <div class="info">📖 <strong>{{ .Labels.Conditions }}:</strong> {{ .Weather.Description }}</div>
+    <div class="info">🌡️ <strong>{{ .Labels.Temperature }}:</strong> {{ printf "%.1f" .Weather.Temperature }}{{ .Units.Temperature }} ({{ .Labels.FeelsLike }} {{ printf "%.1f" .Weather.FeelsLike }}{{ .Units.Temperature }})</div>
+    <div class="info">💧 <strong>{{ .Labels.Humidity }}:</strong> {{ .Weather.Humidity }}%</div>
+    <div class="info">🌬️ <strong>{{ .Labels.Wind }}:</strong> {{ printf "%.1f" .Weather.WindSpeed }} {{ .Units.Speed }} {{ compass .Weather.WindDirection }}</div>
+    <div class="info">🧭 <strong>{{ .Labels.Pressure }}:</strong> {{ printf "%.0f" .Weather.Pressure }} hPa</div>
+    <div class="info">👁️ <strong>{{ .Labels.Visibility }}:</strong> {{ printf "%.1f" .Weather.Visibility }} {{ .Units.Distance }}</div>
+    {{- if .Weather.UVIndex }}
+    <div class="info">☀️ <strong>{{ .Labels.UVIndex }}:</strong> {{ printf "%.1f" (deref .Weather.UVIndex) }}</div>
+    {{- end }}
+    {{- if and .Weather.Sunrise .Weather.Sunset }}
+    <div class="info">🌅 <strong>{{ .Labels.SunTimes }}:</strong> {{ clock .Weather.Sunrise }} / {{ clock .Weather.Sunset }}</div>
+    {{- end }}
+    {{- with .AirQuality }}
+    <div class="info">🏭 <strong>{{ $.Labels.AirQuality }}:</strong> AQI {{ .AQI }} ({{ .Category }})</div>
+    <div class="info">🌫️ <strong>PM2.5 / PM10:</strong> {{ printf "%.1f" .PM25 }} / {{ printf "%.1f" .PM10 }} µg/m³</div>
+    <div class="info">🧪 <strong>O₃ / NO₂:</strong> {{ printf "%.1f" .O3 }} / {{ printf "%.1f" .NO2 }} µg/m³</div>
+    {{- end }}
+{{- end }}
+{{- define "links" }}
+    <div class="unsubscribe">
+      👉 <a href="{{ .UnsubscribeURL }}">{{ .Labels.Unsubscribe }}</a> · <a href="{{ .ManageURL }}">{{ .Labels.Manage }}</a> · <a href="{{ .SnoozeURL }}">{{ .Labels.Snooze }}</a>
+    </div>
+{{- end }}
+`))
+
+var weatherEmailTemplate = template.Must(template.Must(weatherPartials.Clone()).New("weather").Parse(`
+<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+  <meta charset="UTF-8">
+  <title>Weather Forecast</title>
+  {{- template "styles" }}
+</head>
+<body>
+  <div class="container">
+    <div class="heading">
+      {{- if .Weather.Icon }}<img src="{{ .Weather.Icon }}" alt="{{ .Weather.Condition }}" width="48" height="48" style="vertical-align: middle;"/>{{ else }}🌤️{{ end }} {{ .Labels.Heading }}
+    </div>
+    {{- template "weatherDetails" . }}
+    <div class="footer">{{ .Labels.Footer }}</div>
+    {{- template "links" . }}
+  </div>
+</body>
+</html>
+`))
+
+var digestEmailTemplate = template.Must(template.Must(weatherPartials.Clone()).New("digest").Parse(`
+<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+  <meta charset="UTF-8">
+  <title>Weather Digest</title>
+  {{- template "styles" }}
+</head>
+<body>
+  <div class="container">
+    <div class="heading">🌤️ {{ .Labels.DigestHeading }}</div>
+    {{- range .Sections }}
+    <div class="section">
+      <div class="city">
+        {{- if .Weather.Icon }}<img src="{{ .Weather.Icon }}" alt="{{ .Weather.Condition }}" width="32" height="32" style="vertical-align: middle;"/> {{ end }}{{ .City }}
+      </div>
+      {{- template "weatherDetails" . }}
+      {{- template "links" . }}
+    </div>
+    {{- end }}
+    <div class="footer">{{ .Labels.Footer }}</div>
+  </div>
+</body>
+</html>
+`))
+
+func renderTemplate(tmpl *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
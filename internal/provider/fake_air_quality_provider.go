@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+
+	"weatherApi/internal/common/errors"
+)
+
+var _ AirQualityProviderInterface = (*FakeAirQualityProvider)(nil)
+
+// FakeAirQualityProvider is FakeWeatherProvider counterpart for air quality,
+// warming scenario doesn't affect pollution readings
+type FakeAirQualityProvider struct {
+	log       *logger.Logger
+	next      AirQualityProviderInterface
+	scenarios FakeScenarios
+}
+
+func NewFakeAirQualityProvider(log *logger.Logger, scenarios FakeScenarios) *FakeAirQualityProvider {
+	return &FakeAirQualityProvider{log: log, scenarios: scenarios}
+}
+
+func (f *FakeAirQualityProvider) Name() string {
+	return "FakeAirQuality"
+}
+
+func (f *FakeAirQualityProvider) SetNext(next AirQualityProviderInterface) {
+	f.next = next
+}
+
+func (f *FakeAirQualityProvider) GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError) {
+	if scenario, ok := f.scenarios.forKey(location.StorageKey()); ok {
+		if err := playFakeScenario(ctx, f.Name(), scenario); err != nil {
+			if ShouldFallback(err) {
+				return TryNextAirQuality(f.log.FromContext(ctx), ctx, f, f.next, location, err)
+			}
+			return nil, err
+		}
+	}
+
+	r := seededRand(location)
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return newAirQualityResponse(round(r.Float64()*60), round(r.Float64()*120), round(r.Float64()*150), round(r.Float64()*80)), nil
+}
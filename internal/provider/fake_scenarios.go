@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type FakeScenarioType string
+
+const (
+	FakeScenarioOutage   FakeScenarioType = "outage"
+	FakeScenarioSlow     FakeScenarioType = "slow"
+	FakeScenarioNotFound FakeScenarioType = "not_found"
+	FakeScenarioWarming  FakeScenarioType = "warming"
+)
+
+// fakeScenarioAnyCity applies scenario to every city without its own scenario
+const fakeScenarioAnyCity = "*"
+
+type FakeScenario struct {
+	City        string           `yaml:"city"`
+	Type        FakeScenarioType `yaml:"type"`
+	Delay       time.Duration    `yaml:"delay"`
+	RatePerHour float64          `yaml:"rate_per_hour"`
+}
+
+// FakeScenarios holds scripted behaviour of fake providers keyed by lowercased city
+type FakeScenarios map[string]FakeScenario
+
+// LoadFakeScenarios reads scenarios from YAML file:
+//
+//	scenarios:
+//	  - city: London
+//	    type: outage
+//	  - city: Kyiv
+//	    type: warming
+//	    rate_per_hour: 0.5
+func LoadFakeScenarios(path string) (FakeScenarios, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake scenarios: %w", err)
+	}
+
+	var file struct {
+		Scenarios []FakeScenario `yaml:"scenarios"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fake scenarios: %w", err)
+	}
+
+	scenarios := make(FakeScenarios, len(file.Scenarios))
+	for _, scenario := range file.Scenarios {
+		switch scenario.Type {
+		case FakeScenarioOutage, FakeScenarioSlow, FakeScenarioNotFound, FakeScenarioWarming:
+		default:
+			return nil, fmt.Errorf("unknown fake scenario type %q for %s", scenario.Type, scenario.City)
+		}
+		scenarios[strings.ToLower(strings.TrimSpace(scenario.City))] = scenario
+	}
+	return scenarios, nil
+}
+
+func (s FakeScenarios) forKey(key string) (FakeScenario, bool) {
+	if scenario, ok := s[key]; ok {
+		return scenario, true
+	}
+	scenario, ok := s[fakeScenarioAnyCity]
+	return scenario, ok
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+	"weatherApi/internal/common/utils"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+
+	"weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+var _ WeatherProviderInterface = (*FakeWeatherProvider)(nil)
+
+// FakeWeatherProvider generates deterministic readings per location without network access,
+// behaviour of particular cities can be scripted with scenarios
+type FakeWeatherProvider struct {
+	log       *logger.Logger
+	next      WeatherProviderInterface
+	scenarios FakeScenarios
+	startedAt time.Time
+	now       func() time.Time
+}
+
+func NewFakeWeatherProvider(log *logger.Logger, scenarios FakeScenarios) *FakeWeatherProvider {
+	return &FakeWeatherProvider{log: log, scenarios: scenarios, startedAt: time.Now(), now: time.Now}
+}
+
+func (f *FakeWeatherProvider) Name() string {
+	return "Fake"
+}
+
+func (f *FakeWeatherProvider) SetNext(next WeatherProviderInterface) {
+	f.next = next
+}
+
+func (f *FakeWeatherProvider) GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	result, err := f.Fetch(ctx, location, lang)
+	if ShouldFallback(err) {
+		return TryNext(f.log.FromContext(ctx), ctx, f, f.next, location, lang, err)
+	}
+	return result, err
+}
+
+func (f *FakeWeatherProvider) Fetch(ctx context.Context, location dto.Location, _ string) (*dto.WeatherResponse, *errors.AppError) {
+	scenario, hasScenario := f.scenarios.forKey(location.StorageKey())
+	if hasScenario {
+		if err := playFakeScenario(ctx, f.Name(), scenario); err != nil {
+			return nil, err
+		}
+	}
+
+	data := utils.SeededOpenweatherMapAPIResponse(seededRand(location), f.now())
+	result := openWeatherMapToWeatherResponse(&data, f.Name())
+	if hasScenario && scenario.Type == FakeScenarioWarming {
+		warming := scenario.RatePerHour * f.now().Sub(f.startedAt).Hours()
+		result.Temperature = math.Round((result.Temperature+warming)*100) / 100
+		result.FeelsLike = math.Round((result.FeelsLike+warming)*100) / 100
+	}
+	return result, nil
+}
+
+// playFakeScenario applies scenarios which affect the call itself rather than returned values
+func playFakeScenario(ctx context.Context, provider string, scenario FakeScenario) *errors.AppError {
+	switch scenario.Type {
+	case FakeScenarioOutage:
+		return providerFailure(provider, ErrorKindUnavailable, fmt.Errorf("scripted outage for %s", scenario.City))
+	case FakeScenarioNotFound:
+		return serviceErrors.ErrCityNotFound
+	case FakeScenarioSlow:
+		select {
+		case <-time.After(scenario.Delay):
+		case <-ctx.Done():
+			return providerFailure(provider, ErrorKindUnavailable, ctx.Err())
+		}
+	}
+	return nil
+}
+
+// seededRand returns random source seeded by location, so every location has its own stable reading
+func seededRand(location dto.Location) *rand.Rand {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(location.StorageKey()))
+	// #nosec G404 G115 -- not used for cryptographic purposes, overflow only changes the seed
+	return rand.New(rand.NewSource(int64(hash.Sum64())))
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+type GeoLocatorInterface interface {
+	Locate(ctx context.Context, ip string) (*dto.Location, *appErrors.AppError)
+}
+
+var _ GeoLocatorInterface = (*MaxMindGeoLocator)(nil)
+
+// MaxMindGeoLocator resolves caller location from local GeoLite2/GeoIP2 City database file (.mmdb)
+type MaxMindGeoLocator struct {
+	log    *logger.Logger
+	reader *geoip2.Reader
+}
+
+func NewMaxMindGeoLocator(log *logger.Logger, dbPath string) (*MaxMindGeoLocator, error) {
+	reader, err := geoip2.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database %s: %w", dbPath, err)
+	}
+	return &MaxMindGeoLocator{log: log, reader: reader}, nil
+}
+
+func (g *MaxMindGeoLocator) Locate(ctx context.Context, ip string) (*dto.Location, *appErrors.AppError) {
+	log := g.log.FromContext(ctx)
+
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		log.Error().Msgf("GeoIP: invalid ip address %q", ip)
+		return nil, serviceErrors.ErrLocationNotResolved
+	}
+	if parsedIP.IsLoopback() || parsedIP.IsPrivate() || parsedIP.IsUnspecified() {
+		log.Warn().Msgf("GeoIP: cannot resolve location for non public ip %s", ip)
+		return nil, serviceErrors.ErrLocationNotResolved
+	}
+
+	record, err := g.reader.City(parsedIP)
+	if err != nil {
+		log.Error().Err(err).Msgf("GeoIP: lookup failed for %s", ip)
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	if record.Location.Latitude == 0 && record.Location.Longitude == 0 {
+		return nil, serviceErrors.ErrLocationNotResolved
+	}
+
+	location := dto.NewCoordinatesLocation(record.Location.Latitude, record.Location.Longitude)
+	location.City = record.City.Names["en"]
+	return &location, nil
+}
+
+func (g *MaxMindGeoLocator) Close() error {
+	if g.reader == nil {
+		return errors.New("geoip reader is not initialized")
+	}
+	return g.reader.Close()
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"weatherApi/internal/common/errors"
+	"weatherApi/internal/metrics"
+)
+
+var (
+	// errInvalidResponse marks responses which were received but can't be used
+	errInvalidResponse = stdErrors.New("invalid response")
+	errBodyTooLarge    = fmt.Errorf("%w: body exceeds size limit", errInvalidResponse)
+)
+
+type HTTPClientOptions struct {
+	// Timeout limits a single attempt, retries are bounded by caller context. Non positive timeout
+	// and body limit fall back to defaults
+	Timeout             time.Duration
+	MaxRetries          int
+	RetryBaseDelay      time.Duration
+	MaxBodyBytes        int64
+	MaxIdleConnsPerHost int
+}
+
+func DefaultHTTPClientOptions() HTTPClientOptions {
+	return HTTPClientOptions{
+		Timeout:             5 * time.Second,
+		MaxRetries:          2,
+		RetryBaseDelay:      100 * time.Millisecond,
+		MaxBodyBytes:        1 << 20,
+		MaxIdleConnsPerHost: 10,
+	}
+}
+
+// HTTPClient is shared client of upstream providers: pooled connections, bounded retries with jitter
+// for failed GET requests and limited response bodies. Retries of requests made by QuotaLimitedProvider
+// are counted against its account and stop once quota is exhausted. Metrics are optional.
+type HTTPClient struct {
+	client  *http.Client
+	options HTTPClientOptions
+	metrics *metrics.ProviderMetrics
+}
+
+var defaultHTTPClient = NewHTTPClient(DefaultHTTPClientOptions(), nil)
+
+func NewHTTPClient(options HTTPClientOptions, providerMetrics *metrics.ProviderMetrics) *HTTPClient {
+	defaults := DefaultHTTPClientOptions()
+	if options.Timeout <= 0 {
+		options.Timeout = defaults.Timeout
+	}
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = defaults.MaxBodyBytes
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+
+	return &HTTPClient{
+		client:  &http.Client{Transport: transport},
+		options: options,
+		metrics: providerMetrics,
+	}
+}
+
+// GetJSON requests endpoint with encoded query and decodes body into target when upstream responds with 200,
+// other statuses are returned without error. Errors are returned for transport and decoding failures.
+func (c *HTTPClient) GetJSON(ctx context.Context, provider, endpoint string, query url.Values, target any) (int, error) {
+	status, body, err := c.Get(ctx, provider, endpoint, query)
+	if err != nil || status != http.StatusOK {
+		return status, err
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return status, fmt.Errorf("%w: failed to decode: %w", errInvalidResponse, err)
+	}
+	return status, nil
+}
+
+// Get requests endpoint retrying transport errors and 5xx responses
+func (c *HTTPClient) Get(ctx context.Context, provider, endpoint string, query url.Values) (int, []byte, error) {
+	requestURL, err := buildURL(endpoint, query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		status, body, err := c.do(ctx, provider, requestURL)
+		if attempt >= c.options.MaxRetries || !isRetryable(status, err) || ctx.Err() != nil || !acquireRetry(ctx) {
+			return status, body, err
+		}
+		if c.metrics != nil {
+			c.metrics.IncRetry(provider)
+		}
+		select {
+		case <-time.After(c.backoff(attempt)):
+		case <-ctx.Done():
+			return status, body, err
+		}
+	}
+}
+
+func (c *HTTPClient) do(ctx context.Context, provider, requestURL string) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request creation failed: %w", err)
+	}
+
+	start := time.Now()
+	response, err := c.client.Do(req)
+	if err != nil {
+		c.observe(provider, "error", start)
+		return 0, nil, fmt.Errorf("HTTP request failed: %w", redactURL(err))
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, c.options.MaxBodyBytes+1))
+	c.observe(provider, strconv.Itoa(response.StatusCode), start)
+	if err != nil {
+		return response.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > c.options.MaxBodyBytes {
+		return response.StatusCode, nil, errBodyTooLarge
+	}
+	return response.StatusCode, body, nil
+}
+
+// checkResponse classifies GetJSON outcome into provider failure, nil means successful response.
+// statusKind classifies non-200 statuses and lets providers apply their own error semantics.
+func (c *HTTPClient) checkResponse(provider string, status int, err error, statusKind func(int) ErrorKind) *errors.AppError {
+	if err != nil {
+		return c.failure(provider, requestErrorKind(err), err)
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+	return c.failure(provider, statusKind(status), fmt.Errorf("bad API response: status %d", status))
+}
+
+// failure builds classified provider failure and counts it in metrics
+func (c *HTTPClient) failure(provider string, kind ErrorKind, err error) *errors.AppError {
+	if c.metrics != nil {
+		c.metrics.IncError(provider, string(kind))
+	}
+	return providerFailure(provider, kind, err)
+}
+
+func (c *HTTPClient) observe(provider, status string, start time.Time) {
+	if c.metrics != nil {
+		c.metrics.ObserveRequestDuration(provider, status, time.Since(start).Seconds())
+	}
+}
+
+// backoff returns exponential delay with equal jitter, so concurrent retries don't hit upstream at once
+func (c *HTTPClient) backoff(attempt int) time.Duration {
+	delay := c.options.RetryBaseDelay << attempt
+	if delay <= 0 {
+		return 0
+	}
+	// #nosec G404 -- not used for cryptographic purposes
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func isRetryable(status int, err error) bool {
+	if err != nil {
+		return !stdErrors.Is(err, errBodyTooLarge)
+	}
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func buildURL(endpoint string, query url.Values) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+	values := parsed.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	parsed.RawQuery = values.Encode()
+	return parsed.String(), nil
+}
+
+// redactURL drops request URL from transport errors, as query contains API keys
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if stdErrors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"weatherApi/internal/dto"
+
+	"weatherApi/internal/common/errors"
+)
+
+type MockAirQualityProvider struct {
+	next                   AirQualityProviderInterface
+	Response               *dto.AirQualityResponse
+	Err                    *errors.AppError
+	GetAirQualityCallCount int
+}
+
+func (m *MockAirQualityProvider) GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError) {
+	m.GetAirQualityCallCount++
+	if m.Err != nil {
+		if ShouldFallback(m.Err) && m.next != nil {
+			return m.next.GetAirQuality(ctx, location)
+		}
+		return nil, m.Err
+	}
+	return m.Response, nil
+}
+
+func (m *MockAirQualityProvider) Name() string {
+	return "MockAirQualityProvider"
+}
+
+func (m *MockAirQualityProvider) SetNext(next AirQualityProviderInterface) {
+	m.next = next
+}
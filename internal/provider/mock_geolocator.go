@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"context"
+	"weatherApi/internal/dto"
+
+	"weatherApi/internal/common/errors"
+)
+
+type MockGeoLocator struct {
+	Location    *dto.Location
+	Err         *errors.AppError
+	RequestedIP []string
+}
+
+func (m *MockGeoLocator) Locate(ctx context.Context, ip string) (*dto.Location, *errors.AppError) {
+	m.RequestedIP = append(m.RequestedIP, ip)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Location, nil
+}
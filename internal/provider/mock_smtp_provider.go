@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/dto"
 )
 
@@ -8,6 +9,9 @@ type MockSMTPClient struct {
 	SentConfirmations []dto.ConfirmationEmailTask
 	SentWeatherData   []dto.WeatherResponse
 	SentUserData      []dto.UserData
+	SentAirQuality    []*dto.AirQualityResponse
+	SentPrivacyLinks  []dto.PrivacyLinkTask
+	SentDigests       []dto.WeatherDigestTask
 }
 
 func (m *MockSMTPClient) SendConfirmationToken(email, token, city string) error {
@@ -19,8 +23,33 @@ func (m *MockSMTPClient) SendConfirmationToken(email, token, city string) error
 	return nil
 }
 
-func (m *MockSMTPClient) SendSubscriptionWeatherData(data *dto.WeatherResponse, user *dto.UserData) error {
+func (m *MockSMTPClient) SendEmailChangeConfirmation(email, token, city string) error {
+	m.SentConfirmations = append(m.SentConfirmations, dto.ConfirmationEmailTask{
+		Email:       email,
+		Token:       token,
+		City:        city,
+		EmailChange: true,
+	})
+	return nil
+}
+
+func (m *MockSMTPClient) SendSubscriptionWeatherData(data *dto.WeatherResponse, airQuality *dto.AirQualityResponse, user *dto.UserData) error {
 	m.SentWeatherData = append(m.SentWeatherData, *data)
+	m.SentAirQuality = append(m.SentAirQuality, airQuality)
 	m.SentUserData = append(m.SentUserData, *user)
 	return nil
 }
+
+func (m *MockSMTPClient) SendDigestWeatherData(task *dto.WeatherDigestTask) error {
+	m.SentDigests = append(m.SentDigests, *task)
+	return nil
+}
+
+func (m *MockSMTPClient) SendPrivacyLink(email, token string, action constants.PrivacyAction) error {
+	m.SentPrivacyLinks = append(m.SentPrivacyLinks, dto.PrivacyLinkTask{
+		Email:  email,
+		Token:  token,
+		Action: action,
+	})
+	return nil
+}
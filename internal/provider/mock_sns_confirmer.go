@@ -0,0 +1,19 @@
+package provider
+
+import "context"
+
+type MockSNSConfirmer struct {
+	Confirmed []string
+	Err       error
+}
+
+func (m *MockSNSConfirmer) Confirm(ctx context.Context, subscribeURL string) error {
+	if err := ValidateSubscribeURL(subscribeURL); err != nil {
+		return err
+	}
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Confirmed = append(m.Confirmed, subscribeURL)
+	return nil
+}
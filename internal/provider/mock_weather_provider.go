@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"sync"
 	"weatherApi/internal/dto"
 	serviceErrors "weatherApi/internal/service/weather/errors"
 
@@ -9,18 +10,30 @@ import (
 )
 
 type MockProvider struct {
+	mu                  sync.Mutex
 	next                WeatherProviderInterface
 	Response            *dto.WeatherResponse
 	Err                 *errors.AppError
 	GetWeatherCallCount int
+	LastLocation        dto.Location
+	LastLang            string
 }
 
-func (m *MockProvider) GetWeather(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
+func (m *MockProvider) GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	result, err := m.Fetch(ctx, location, lang)
+	if ShouldFallback(err) && m.next != nil {
+		return m.Next(ctx, location, lang)
+	}
+	return result, err
+}
+
+func (m *MockProvider) Fetch(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetWeatherCallCount++
+	m.LastLocation = location
+	m.LastLang = lang
 	if m.Err != nil {
-		if m.Err.Code == 500 && m.next != nil {
-			return m.Next(ctx, city)
-		}
 		return nil, m.Err
 	}
 	return m.Response, nil
@@ -34,9 +47,9 @@ func (m *MockProvider) SetNext(next WeatherProviderInterface) {
 	m.next = next
 }
 
-func (m *MockProvider) Next(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
+func (m *MockProvider) Next(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
 	if m.next != nil {
-		return m.next.GetWeather(ctx, city)
+		return m.next.GetWeather(ctx, location, lang)
 	}
 	return nil, serviceErrors.ErrInternalServerError
 }
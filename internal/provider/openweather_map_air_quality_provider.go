@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+
+	"weatherApi/internal/common/errors"
+)
+
+var _ AirQualityProviderInterface = (*OpenWeatherMapAirQualityProvider)(nil)
+
+// OpenWeatherMapAirQualityProvider uses Air Pollution API, which accepts only coordinates,
+// so city locations are resolved with Geocoding API first.
+type OpenWeatherMapAirQualityProvider struct {
+	log         *logger.Logger
+	next        AirQualityProviderInterface
+	httpClient  *HTTPClient
+	apiKey      string
+	url         string
+	geocoderUrl string
+}
+
+func NewOpenWeatherMapAirQualityProvider(log *logger.Logger, apikey, url, geocoderUrl string) *OpenWeatherMapAirQualityProvider {
+	return &OpenWeatherMapAirQualityProvider{
+		log:         log,
+		httpClient:  defaultHTTPClient,
+		apiKey:      apikey,
+		url:         url,
+		geocoderUrl: geocoderUrl,
+	}
+}
+
+func (w *OpenWeatherMapAirQualityProvider) WithHTTPClient(client *HTTPClient) *OpenWeatherMapAirQualityProvider {
+	w.httpClient = client
+	return w
+}
+
+func (w *OpenWeatherMapAirQualityProvider) Name() string {
+	return "OpenWeatherMapAirQuality"
+}
+
+func (w *OpenWeatherMapAirQualityProvider) SetNext(next AirQualityProviderInterface) {
+	w.next = next
+}
+
+func (w *OpenWeatherMapAirQualityProvider) GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError) {
+	log := w.log.FromContext(ctx)
+
+	coordinates := location.Coordinates
+	if coordinates == nil {
+		resolved, failure := w.geocode(ctx, location.City)
+		if failure != nil {
+			if !ShouldFallback(failure) {
+				return nil, failure
+			}
+			return TryNextAirQuality(log, ctx, w, w.next, location, failure)
+		}
+		coordinates = resolved
+	}
+
+	var airPollutionResponse dto.OpenweatherMapAirPollutionResponse
+	query := url.Values{}
+	query.Set("lat", formatCoordinate(coordinates.Lat))
+	query.Set("lon", formatCoordinate(coordinates.Lon))
+	query.Set("appid", w.apiKey)
+	if failure := w.getJSON(ctx, w.url, query, &airPollutionResponse); failure != nil {
+		return TryNextAirQuality(log, ctx, w, w.next, location, failure)
+	}
+	if len(airPollutionResponse.List) == 0 {
+		return TryNextAirQuality(log, ctx, w, w.next, location,
+			w.httpClient.failure(w.Name(), ErrorKindDecode, fmt.Errorf("empty air pollution response")))
+	}
+
+	data := airPollutionResponse.List[0].Components
+	return newAirQualityResponse(data.PM25, data.PM10, data.O3, data.NO2), nil
+}
+
+// geocode resolves city coordinates, unknown city is reported as not found failure
+func (w *OpenWeatherMapAirQualityProvider) geocode(ctx context.Context, city string) (*dto.Coordinates, *errors.AppError) {
+	var geocodingResponse dto.OpenweatherMapGeocodingResponse
+	query := url.Values{}
+	query.Set("q", city)
+	query.Set("limit", "1")
+	query.Set("appid", w.apiKey)
+	if failure := w.getJSON(ctx, w.geocoderUrl, query, &geocodingResponse); failure != nil {
+		return nil, failure
+	}
+	if len(geocodingResponse) == 0 {
+		return nil, w.httpClient.failure(w.Name(), ErrorKindNotFound, fmt.Errorf("geocoding found no %q", city))
+	}
+	return &dto.Coordinates{Lat: geocodingResponse[0].Lat, Lon: geocodingResponse[0].Lon}, nil
+}
+
+func (w *OpenWeatherMapAirQualityProvider) getJSON(ctx context.Context, endpoint string, query url.Values, target any) *errors.AppError {
+	status, err := w.httpClient.GetJSON(ctx, w.Name(), endpoint, query, target)
+	return w.httpClient.checkResponse(w.Name(), status, err, statusErrorKind)
+}
@@ -2,106 +2,122 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
 	"time"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
 
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/common/errors"
 	serviceErrors "weatherApi/internal/service/weather/errors"
 )
 
-var _ WeatherProviderInterface = (*WeatherApiProvider)(nil)
+var _ WeatherProviderInterface = (*OpenWeatherMapApiProvider)(nil)
+
+const OpenWeatherMapName = "OpenWeatherMap"
 
 type OpenWeatherMapApiProvider struct {
-	log    *logger.Logger
-	next   WeatherProviderInterface
-	apiKey string
-	url    string
+	log        *logger.Logger
+	next       WeatherProviderInterface
+	httpClient *HTTPClient
+	apiKey     string
+	url        string
 }
 
 func NewOpenWeatherApiProvider(log *logger.Logger, apikey, url string) *OpenWeatherMapApiProvider {
 	return &OpenWeatherMapApiProvider{
-		log:    log,
-		apiKey: apikey,
-		url:    url,
+		log:        log,
+		httpClient: defaultHTTPClient,
+		apiKey:     apikey,
+		url:        url,
 	}
 }
 
+func (w *OpenWeatherMapApiProvider) WithHTTPClient(client *HTTPClient) *OpenWeatherMapApiProvider {
+	w.httpClient = client
+	return w
+}
+
 func (w *OpenWeatherMapApiProvider) Name() string {
-	return "OpenWeatherMap"
+	return OpenWeatherMapName
 }
 
 func (w *OpenWeatherMapApiProvider) SetNext(next WeatherProviderInterface) {
 	w.next = next
 }
 
-func (w *OpenWeatherMapApiProvider) Next(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
+func (w *OpenWeatherMapApiProvider) Next(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
 	log := w.log.FromContext(ctx)
 	if w.next != nil {
-		return w.next.GetWeather(ctx, city)
+		return w.next.GetWeather(ctx, location, lang)
 	}
 	log.Error().Msg("OpenWeatherMapApiProvider: no providers left in chain!")
 	return nil, serviceErrors.ErrInternalServerError
 }
 
-func (w *OpenWeatherMapApiProvider) GetWeather(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
-	var openWeatherMapResponse dto.OpenweatherMapAPIResponse
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	log := w.log.FromContext(ctx)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
-		fmt.Sprintf("%s?q=%s&APPID=%s&units=metric", w.url, city, w.apiKey),
-		nil,
-	)
-	if err != nil {
-		return TryNext(log, ctx, w, w.next, city, fmt.Errorf("request creation failed: %w", err))
+func (w *OpenWeatherMapApiProvider) GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	result, err := w.Fetch(ctx, location, lang)
+	if ShouldFallback(err) {
+		return TryNext(w.log.FromContext(ctx), ctx, w, w.next, location, lang, err)
 	}
+	return result, err
+}
 
-	response, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return TryNext(log, ctx, w, w.next, city, fmt.Errorf("HTTP request failed: %w", err))
+func (w *OpenWeatherMapApiProvider) Fetch(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	var openWeatherMapResponse dto.OpenweatherMapAPIResponse
+	query := w.locationQuery(location)
+	query.Set("APPID", w.apiKey)
+	query.Set("units", "metric")
+	query.Set("lang", lang)
+
+	status, err := w.httpClient.GetJSON(ctx, w.Name(), w.url, query, &openWeatherMapResponse)
+	if failure := w.httpClient.checkResponse(w.Name(), status, err, statusErrorKind); failure != nil {
+		return nil, failure
 	}
 
-	defer func() {
-		if err := response.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
-		}
-	}()
+	return openWeatherMapToWeatherResponse(&openWeatherMapResponse, w.Name()), nil
+}
 
-	if badResponse := w.checkApiResponse(response); badResponse != nil {
-		if badResponse.Code == 500 {
-			return TryNext(log, ctx, w, w.next, city, fmt.Errorf("bad API response: %w", err))
-		}
-		return nil, badResponse
+func openWeatherMapToWeatherResponse(data *dto.OpenweatherMapAPIResponse, providerName string) *dto.WeatherResponse {
+	result := &dto.WeatherResponse{
+		Temperature:   data.Main.Temperature,
+		FeelsLike:     data.Main.FeelsLike,
+		Humidity:      data.Main.Humidity,
+		Pressure:      data.Main.Pressure,
+		WindSpeed:     data.Wind.Speed,
+		WindDirection: data.Wind.Deg,
+		Visibility:    float64(data.Visibility) / 1000,
+		Condition:     constants.ConditionUnknown,
+		Units:         constants.UnitsMetric,
+		Provider:      providerName,
 	}
 
-	if err := json.NewDecoder(response.Body).Decode(&openWeatherMapResponse); err != nil {
-		return TryNext(log, ctx, w, w.next, city, fmt.Errorf("failed to decode response: %w", err))
+	if len(data.Weather) > 0 {
+		result.Description = data.Weather[0].Description
+		result.Condition = openWeatherMapCondition(data.Weather[0].ID)
+		if data.Weather[0].Icon != "" {
+			result.Icon = fmt.Sprintf("https://openweathermap.org/img/wn/%s@2x.png", data.Weather[0].Icon)
+		}
 	}
-
-	var description string
-
-	if len(openWeatherMapResponse.Weather) > 0 {
-		description = openWeatherMapResponse.Weather[0].Description
+	if data.Sys.Sunrise > 0 {
+		sunrise := time.Unix(data.Sys.Sunrise, 0).UTC()
+		result.Sunrise = &sunrise
 	}
-
-	return &dto.WeatherResponse{
-		Temperature: openWeatherMapResponse.Main.Temperature,
-		Humidity:    openWeatherMapResponse.Main.Humidity,
-		Description: description,
-	}, nil
+	if data.Sys.Sunset > 0 {
+		sunset := time.Unix(data.Sys.Sunset, 0).UTC()
+		result.Sunset = &sunset
+	}
+	return result
 }
 
-func (w *OpenWeatherMapApiProvider) checkApiResponse(response *http.Response) *errors.AppError {
-	switch response.StatusCode {
-	case http.StatusOK:
-		return nil
-	case http.StatusNotFound:
-		return serviceErrors.ErrCityNotFound
-	default:
-		return serviceErrors.ErrInternalServerError
+func (w *OpenWeatherMapApiProvider) locationQuery(location dto.Location) url.Values {
+	query := url.Values{}
+	if location.HasCoordinates() {
+		query.Set("lat", formatCoordinate(location.Coordinates.Lat))
+		query.Set("lon", formatCoordinate(location.Coordinates.Lon))
+		return query
 	}
+	query.Set("q", location.City)
+	return query
 }
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"weatherApi/internal/common/errors"
+
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+// ErrorKind classifies upstream provider failures, it drives fallback decisions,
+// metrics labels and status code returned to API client
+type ErrorKind string
+
+const (
+	ErrorKindAuth        ErrorKind = "auth"
+	ErrorKindQuota       ErrorKind = "quota"
+	ErrorKindNotFound    ErrorKind = "not_found"
+	ErrorKindUnavailable ErrorKind = "unavailable"
+	ErrorKindDecode      ErrorKind = "decode"
+)
+
+// Fallback reports whether the next provider in chain is worth trying,
+// location unknown to one provider is not expected to be found by others
+func (k ErrorKind) Fallback() bool {
+	return k != ErrorKindNotFound
+}
+
+func (k ErrorKind) clientError() *errors.AppError {
+	switch k {
+	case ErrorKindNotFound:
+		return serviceErrors.ErrCityNotFound
+	case ErrorKindAuth, ErrorKindDecode:
+		return serviceErrors.ErrProviderBadResponse
+	default:
+		return serviceErrors.ErrProviderUnavailable
+	}
+}
+
+type ProviderError struct {
+	Provider string
+	Kind     ErrorKind
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s %s error: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// providerFailure wraps classified upstream failure into app error with client visible status of its kind
+func providerFailure(provider string, kind ErrorKind, err error) *errors.AppError {
+	clientErr := kind.clientError()
+	return errors.New(clientErr.Code, clientErr.Message, &ProviderError{Provider: provider, Kind: kind, Err: err})
+}
+
+// ShouldFallback reports whether provider chain should move on after err,
+// errors not produced by providers fall back only when they are server failures
+func ShouldFallback(err *errors.AppError) bool {
+	if err == nil {
+		return false
+	}
+	var providerErr *ProviderError
+	if stdErrors.As(err, &providerErr) {
+		return providerErr.Kind.Fallback()
+	}
+	return err.Code >= http.StatusInternalServerError
+}
+
+// ClientError maps the last failure of exhausted provider chain to error returned to API client
+func ClientError(err error) *errors.AppError {
+	var providerErr *ProviderError
+	if stdErrors.As(err, &providerErr) {
+		return providerErr.Kind.clientError()
+	}
+	return serviceErrors.ErrProviderUnavailable
+}
+
+// statusErrorKind classifies non-200 upstream status, providers with own semantics wrap it
+func statusErrorKind(status int) ErrorKind {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorKindAuth
+	case http.StatusTooManyRequests:
+		return ErrorKindQuota
+	case http.StatusNotFound:
+		return ErrorKindNotFound
+	default:
+		return ErrorKindUnavailable
+	}
+}
+
+// requestErrorKind classifies HTTPClient errors, unusable responses are told apart from unreachable upstream
+func requestErrorKind(err error) ErrorKind {
+	if stdErrors.Is(err, errInvalidResponse) {
+		return ErrorKindDecode
+	}
+	return ErrorKindUnavailable
+}
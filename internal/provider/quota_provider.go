@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"weatherApi/internal/common/errors"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+)
+
+var errQuotaExhausted = fmt.Errorf("daily quota is exhausted")
+
+type QuotaGuardInterface interface {
+	Acquire(ctx context.Context, account string) bool
+}
+
+type quotaAccountKey struct{}
+
+type quotaAccount struct {
+	guard   QuotaGuardInterface
+	account string
+}
+
+// withQuotaAccount marks requests made for provider account, so HTTPClient counts their retries against its quota
+func withQuotaAccount(ctx context.Context, guard QuotaGuardInterface, account string) context.Context {
+	return context.WithValue(ctx, quotaAccountKey{}, quotaAccount{guard: guard, account: account})
+}
+
+// acquireRetry counts retry against quota of account request is made for, requests of not limited providers
+// are always allowed
+func acquireRetry(ctx context.Context) bool {
+	limited, ok := ctx.Value(quotaAccountKey{}).(quotaAccount)
+	if !ok {
+		return true
+	}
+	return limited.guard.Acquire(ctx, limited.account)
+}
+
+var _ WeatherProviderInterface = (*QuotaLimitedProvider)(nil)
+
+// QuotaLimitedProvider counts calls to wrapped provider and skips it to the next one in chain
+// once daily quota of provider account is exhausted, retries of failed calls are counted as well
+type QuotaLimitedProvider struct {
+	log     *logger.Logger
+	inner   WeatherProviderInterface
+	next    WeatherProviderInterface
+	guard   QuotaGuardInterface
+	account string
+}
+
+func NewQuotaLimitedProvider(log *logger.Logger, inner WeatherProviderInterface, guard QuotaGuardInterface, account string) *QuotaLimitedProvider {
+	return &QuotaLimitedProvider{log: log, inner: inner, guard: guard, account: account}
+}
+
+func (q *QuotaLimitedProvider) Name() string {
+	return q.inner.Name()
+}
+
+func (q *QuotaLimitedProvider) SetNext(next WeatherProviderInterface) {
+	q.next = next
+	q.inner.SetNext(next)
+}
+
+func (q *QuotaLimitedProvider) GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	if !q.guard.Acquire(ctx, q.account) {
+		return TryNext(q.log.FromContext(ctx), ctx, q, q.next, location, lang, providerFailure(q.Name(), ErrorKindQuota, errQuotaExhausted))
+	}
+	return q.inner.GetWeather(withQuotaAccount(ctx, q.guard, q.account), location, lang)
+}
+
+func (q *QuotaLimitedProvider) Fetch(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	if !q.guard.Acquire(ctx, q.account) {
+		return nil, providerFailure(q.Name(), ErrorKindQuota, errQuotaExhausted)
+	}
+	return q.inner.Fetch(withQuotaAccount(ctx, q.guard, q.account), location, lang)
+}
+
+var _ AirQualityProviderInterface = (*QuotaLimitedAirQualityProvider)(nil)
+
+// QuotaLimitedAirQualityProvider is QuotaLimitedProvider for air quality chain,
+// calls are counted against the same provider account as weather ones
+type QuotaLimitedAirQualityProvider struct {
+	log     *logger.Logger
+	inner   AirQualityProviderInterface
+	next    AirQualityProviderInterface
+	guard   QuotaGuardInterface
+	account string
+}
+
+func NewQuotaLimitedAirQualityProvider(
+	log *logger.Logger,
+	inner AirQualityProviderInterface,
+	guard QuotaGuardInterface,
+	account string,
+) *QuotaLimitedAirQualityProvider {
+	return &QuotaLimitedAirQualityProvider{log: log, inner: inner, guard: guard, account: account}
+}
+
+func (q *QuotaLimitedAirQualityProvider) Name() string {
+	return q.inner.Name()
+}
+
+func (q *QuotaLimitedAirQualityProvider) SetNext(next AirQualityProviderInterface) {
+	q.next = next
+	q.inner.SetNext(next)
+}
+
+func (q *QuotaLimitedAirQualityProvider) GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError) {
+	if !q.guard.Acquire(ctx, q.account) {
+		return TryNextAirQuality(q.log.FromContext(ctx), ctx, q, q.next, location, providerFailure(q.Name(), ErrorKindQuota, errQuotaExhausted))
+	}
+	return q.inner.GetAirQuality(withQuotaAccount(ctx, q.guard, q.account), location)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/dto"
 
 	"gopkg.in/gomail.v2"
@@ -9,9 +10,15 @@ import (
 
 type SMTPClientInterface interface {
 	SendConfirmationToken(to, token, city string) error
-	SendSubscriptionWeatherData(data *dto.WeatherResponse, user *dto.UserData) error
+	SendEmailChangeConfirmation(to, token, city string) error
+	SendSubscriptionWeatherData(data *dto.WeatherResponse, airQuality *dto.AirQualityResponse, user *dto.UserData) error
+	SendDigestWeatherData(task *dto.WeatherDigestTask) error
+	SendPrivacyLink(to, token string, action constants.PrivacyAction) error
 }
 
+// snoozeDays is pause offered by snooze link of weather emails, manage page asks to confirm it
+const snoozeDays = 7
+
 type SMTPClient struct {
 	host      string
 	port      int
@@ -59,64 +66,159 @@ func (c *SMTPClient) SendConfirmationToken(to, token, city string) error {
 	return d.DialAndSend(m)
 }
 
-func (c *SMTPClient) SendSubscriptionWeatherData(data *dto.WeatherResponse, user *dto.UserData) error {
+// SendEmailChangeConfirmation asks new address to confirm it, subscription moves to it only after the link is opened
+func (c *SMTPClient) SendEmailChangeConfirmation(to, token, city string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", c.login)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", "Confirm your new email for weather updates")
+	confirmationURL := fmt.Sprintf("%s/confirm/%s", c.serverUrl, token)
+	htmlBody := fmt.Sprintf(`
+		<html>
+			<body style="font-family: Arial, sans-serif; color: #333;">
+				<h2>Hello!</h2>
+				<p>You requested to receive weather updates for <strong>%s</strong> at this address.</p>
+				<p>Updates keep going to your previous address until you confirm this one:</p>
+				<a href="%s"
+				   style="display:inline-block; padding:10px 20px; background-color:#28a745; color:white; text-decoration:none; border-radius:5px;">
+					Confirm New Email
+				</a>
+				<p>If you did not request this, you can ignore this email.</p>
+				<br/>
+				<small>Weather Service Team</small>
+			</body>
+		</html>`, city, confirmationURL)
+
+	m.SetBody("text/html", htmlBody)
+
+	d := gomail.NewDialer(c.host, c.port, c.login, c.password)
+
+	return d.DialAndSend(m)
+}
+
+// SendSubscriptionWeatherData sends weather update, airQuality is optional and rendered only when present
+func (c *SMTPClient) SendSubscriptionWeatherData(data *dto.WeatherResponse, airQuality *dto.AirQualityResponse, user *dto.UserData) error {
+	labels, lang := labelsFor(user.Lang)
+	alert := user.IsAirQualityAlert(airQuality)
+	subject := "Weather subscription confimation"
+	if alert {
+		subject = labels.AirQualityAlertSubject
+	}
+
 	m := gomail.NewMessage()
 	m.SetHeader("From", c.login)
 	m.SetHeader("To", user.Email)
-	m.SetHeader("Subject", "Weather subscription confimation")
-	unsubscribeUrl := fmt.Sprintf("%s/unsubscribe/%s", c.serverUrl, user.Token)
+	m.SetHeader("Subject", subject)
+	// RFC 8058 one-click unsubscribe, mail client POSTs to the URL without opening it
+	unsubscribeURL := fmt.Sprintf("%s/unsubscribe/%s", c.serverUrl, user.UnsubscribeToken)
+	m.SetHeader("List-Unsubscribe", "<"+unsubscribeURL+">")
+	m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	manageURL := fmt.Sprintf("%s/manage/%s", c.serverUrl, user.ManageToken)
+	htmlBody, err := renderTemplate(weatherEmailTemplate, weatherEmailData{
+		Weather:        data,
+		AirQuality:     airQuality,
+		AirAlert:       alert,
+		Labels:         labels,
+		Units:          unitSymbolsFor(data.Units),
+		Lang:           lang,
+		UnsubscribeURL: unsubscribeURL,
+		ManageURL:      manageURL,
+		SnoozeURL:      fmt.Sprintf("%s?snooze=%d", manageURL, snoozeDays),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render weather email: %w", err)
+	}
+
+	m.SetBody("text/html", htmlBody)
+
+	d := gomail.NewDialer(c.host, c.port, c.login, c.password)
+
+	return d.DialAndSend(m)
+}
+
+// SendDigestWeatherData sends weather of several cities in one email, each section carries
+// links of its own subscription
+func (c *SMTPClient) SendDigestWeatherData(task *dto.WeatherDigestTask) error {
+	labels, lang := labelsFor(task.Lang)
+	subject := labels.DigestSubject
+
+	sections := make([]digestSectionData, len(task.Sections))
+	for i := range task.Sections {
+		section := &task.Sections[i]
+		sectionLabels, _ := labelsFor(section.User.Lang)
+		alert := section.User.IsAirQualityAlert(section.AirQuality)
+		if alert {
+			subject = labels.AirQualityAlertSubject
+		}
+		var airQuality *dto.AirQualityResponse
+		if section.User.IncludeAirQuality || alert {
+			airQuality = section.AirQuality
+		}
+		manageURL := fmt.Sprintf("%s/manage/%s", c.serverUrl, section.User.ManageToken)
+		sections[i] = digestSectionData{
+			City:           section.City,
+			Weather:        &section.Weather,
+			AirQuality:     airQuality,
+			AirAlert:       alert,
+			Labels:         sectionLabels,
+			Units:          unitSymbolsFor(section.Weather.Units),
+			UnsubscribeURL: fmt.Sprintf("%s/unsubscribe/%s", c.serverUrl, section.User.UnsubscribeToken),
+			ManageURL:      manageURL,
+			SnoozeURL:      fmt.Sprintf("%s?snooze=%d", manageURL, snoozeDays),
+		}
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", c.login)
+	m.SetHeader("To", task.Email)
+	m.SetHeader("Subject", subject)
+	// one-click unsubscribe removes a single subscription, so it's offered only when digest has one city
+	if len(sections) == 1 {
+		m.SetHeader("List-Unsubscribe", "<"+sections[0].UnsubscribeURL+">")
+		m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	htmlBody, err := renderTemplate(digestEmailTemplate, digestEmailData{
+		Sections: sections,
+		Labels:   labels,
+		Lang:     lang,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render digest email: %w", err)
+	}
+
+	m.SetBody("text/html", htmlBody)
+
+	d := gomail.NewDialer(c.host, c.port, c.login, c.password)
+
+	return d.DialAndSend(m)
+}
+
+// SendPrivacyLink sends link verifying data export or erasure request
+func (c *SMTPClient) SendPrivacyLink(to, token string, action constants.PrivacyAction) error {
+	subject, intro, button := "Your weather subscription data", "You requested a copy of data we keep about you.", "Download my data"
+	if action == constants.PrivacyErase {
+		subject, intro, button = "Delete your weather subscription data", "You requested to delete all data we keep about you, including subscriptions.", "Delete my data"
+	}
+	m := gomail.NewMessage()
+	m.SetHeader("From", c.login)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	privacyURL := fmt.Sprintf("%s/privacy/%s/%s", c.serverUrl, action, token)
 	htmlBody := fmt.Sprintf(`
-<!DOCTYPE html>
-<html lang="en">
-<head>
-  <meta charset="UTF-8">
-  <title>Weather Forecast</title>
-  <style>
-    body {
-      font-family: Arial, sans-serif;
-      background-color: #f4f6f8;
-      padding: 20px;
-      color: #333;
-    }
-    .container {
-      background-color: #ffffff;
-      border-radius: 8px;
-      padding: 24px;
-      max-width: 500px;
-      margin: auto;
-      box-shadow: 0 2px 4px rgba(0,0,0,0.1);
-    }
-    .heading {
-      font-size: 22px;
-      font-weight: bold;
-      margin-bottom: 16px;
-      text-align: center;
-    }
-    .info {
-      font-size: 16px;
-      margin-bottom: 10px;
-    }
-    .footer {
-      margin-top: 20px;
-      font-size: 12px;
-      color: #888;
-      text-align: center;
-    }
-  </style>
-</head>
-<body>
-  <div class="container">
-    <div class="heading">🌤️ Weather Update</div>
-    <div class="info">🌡️ <strong>Temperature:</strong> %.1f°C</div>
-    <div class="info">💧 <strong>Humidity:</strong> %d%%</div>
-    <div class="info">📖 <strong>Conditions:</strong> %d</div>
-    <div class="footer">You are receiving this weather update because you subscribed to weather notifications.</div>
-    <div class="unsubscribe">
-      👉 <a href="%s">Unsubscribe from future updates</a>
-    </div>  </div>
-</body>
-</html>
-`, data.Temperature, data.Humidity, data.Humidity, unsubscribeUrl)
+		<html>
+			<body style="font-family: Arial, sans-serif; color: #333;">
+				<h2>Hello!</h2>
+				<p>%s</p>
+				<p>Please verify it is you by clicking the button below:</p>
+				<a href="%s"
+				   style="display:inline-block; padding:10px 20px; background-color:#1976d2; color:white; text-decoration:none; border-radius:5px;">
+					%s
+				</a>
+				<p>If you did not request this, you can ignore this email.</p>
+				<br/>
+				<small>Weather Service Team</small>
+			</body>
+		</html>`, intro, privacyURL, button)
 
 	m.SetBody("text/html", htmlBody)
 
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// snsHostPattern matches regional SNS endpoints, SubscribeURL is only fetched from them
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+var ErrInvalidSubscribeURL = errors.New("subscribe URL is not an SNS endpoint")
+
+type SNSConfirmerInterface interface {
+	// Confirm visits SubscribeURL of SNS subscription confirmation, so topic starts delivering notifications
+	Confirm(ctx context.Context, subscribeURL string) error
+}
+
+var _ SNSConfirmerInterface = (*HTTPSNSConfirmer)(nil)
+
+type HTTPSNSConfirmer struct {
+	client *http.Client
+}
+
+// NewHTTPSNSConfirmer creates confirmer using copy of client, nil client gets default one with short timeout.
+// Redirects are never followed, only SubscribeURL host is validated, so 3xx response fails confirmation
+func NewHTTPSNSConfirmer(client *http.Client) *HTTPSNSConfirmer {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	} else {
+		copied := *client
+		client = &copied
+	}
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &HTTPSNSConfirmer{client: client}
+}
+
+func (c *HTTPSNSConfirmer) Confirm(ctx context.Context, subscribeURL string) error {
+	if err := ValidateSubscribeURL(subscribeURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	response, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("SNS subscription confirmation failed: %w", redactURL(err))
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad SNS confirmation response: status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// ValidateSubscribeURL rejects SubscribeURL not pointing to https SNS endpoint, payload is unauthenticated
+// besides webhook secret and must not make the service request arbitrary hosts
+func ValidateSubscribeURL(subscribeURL string) error {
+	parsed, err := url.Parse(subscribeURL)
+	if err != nil || parsed.Scheme != "https" || parsed.User != nil || parsed.Port() != "" ||
+		!snsHostPattern.MatchString(parsed.Hostname()) {
+		return ErrInvalidSubscribeURL
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+
+	"weatherApi/internal/common/errors"
+)
+
+var _ AirQualityProviderInterface = (*WeatherApiAirQualityProvider)(nil)
+
+// WeatherApiAirQualityProvider uses the same current.json endpoint as weather provider with aqi=yes
+type WeatherApiAirQualityProvider struct {
+	log        *logger.Logger
+	next       AirQualityProviderInterface
+	httpClient *HTTPClient
+	apiKey     string
+	url        string
+}
+
+func NewWeatherApiAirQualityProvider(log *logger.Logger, apikey, url string) *WeatherApiAirQualityProvider {
+	return &WeatherApiAirQualityProvider{
+		log:        log,
+		httpClient: defaultHTTPClient,
+		apiKey:     apikey,
+		url:        url,
+	}
+}
+
+func (w *WeatherApiAirQualityProvider) WithHTTPClient(client *HTTPClient) *WeatherApiAirQualityProvider {
+	w.httpClient = client
+	return w
+}
+
+func (w *WeatherApiAirQualityProvider) Name() string {
+	return "WeatherApiAirQuality"
+}
+
+func (w *WeatherApiAirQualityProvider) SetNext(next AirQualityProviderInterface) {
+	w.next = next
+}
+
+func (w *WeatherApiAirQualityProvider) GetAirQuality(ctx context.Context, location dto.Location) (*dto.AirQualityResponse, *errors.AppError) {
+	log := w.log.FromContext(ctx)
+
+	var airQualityResponse dto.WeatherAPIAirQualityResponse
+	query := url.Values{}
+	query.Set("key", w.apiKey)
+	query.Set("q", weatherApiLocationQuery(location))
+	query.Set("aqi", "yes")
+
+	status, err := w.httpClient.GetJSON(ctx, w.Name(), w.url, query, &airQualityResponse)
+	if failure := w.httpClient.checkResponse(w.Name(), status, err, weatherApiErrorKind); failure != nil {
+		if !ShouldFallback(failure) {
+			return nil, failure
+		}
+		return TryNextAirQuality(log, ctx, w, w.next, location, failure)
+	}
+
+	data := airQualityResponse.Current.AirQuality
+	return newAirQualityResponse(data.PM25, data.PM10, data.O3, data.NO2), nil
+}
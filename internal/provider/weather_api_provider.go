@@ -2,101 +2,128 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+	"strings"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
 
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/common/errors"
 	serviceErrors "weatherApi/internal/service/weather/errors"
 )
 
 var _ WeatherProviderInterface = (*WeatherApiProvider)(nil)
 
+const WeatherApiName = "WeatherApi"
+
 type WeatherApiProvider struct {
-	log    *logger.Logger
-	next   WeatherProviderInterface
-	apiKey string
-	url    string
+	log        *logger.Logger
+	next       WeatherProviderInterface
+	httpClient *HTTPClient
+	apiKey     string
+	url        string
 }
 
 func NewWeatherApiProvider(log *logger.Logger, apikey, url string) *WeatherApiProvider {
 	return &WeatherApiProvider{
-		log:    log,
-		apiKey: apikey,
-		url:    url,
+		log:        log,
+		httpClient: defaultHTTPClient,
+		apiKey:     apikey,
+		url:        url,
 	}
 }
 
+func (w *WeatherApiProvider) WithHTTPClient(client *HTTPClient) *WeatherApiProvider {
+	w.httpClient = client
+	return w
+}
+
 func (w *WeatherApiProvider) Name() string {
-	return "WeatherApi"
+	return WeatherApiName
 }
 
 func (w *WeatherApiProvider) SetNext(next WeatherProviderInterface) {
 	w.next = next
 }
 
-func (w *WeatherApiProvider) Next(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
+func (w *WeatherApiProvider) Next(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
 	log := w.log.FromContext(ctx)
 	if w.next != nil {
-		return w.next.GetWeather(ctx, city)
+		return w.next.GetWeather(ctx, location, lang)
 	}
 	log.Error().Msg("WeatherApiProvider: no providers left in chain!")
 	return nil, serviceErrors.ErrInternalServerError
 }
 
-func (w *WeatherApiProvider) GetWeather(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError) {
-	log := w.log.FromContext(ctx)
-
-	var weatherResponse dto.WeatherAPIResponse
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
-		fmt.Sprintf("%s?key=%s&q=%s&aqi=no", w.url, w.apiKey, city),
-		nil,
-	)
-	if err != nil {
-		return TryNext(log, ctx, w, w.next, city, err)
+func (w *WeatherApiProvider) GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	result, err := w.Fetch(ctx, location, lang)
+	if ShouldFallback(err) {
+		return TryNext(w.log.FromContext(ctx), ctx, w, w.next, location, lang, err)
 	}
+	return result, err
+}
 
-	response, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return TryNext(log, ctx, w, w.next, city, err)
+func (w *WeatherApiProvider) Fetch(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError) {
+	var weatherResponse dto.WeatherAPIResponse
+	query := url.Values{}
+	query.Set("key", w.apiKey)
+	query.Set("q", weatherApiLocationQuery(location))
+	query.Set("aqi", "no")
+	query.Set("lang", lang)
+
+	status, err := w.httpClient.GetJSON(ctx, w.Name(), w.url, query, &weatherResponse)
+	if failure := w.httpClient.checkResponse(w.Name(), status, err, weatherApiErrorKind); failure != nil {
+		return nil, failure
 	}
 
-	defer func() {
-		if err := response.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
-		}
-	}()
+	return w.toWeatherResponse(&weatherResponse), nil
+}
 
-	if badResponse := w.checkApiResponse(response); badResponse != nil {
-		if badResponse.Code == 500 {
-			return TryNext(log, ctx, w, w.next, city, fmt.Errorf("bad API response: %v", badResponse.Message))
+// toWeatherResponse maps WeatherAPI response, sunrise and sunset are not available in current.json endpoint
+func (w *WeatherApiProvider) toWeatherResponse(data *dto.WeatherAPIResponse) *dto.WeatherResponse {
+	uv := data.Current.UV
+	result := &dto.WeatherResponse{
+		Temperature:   data.Current.Temperature,
+		FeelsLike:     data.Current.FeelsLike,
+		Humidity:      data.Current.Humidity,
+		Pressure:      data.Current.PressureMb,
+		WindSpeed:     data.Current.WindKph / 3.6,
+		WindDirection: data.Current.WindDegree,
+		Visibility:    data.Current.VisKm,
+		UVIndex:       &uv,
+		Condition:     weatherApiCondition(data.Current.Condition.Code),
+		Description:   data.Current.Condition.Text,
+		Units:         constants.UnitsMetric,
+		Provider:      w.Name(),
+	}
+	if icon := data.Current.Condition.Icon; icon != "" {
+		// WeatherAPI returns protocol-relative icon url: //cdn.weatherapi.com/...
+		if strings.HasPrefix(icon, "//") {
+			icon = "https:" + icon
 		}
-		return nil, badResponse
+		result.Icon = icon
 	}
+	return result
+}
 
-	if err := json.NewDecoder(response.Body).Decode(&weatherResponse); err != nil {
-		return TryNext(log, ctx, w, w.next, city, fmt.Errorf("failed to decode response: %w", err))
+// weatherApiLocationQuery builds q parameter, WeatherAPI accepts both city name and "lat,lon" pair in it
+func weatherApiLocationQuery(location dto.Location) string {
+	if location.HasCoordinates() {
+		return formatCoordinate(location.Coordinates.Lat) + "," + formatCoordinate(location.Coordinates.Lon)
 	}
-
-	return &dto.WeatherResponse{
-		Temperature: weatherResponse.Current.Temperature,
-		Humidity:    weatherResponse.Current.Humidity,
-		Description: weatherResponse.Current.Condition.Text,
-	}, nil
+	return location.City
 }
 
-func (w *WeatherApiProvider) checkApiResponse(response *http.Response) *errors.AppError {
-	switch response.StatusCode {
-	case http.StatusOK:
-		return nil
-	case http.StatusNotFound:
-		return serviceErrors.ErrCityNotFound
+// weatherApiErrorKind follows WeatherAPI error codes: unknown location is reported with 400
+// and exceeded calls quota with 403
+func weatherApiErrorKind(status int) ErrorKind {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorKindNotFound
+	case http.StatusForbidden:
+		return ErrorKindQuota
 	default:
-		return serviceErrors.ErrInternalServerError
+		return statusErrorKind(status)
 	}
 }
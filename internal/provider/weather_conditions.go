@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"slices"
+	"weatherApi/internal/common/constants"
+)
+
+// openWeatherMapCondition maps OpenWeatherMap condition id to provider-agnostic condition,
+// see https://openweathermap.org/weather-conditions
+func openWeatherMapCondition(id int) constants.WeatherCondition {
+	switch {
+	case id >= 200 && id < 300:
+		return constants.ConditionThunderstorm
+	case id >= 300 && id < 400:
+		return constants.ConditionDrizzle
+	case id == 511:
+		return constants.ConditionSleet
+	case id >= 500 && id < 600:
+		return constants.ConditionRain
+	case id >= 611 && id <= 616:
+		return constants.ConditionSleet
+	case id >= 600 && id < 700:
+		return constants.ConditionSnow
+	case id >= 700 && id < 800:
+		return constants.ConditionFog
+	case id == 800:
+		return constants.ConditionClear
+	case id == 801 || id == 802:
+		return constants.ConditionPartlyCloudy
+	case id == 803 || id == 804:
+		return constants.ConditionCloudy
+	default:
+		return constants.ConditionUnknown
+	}
+}
+
+var (
+	weatherApiFogCodes          = []int{1030, 1135, 1147}
+	weatherApiDrizzleCodes      = []int{1150, 1153}
+	weatherApiRainCodes         = []int{1063, 1180, 1183, 1186, 1189, 1192, 1195, 1240, 1243, 1246}
+	weatherApiSleetCodes        = []int{1069, 1072, 1168, 1171, 1198, 1201, 1204, 1207, 1237, 1249, 1252, 1261, 1264}
+	weatherApiSnowCodes         = []int{1066, 1114, 1117, 1210, 1213, 1216, 1219, 1222, 1225, 1255, 1258}
+	weatherApiThunderstormCodes = []int{1087, 1273, 1276, 1279, 1282}
+)
+
+// weatherApiCondition maps WeatherAPI condition code to provider-agnostic condition,
+// see https://www.weatherapi.com/docs/weather_conditions.json
+func weatherApiCondition(code int) constants.WeatherCondition {
+	switch {
+	case code == 1000:
+		return constants.ConditionClear
+	case code == 1003:
+		return constants.ConditionPartlyCloudy
+	case code == 1006 || code == 1009:
+		return constants.ConditionCloudy
+	case slices.Contains(weatherApiFogCodes, code):
+		return constants.ConditionFog
+	case slices.Contains(weatherApiDrizzleCodes, code):
+		return constants.ConditionDrizzle
+	case slices.Contains(weatherApiRainCodes, code):
+		return constants.ConditionRain
+	case slices.Contains(weatherApiSleetCodes, code):
+		return constants.ConditionSleet
+	case slices.Contains(weatherApiSnowCodes, code):
+		return constants.ConditionSnow
+	case slices.Contains(weatherApiThunderstormCodes, code):
+		return constants.ConditionThunderstorm
+	default:
+		return constants.ConditionUnknown
+	}
+}
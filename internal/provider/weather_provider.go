@@ -6,23 +6,24 @@ import (
 	"weatherApi/internal/dto"
 
 	"github.com/rs/zerolog"
-
-	serviceErrors "weatherApi/internal/service/weather/errors"
 )
 
 type WeatherProviderInterface interface {
 	SetNext(next WeatherProviderInterface)
-	GetWeather(ctx context.Context, city string) (*dto.WeatherResponse, *errors.AppError)
+	// GetWeather requests this provider and falls back to the next one in chain on provider failure
+	GetWeather(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError)
+	// Fetch requests only this provider, provider failures are returned as classified ProviderError
+	Fetch(ctx context.Context, location dto.Location, lang string) (*dto.WeatherResponse, *errors.AppError)
 	Name() string
 }
 
-func TryNext(log *zerolog.Logger, ctx context.Context, current WeatherProviderInterface, next WeatherProviderInterface, city string, err error) (*dto.WeatherResponse, *errors.AppError) {
+func TryNext(log *zerolog.Logger, ctx context.Context, current WeatherProviderInterface, next WeatherProviderInterface, location dto.Location, lang string, err error) (*dto.WeatherResponse, *errors.AppError) {
 	log.Error().Err(err).Msgf("%s: Provider failed", current.Name())
 
 	if next != nil {
-		return next.GetWeather(ctx, city)
+		return next.GetWeather(ctx, location, lang)
 	}
 
 	log.Error().Msgf("%s: no next provider available", current.Name())
-	return nil, serviceErrors.ErrInternalServerError
+	return nil, ClientError(err)
 }
@@ -0,0 +1,12 @@
+package airquality
+
+import (
+	"weatherApi/internal/dto"
+	"weatherApi/internal/repository/cache"
+)
+
+type MockCacheRepo = cache.MockCacheRepo[dto.AirQualityResponse]
+
+func NewMockCacheRepo() *MockCacheRepo {
+	return cache.NewMockCacheRepo[dto.AirQualityResponse]()
+}
@@ -0,0 +1,16 @@
+package airquality
+
+import (
+	"weatherApi/internal/dto"
+	"weatherApi/internal/repository/cache"
+)
+
+type CacheRepoInterface = cache.CacheRepoInterface[dto.AirQualityResponse]
+
+type Repository = cache.RedisRepository[dto.AirQualityResponse]
+
+var ErrCacheIsEmpty = cache.ErrCacheIsEmpty
+
+func NewAirQualityRepository(options *cache.RepositoryOptions) *Repository {
+	return cache.NewRedisRepository[dto.AirQualityResponse]("air_quality", options)
+}
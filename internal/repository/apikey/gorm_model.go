@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"time"
+	"weatherApi/internal/dto"
+)
+
+// APIKeyModel stores only SHA-256 hash of issued key, plain key is shown once on creation
+type APIKeyModel struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Name               string `gorm:"size:64;not null"`
+	Prefix             string `gorm:"size:16;not null"`
+	KeyHash            string `gorm:"type:CHAR(64);uniqueIndex;not null"`
+	RateLimitPerMinute int    `gorm:"not null"`
+	RevokedAt          *time.Time
+}
+
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+func (m *APIKeyModel) ToDTO() dto.APIKey {
+	return dto.APIKey{
+		ID:                 m.ID,
+		Name:               m.Name,
+		Prefix:             m.Prefix,
+		RateLimitPerMinute: m.RateLimitPerMinute,
+		CreatedAt:          m.CreatedAt,
+		RevokedAt:          m.RevokedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepositoryInterface interface {
+	FindAll(ctx context.Context, query any, args ...any) ([]APIKeyModel, error)
+	FindOneOrNone(ctx context.Context, query any, args ...any) (*APIKeyModel, error)
+	CreateOne(ctx context.Context, entity *APIKeyModel) error
+	Update(ctx context.Context, entity *APIKeyModel) error
+}
+
+type APIKeyRepository struct {
+	*base.BaseRepository[APIKeyModel]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		BaseRepository: base.NewRepository[APIKeyModel](db),
+	}
+}
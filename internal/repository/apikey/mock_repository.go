@@ -0,0 +1,59 @@
+package apikey
+
+import (
+	"context"
+	"sync"
+	"time"
+	"weatherApi/internal/repository/base"
+)
+
+// MockAPIKeyRepository keeps keys in memory, queries are matched by key hash or id argument
+type MockAPIKeyRepository struct {
+	mu   sync.Mutex
+	keys []*APIKeyModel
+}
+
+func (m *MockAPIKeyRepository) FindAll(_ context.Context, _ any, _ ...any) ([]APIKeyModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]APIKeyModel, len(m.keys))
+	for i, key := range m.keys {
+		result[i] = *key
+	}
+	return result, nil
+}
+
+func (m *MockAPIKeyRepository) FindOneOrNone(_ context.Context, _ any, args ...any) (*APIKeyModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		if len(args) > 0 && (args[0] == key.KeyHash || args[0] == key.ID) {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, base.ErrNotFound
+}
+
+func (m *MockAPIKeyRepository) CreateOne(_ context.Context, entity *APIKeyModel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entity.ID = uint(len(m.keys) + 1)
+	entity.CreatedAt = time.Now()
+	stored := *entity
+	m.keys = append(m.keys, &stored)
+	return nil
+}
+
+func (m *MockAPIKeyRepository) Update(_ context.Context, entity *APIKeyModel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, key := range m.keys {
+		if key.ID == entity.ID {
+			stored := *entity
+			m.keys[i] = &stored
+			return nil
+		}
+	}
+	return base.ErrNotFound
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+// AuditLogModel is a single admin action, rows are never updated or deleted
+type AuditLogModel struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	Actor          string `gorm:"size:64;not null"`
+	RemoteIP       string `gorm:"size:64"`
+	Action         string `gorm:"size:32;not null"`
+	UserID         *uint
+	SubscriptionID *uint
+	Details        string
+	// Outcome is empty for entries written before actions were audited with their result
+	Outcome constants.AuditOutcome `gorm:"size:16;not null;default:''"`
+	Error   string                 `gorm:"not null;default:''"`
+}
+
+func (AuditLogModel) TableName() string {
+	return "admin_audit_log"
+}
+
+func (m *AuditLogModel) ToDTO() dto.AuditLogEntry {
+	return dto.AuditLogEntry{
+		ID:             m.ID,
+		CreatedAt:      m.CreatedAt,
+		Actor:          m.Actor,
+		RemoteIP:       m.RemoteIP,
+		Action:         m.Action,
+		UserID:         m.UserID,
+		SubscriptionID: m.SubscriptionID,
+		Details:        m.Details,
+		Outcome:        m.Outcome,
+		Error:          m.Error,
+	}
+}
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"context"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepositoryInterface interface {
+	CreateOne(ctx context.Context, entity *AuditLogModel) error
+	FindLatest(ctx context.Context, limit int) ([]AuditLogModel, error)
+}
+
+type AuditLogRepository struct {
+	*base.BaseRepository[AuditLogModel]
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		BaseRepository: base.NewRepository[AuditLogModel](db),
+	}
+}
+
+// FindLatest returns up to limit newest entries
+func (r *AuditLogRepository) FindLatest(ctx context.Context, limit int) ([]AuditLogModel, error) {
+	var entities []AuditLogModel
+	result := r.DB.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entities)
+	return entities, result.Error
+}
@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MockAuditLogRepository keeps entries in memory in insertion order
+type MockAuditLogRepository struct {
+	mu      sync.Mutex
+	Entries []AuditLogModel
+	Err     error
+}
+
+func (m *MockAuditLogRepository) CreateOne(_ context.Context, entity *AuditLogModel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	entity.ID = uint(len(m.Entries) + 1)
+	entity.CreatedAt = time.Now()
+	m.Entries = append(m.Entries, *entity)
+	return nil
+}
+
+func (m *MockAuditLogRepository) FindLatest(_ context.Context, limit int) ([]AuditLogModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]AuditLogModel, 0, limit)
+	for i := len(m.Entries) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, m.Entries[i])
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type MockCacheRepo[T any] struct {
+	mu       sync.Mutex
+	data     map[string]*T
+	locks    map[string]bool
+	lockCond map[string]*sync.Cond
+
+	GetFunc func(ctx context.Context, key string) (*T, error)
+}
+
+func NewMockCacheRepo[T any]() *MockCacheRepo[T] {
+	return &MockCacheRepo[T]{
+		data:     make(map[string]*T),
+		locks:    make(map[string]bool),
+		lockCond: make(map[string]*sync.Cond),
+	}
+}
+
+func (m *MockCacheRepo[T]) AcquireLock(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locks[key] {
+		return false, nil
+	}
+	m.locks[key] = true
+	if _, exists := m.lockCond[key]; !exists {
+		m.lockCond[key] = sync.NewCond(&m.mu)
+	}
+	return true, nil
+}
+
+func (m *MockCacheRepo[T]) ReleaseLock(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locks[key] = false
+	if cond, exists := m.lockCond[key]; exists {
+		cond.Broadcast()
+	}
+	return nil
+}
+
+func (m *MockCacheRepo[T]) WaitForUnlock(ctx context.Context, key string) (*T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cond, exists := m.lockCond[key]
+	if !exists {
+		cond = sync.NewCond(&m.mu)
+		m.lockCond[key] = cond
+	}
+
+	for m.locks[key] {
+		done := make(chan struct{})
+		go func() {
+			cond.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+		}
+	}
+
+	val, ok := m.data[key]
+	if !ok {
+		return nil, ErrCacheIsEmpty
+	}
+	return val, nil
+}
+
+func (m *MockCacheRepo[T]) Set(ctx context.Context, key string, data *T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data[key] = data
+	return nil
+}
+
+func (m *MockCacheRepo[T]) Get(ctx context.Context, key string) (*T, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
+	}
+	return m.WaitForUnlock(ctx, key)
+}
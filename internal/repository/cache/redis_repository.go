@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"weatherApi/internal/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CacheRepoInterface[T any] interface {
+	Get(ctx context.Context, key string) (*T, error)
+	Set(ctx context.Context, key string, data *T) error
+	AcquireLock(ctx context.Context, key string) (bool, error)
+	WaitForUnlock(ctx context.Context, key string) (*T, error)
+	ReleaseLock(ctx context.Context, key string) error
+}
+
+var ErrCacheIsEmpty = errors.New("cache is empty")
+
+// RedisRepository is read-through cache with distributed lock, keys are namespaced by prefix
+type RedisRepository[T any] struct {
+	prefix       string
+	client       *redis.Client
+	cacheTTL     time.Duration
+	lockTTL      time.Duration
+	lockRetryDur time.Duration
+	lockMaxWait  time.Duration
+	metrics      *metrics.CacheMetrics
+}
+
+type RepositoryOptions struct {
+	Client       *redis.Client
+	CacheTTL     time.Duration
+	LockTTL      time.Duration
+	LockRetryDur time.Duration
+	LockMaxWait  time.Duration
+	Metrics      *metrics.CacheMetrics
+}
+
+func NewRedisRepository[T any](prefix string, options *RepositoryOptions) *RedisRepository[T] {
+	return &RedisRepository[T]{
+		prefix:       prefix,
+		client:       options.Client,
+		cacheTTL:     options.CacheTTL,
+		lockTTL:      options.LockTTL,
+		lockRetryDur: options.LockRetryDur,
+		lockMaxWait:  options.LockMaxWait,
+		metrics:      options.Metrics,
+	}
+}
+
+func (r *RedisRepository[T]) getCacheKey(key string) string {
+	return fmt.Sprintf("%s:location:%s", r.prefix, key)
+}
+
+func (r *RedisRepository[T]) getLockKey(key string) string {
+	return fmt.Sprintf("%s:lock:%s", r.prefix, key)
+}
+
+func (r *RedisRepository[T]) Get(ctx context.Context, key string) (*T, error) {
+	cacheKey := r.getCacheKey(key)
+
+	data, err := r.client.Get(ctx, cacheKey).Result()
+	if errors.Is(err, redis.Nil) {
+		r.metrics.IncCacheMiss()
+		return nil, ErrCacheIsEmpty
+	} else if err != nil {
+		return nil, err
+	}
+	r.metrics.IncCacheHit()
+	var res T
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *RedisRepository[T]) Set(ctx context.Context, key string, data *T) error {
+	cacheKey := r.getCacheKey(key)
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, cacheKey, raw, r.cacheTTL).Err()
+}
+
+func (r *RedisRepository[T]) AcquireLock(ctx context.Context, key string) (bool, error) {
+	lockKey := r.getLockKey(key)
+	ok, err := r.client.SetNX(ctx, lockKey, "1", r.lockTTL).Result()
+	return ok, err
+}
+
+func (r *RedisRepository[T]) WaitForUnlock(ctx context.Context, key string) (*T, error) {
+	cacheKey := r.getCacheKey(key)
+	start := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(r.lockRetryDur):
+			data, err := r.client.Get(ctx, cacheKey).Result()
+			if err == nil {
+				r.metrics.ObserveLockWaitDuration(time.Since(start).Seconds())
+				var res T
+				if err := json.Unmarshal([]byte(data), &res); err == nil {
+					return &res, nil
+				}
+			} else if !errors.Is(err, redis.Nil) {
+				r.metrics.ObserveLockWaitDuration(time.Since(start).Seconds())
+				return nil, err
+			}
+
+			if time.Since(start) > r.lockMaxWait {
+				r.metrics.ObserveLockWaitDuration(time.Since(start).Seconds())
+				return nil, errors.New("timeout waiting for cache fill")
+			}
+		}
+	}
+}
+
+func (r *RedisRepository[T]) ReleaseLock(ctx context.Context, key string) error {
+	lockKey := r.getLockKey(key)
+	return r.client.Del(ctx, lockKey).Err()
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"strings"
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// citySeparator joins cities of digest email in a single column
+const citySeparator = ","
+
+// DeliveryModel is a weather email handed over to SMTP server, email is stored lowercased
+// so it can be found for export and erasure
+type DeliveryModel struct {
+	ID     uint                   `gorm:"primaryKey"`
+	Email  string                 `gorm:"size:255;not null"`
+	Kind   constants.DeliveryKind `gorm:"size:16;not null"`
+	Cities string                 `gorm:"not null;default:''"`
+	SentAt time.Time              `gorm:"not null"`
+}
+
+func (DeliveryModel) TableName() string {
+	return "email_deliveries"
+}
+
+func NewDelivery(email string, kind constants.DeliveryKind, cities []string, sentAt time.Time) *DeliveryModel {
+	return &DeliveryModel{
+		Email:  normalizeEmail(email),
+		Kind:   kind,
+		Cities: strings.Join(cities, citySeparator),
+		SentAt: sentAt,
+	}
+}
+
+// CityList returns cities the email was about
+func (m *DeliveryModel) CityList() []string {
+	if m.Cities == "" {
+		return nil
+	}
+	return strings.Split(m.Cities, citySeparator)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
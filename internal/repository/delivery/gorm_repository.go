@@ -0,0 +1,37 @@
+package delivery
+
+import (
+	"context"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+)
+
+type DeliveryRepositoryInterface interface {
+	Record(ctx context.Context, delivery *DeliveryModel) error
+	// FindByEmail returns deliveries to email, oldest first
+	FindByEmail(ctx context.Context, email string) ([]DeliveryModel, error)
+}
+
+type DeliveryRepository struct {
+	*base.BaseRepository[DeliveryModel]
+}
+
+func NewDeliveryRepository(db *gorm.DB) *DeliveryRepository {
+	return &DeliveryRepository{
+		BaseRepository: base.NewRepository[DeliveryModel](db),
+	}
+}
+
+func (r *DeliveryRepository) Record(ctx context.Context, delivery *DeliveryModel) error {
+	return r.CreateOne(ctx, delivery)
+}
+
+func (r *DeliveryRepository) FindByEmail(ctx context.Context, email string) ([]DeliveryModel, error) {
+	var entities []DeliveryModel
+	result := r.DB.WithContext(ctx).
+		Where("email = ?", normalizeEmail(email)).
+		Order("sent_at, id").
+		Find(&entities)
+	return entities, result.Error
+}
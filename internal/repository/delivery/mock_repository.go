@@ -0,0 +1,39 @@
+package delivery
+
+import (
+	"context"
+	"sync"
+)
+
+// MockDeliveryRepository keeps deliveries in memory in insertion order
+type MockDeliveryRepository struct {
+	mu         sync.Mutex
+	Deliveries []DeliveryModel
+	Err        error
+}
+
+func (m *MockDeliveryRepository) Record(_ context.Context, delivery *DeliveryModel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	delivery.ID = uint(len(m.Deliveries) + 1)
+	m.Deliveries = append(m.Deliveries, *delivery)
+	return nil
+}
+
+func (m *MockDeliveryRepository) FindByEmail(_ context.Context, email string) ([]DeliveryModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var result []DeliveryModel
+	for _, delivery := range m.Deliveries {
+		if delivery.Email == normalizeEmail(email) {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package jobrun
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+// JobRunModel is a single run of scheduled job, slot is the scheduled time the run stands for,
+// so each slot of a job runs at most once. Instance and StartedAt identify who runs it, running run
+// not heard of since HeartbeatAt for run timeout is taken for crashed
+type JobRunModel struct {
+	ID          uint   `gorm:"primaryKey"`
+	Job         string `gorm:"size:32;not null"`
+	Slot        time.Time
+	CatchUp     bool
+	Status      constants.JobRunStatus `gorm:"size:16;not null"`
+	Instance    string                 `gorm:"size:128"`
+	StartedAt   time.Time
+	HeartbeatAt *time.Time
+	FinishedAt  *time.Time
+	Cities      int
+	Recipients  int
+	Failures    int
+	Error       string
+}
+
+func (JobRunModel) TableName() string {
+	return "job_runs"
+}
+
+func (m *JobRunModel) ToDTO() dto.JobRun {
+	return dto.JobRun{
+		ID:         m.ID,
+		Job:        m.Job,
+		Slot:       m.Slot,
+		CatchUp:    m.CatchUp,
+		Status:     m.Status,
+		Instance:   m.Instance,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+		Cities:     m.Cities,
+		Recipients: m.Recipients,
+		Failures:   m.Failures,
+		Error:      m.Error,
+	}
+}
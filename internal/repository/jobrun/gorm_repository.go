@@ -0,0 +1,102 @@
+package jobrun
+
+import (
+	"context"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRunRepositoryInterface interface {
+	// Claim stores run unless its slot is already taken, returns whether run was stored. Slot of run
+	// still running but last heard of before staleBefore, i.e. of crashed instance, is taken over
+	Claim(ctx context.Context, run *JobRunModel, staleBefore time.Time) (bool, error)
+	// Heartbeat marks run as alive at, returns false when run was taken over by another instance
+	Heartbeat(ctx context.Context, run *JobRunModel, at time.Time) (bool, error)
+	// Finish stores outcome of run, returns false when run was taken over by another instance
+	Finish(ctx context.Context, run *JobRunModel) (bool, error)
+	// LatestSlot returns the newest slot recorded for job, nil when job never ran. Runs still running
+	// but last heard of before staleBefore are treated as missed, failed runs count as recorded
+	LatestSlot(ctx context.Context, job string, staleBefore time.Time) (*time.Time, error)
+	// FindLatest returns up to limit newest runs, of all jobs when job is empty
+	FindLatest(ctx context.Context, job string, limit int) ([]JobRunModel, error)
+}
+
+type JobRunRepository struct {
+	*base.BaseRepository[JobRunModel]
+}
+
+func NewJobRunRepository(db *gorm.DB) *JobRunRepository {
+	return &JobRunRepository{
+		BaseRepository: base.NewRepository[JobRunModel](db),
+	}
+}
+
+func (r *JobRunRepository) Claim(ctx context.Context, run *JobRunModel, staleBefore time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "job"}, {Name: "slot"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"catch_up", "status", "instance", "started_at", "heartbeat_at", "finished_at", "cities", "recipients", "failures", "error",
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+				SQL:  "job_runs.status = ? AND COALESCE(job_runs.heartbeat_at, job_runs.started_at) < ?",
+				Vars: []any{constants.JobRunRunning, staleBefore},
+			}}},
+		}).
+		Create(run)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *JobRunRepository) Heartbeat(ctx context.Context, run *JobRunModel, at time.Time) (bool, error) {
+	result := r.owned(ctx, run).Update("heartbeat_at", at)
+	if result.Error == nil && result.RowsAffected == 1 {
+		run.HeartbeatAt = &at
+	}
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *JobRunRepository) Finish(ctx context.Context, run *JobRunModel) (bool, error) {
+	result := r.owned(ctx, run).Updates(map[string]any{
+		"status":      run.Status,
+		"finished_at": run.FinishedAt,
+		"cities":      run.Cities,
+		"recipients":  run.Recipients,
+		"failures":    run.Failures,
+		"error":       run.Error,
+	})
+	return result.RowsAffected == 1, result.Error
+}
+
+// owned selects run only while it is still running by the instance which claimed it
+func (r *JobRunRepository) owned(ctx context.Context, run *JobRunModel) *gorm.DB {
+	return r.DB.WithContext(ctx).
+		Model(&JobRunModel{}).
+		Where("id = ? AND instance = ? AND started_at = ? AND status = ?", run.ID, run.Instance, run.StartedAt, constants.JobRunRunning)
+}
+
+func (r *JobRunRepository) LatestSlot(ctx context.Context, job string, staleBefore time.Time) (*time.Time, error) {
+	var slot *time.Time
+	result := r.DB.WithContext(ctx).
+		Model(&JobRunModel{}).
+		Where("job = ? AND NOT (status = ? AND COALESCE(heartbeat_at, started_at) < ?)", job, constants.JobRunRunning, staleBefore).
+		Select("MAX(slot)").
+		Scan(&slot)
+	return slot, result.Error
+}
+
+func (r *JobRunRepository) FindLatest(ctx context.Context, job string, limit int) ([]JobRunModel, error) {
+	var entities []JobRunModel
+	query := r.DB.WithContext(ctx)
+	if job != "" {
+		query = query.Where("job = ?", job)
+	}
+	result := query.
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&entities)
+	return entities, result.Error
+}
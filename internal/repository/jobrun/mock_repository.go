@@ -0,0 +1,126 @@
+package jobrun
+
+import (
+	"context"
+	"sync"
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// MockJobRunRepository keeps runs in memory in insertion order
+type MockJobRunRepository struct {
+	mu   sync.Mutex
+	Runs []JobRunModel
+	Err  error
+}
+
+func (m *MockJobRunRepository) Claim(_ context.Context, run *JobRunModel, staleBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i, existing := range m.Runs {
+		if existing.Job == run.Job && existing.Slot.Equal(run.Slot) {
+			if !isStale(existing, staleBefore) {
+				return false, nil
+			}
+			run.ID = existing.ID
+			m.Runs[i] = *run
+			return true, nil
+		}
+	}
+	run.ID = uint(len(m.Runs) + 1)
+	m.Runs = append(m.Runs, *run)
+	return true, nil
+}
+
+func (m *MockJobRunRepository) Heartbeat(_ context.Context, run *JobRunModel, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, m.Err
+	}
+	owned := m.owned(run)
+	if owned == nil {
+		return false, nil
+	}
+	owned.HeartbeatAt = &at
+	run.HeartbeatAt = &at
+	return true, nil
+}
+
+func (m *MockJobRunRepository) Finish(_ context.Context, run *JobRunModel) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, m.Err
+	}
+	owned := m.owned(run)
+	if owned == nil {
+		return false, nil
+	}
+	*owned = *run
+	return true, nil
+}
+
+// SetInstance changes instance of run with id, as if another instance took it over
+func (m *MockJobRunRepository) SetInstance(id uint, instance string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.Runs {
+		if m.Runs[i].ID == id {
+			m.Runs[i].Instance = instance
+		}
+	}
+}
+
+func (m *MockJobRunRepository) owned(run *JobRunModel) *JobRunModel {
+	for i := range m.Runs {
+		existing := &m.Runs[i]
+		if existing.ID == run.ID && existing.Instance == run.Instance && existing.StartedAt.Equal(run.StartedAt) &&
+			existing.Status == constants.JobRunRunning {
+			return existing
+		}
+	}
+	return nil
+}
+
+func (m *MockJobRunRepository) LatestSlot(_ context.Context, job string, staleBefore time.Time) (*time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var latest *time.Time
+	for i := range m.Runs {
+		if m.Runs[i].Job == job && !isStale(m.Runs[i], staleBefore) && (latest == nil || m.Runs[i].Slot.After(*latest)) {
+			slot := m.Runs[i].Slot
+			latest = &slot
+		}
+	}
+	return latest, nil
+}
+
+func isStale(run JobRunModel, staleBefore time.Time) bool {
+	heardOf := run.StartedAt
+	if run.HeartbeatAt != nil {
+		heardOf = *run.HeartbeatAt
+	}
+	return run.Status == constants.JobRunRunning && heardOf.Before(staleBefore)
+}
+
+func (m *MockJobRunRepository) FindLatest(_ context.Context, job string, limit int) ([]JobRunModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	result := make([]JobRunModel, 0, limit)
+	for i := len(m.Runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if job == "" || m.Runs[i].Job == job {
+			result = append(result, m.Runs[i])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type mockLease struct {
+	holder  string
+	expires time.Time
+}
+
+// MockLeaseRepo is in-memory lease store with the same semantics as Redis scripts
+type MockLeaseRepo struct {
+	mu     sync.Mutex
+	leases map[string]*mockLease
+
+	Err error
+}
+
+func NewMockLeaseRepo() *MockLeaseRepo {
+	return &MockLeaseRepo{leases: make(map[string]*mockLease)}
+}
+
+func (m *MockLeaseRepo) Acquire(_ context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, m.Err
+	}
+
+	now := time.Now()
+	lease, ok := m.leases[name]
+	if ok && lease.holder != holder && now.Before(lease.expires) {
+		return false, nil
+	}
+	m.leases[name] = &mockLease{holder: holder, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *MockLeaseRepo) Release(_ context.Context, name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if lease, ok := m.leases[name]; ok && lease.holder == holder {
+		delete(m.leases, name)
+	}
+	return nil
+}
+
+// Expire drops lease as if its holder died and TTL passed
+func (m *MockLeaseRepo) Expire(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.leases, name)
+}
@@ -0,0 +1,60 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LeaseRepoInterface interface {
+	// Acquire takes lease for holder or extends it when holder already owns it, returns whether holder owns lease
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release gives lease up, lease owned by another holder is left untouched
+	Release(ctx context.Context, name, holder string) error
+}
+
+// acquireScript extends lease of its owner or takes a free one, so expiry of a dead leader hands it over
+var acquireScript = redis.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if owner == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if owner then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type RedisRepository struct {
+	client *redis.Client
+}
+
+func NewLeaseRepository(client *redis.Client) *RedisRepository {
+	return &RedisRepository{client: client}
+}
+
+func (r *RedisRepository) getKey(name string) string {
+	return "leader:" + name
+}
+
+func (r *RedisRepository) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireScript.Run(ctx, r.client, []string{r.getKey(name)}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+func (r *RedisRepository) Release(ctx context.Context, name, holder string) error {
+	return releaseScript.Run(ctx, r.client, []string{r.getKey(name)}, holder).Err()
+}
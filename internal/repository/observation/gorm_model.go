@@ -0,0 +1,65 @@
+package observation
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+// ObservationModel is single provider reading, values are stored in metric units
+type ObservationModel struct {
+	ID         uint      `gorm:"primaryKey"`
+	Location   string    `gorm:"size:64;not null;index:idx_weather_observations_location_time,priority:1"`
+	Provider   string    `gorm:"size:32;not null"`
+	ObservedAt time.Time `gorm:"not null;index:idx_weather_observations_location_time,priority:2"`
+
+	Temperature   float64
+	FeelsLike     float64
+	Humidity      int
+	Pressure      float64
+	WindSpeed     float64
+	WindDirection int
+	Visibility    float64
+	UVIndex       *float64
+	Condition     constants.WeatherCondition `gorm:"size:16"`
+}
+
+func (ObservationModel) TableName() string {
+	return "weather_observations"
+}
+
+func NewObservationModel(location string, observedAt time.Time, weather *dto.WeatherResponse) *ObservationModel {
+	return &ObservationModel{
+		Location:      location,
+		Provider:      weather.Provider,
+		ObservedAt:    observedAt,
+		Temperature:   weather.Temperature,
+		FeelsLike:     weather.FeelsLike,
+		Humidity:      weather.Humidity,
+		Pressure:      weather.Pressure,
+		WindSpeed:     weather.WindSpeed,
+		WindDirection: weather.WindDirection,
+		Visibility:    weather.Visibility,
+		UVIndex:       weather.UVIndex,
+		Condition:     weather.Condition,
+	}
+}
+
+// AggregatedObservation is a row of observations aggregated into a time bucket
+type AggregatedObservation struct {
+	Bucket  time.Time
+	Samples int
+
+	MinTemperature float64
+	MaxTemperature float64
+	AvgTemperature float64
+	MinHumidity    float64
+	MaxHumidity    float64
+	AvgHumidity    float64
+	MinPressure    float64
+	MaxPressure    float64
+	AvgPressure    float64
+	MinWindSpeed   float64
+	MaxWindSpeed   float64
+	AvgWindSpeed   float64
+}
@@ -0,0 +1,56 @@
+package observation
+
+import (
+	"context"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+)
+
+type ObservationRepositoryInterface interface {
+	CreateOne(ctx context.Context, entity *ObservationModel) error
+	Aggregate(
+		ctx context.Context,
+		location string,
+		from, to time.Time,
+		granularity constants.Granularity,
+	) ([]AggregatedObservation, error)
+}
+
+type ObservationRepository struct {
+	*base.BaseRepository[ObservationModel]
+}
+
+func NewObservationRepository(db *gorm.DB) *ObservationRepository {
+	return &ObservationRepository{
+		BaseRepository: base.NewRepository[ObservationModel](db),
+	}
+}
+
+// Aggregate returns min/max/avg of observations in [from, to) grouped by hour or day (UTC)
+func (r *ObservationRepository) Aggregate(
+	ctx context.Context,
+	location string,
+	from, to time.Time,
+	granularity constants.Granularity,
+) ([]AggregatedObservation, error) {
+	var rows []AggregatedObservation
+
+	bucket := gorm.Expr("date_trunc(?, observed_at AT TIME ZONE 'UTC')", string(granularity))
+	result := r.DB.WithContext(ctx).
+		Model(&ObservationModel{}).
+		Select(`? AS bucket,
+			COUNT(*) AS samples,
+			MIN(temperature) AS min_temperature, MAX(temperature) AS max_temperature, AVG(temperature) AS avg_temperature,
+			MIN(humidity) AS min_humidity, MAX(humidity) AS max_humidity, AVG(humidity) AS avg_humidity,
+			MIN(pressure) AS min_pressure, MAX(pressure) AS max_pressure, AVG(pressure) AS avg_pressure,
+			MIN(wind_speed) AS min_wind_speed, MAX(wind_speed) AS max_wind_speed, AVG(wind_speed) AS avg_wind_speed`, bucket).
+		Where("location = ? AND observed_at >= ? AND observed_at < ?", location, from, to).
+		Group("bucket").
+		Order("bucket").
+		Scan(&rows)
+
+	return rows, result.Error
+}
@@ -0,0 +1,25 @@
+package observation
+
+import (
+	"context"
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+type MockObservationRepository struct {
+	CreateOneFn func(entity *ObservationModel) error
+	AggregateFn func(location string, from, to time.Time, granularity constants.Granularity) ([]AggregatedObservation, error)
+}
+
+func (m *MockObservationRepository) CreateOne(_ context.Context, e *ObservationModel) error {
+	return m.CreateOneFn(e)
+}
+
+func (m *MockObservationRepository) Aggregate(
+	_ context.Context,
+	location string,
+	from, to time.Time,
+	granularity constants.Granularity,
+) ([]AggregatedObservation, error) {
+	return m.AggregateFn(location, from, to, granularity)
+}
@@ -0,0 +1,139 @@
+package privacy
+
+import (
+	"context"
+	"strings"
+	"time"
+	"weatherApi/internal/repository/audit"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/suppression"
+	"weatherApi/internal/repository/user"
+
+	"gorm.io/gorm"
+)
+
+// erasedDetails replaces audit log details which may contain erased email
+const erasedDetails = "[erased]"
+
+type ErasureRepositoryInterface interface {
+	EraseUsers(ctx context.Context, userIDs []uint) error
+	FindUnconfirmedUserIDs(ctx context.Context, before time.Time, limit int) ([]uint, error)
+	FindOrphanedUserIDs(ctx context.Context, createdBefore time.Time, limit int) ([]uint, error)
+}
+
+// ErasureRepository removes personal data across tables, rows are deleted for good, not soft deleted
+type ErasureRepository struct {
+	DB *gorm.DB
+}
+
+func NewErasureRepository(db *gorm.DB) *ErasureRepository {
+	return &ErasureRepository{DB: db}
+}
+
+// EraseUsers hard-deletes users with all their subscriptions, scrubs admin audit log entries about them and
+// removes delivery log and suppression list entries of every address they used, including the ones their
+// subscriptions were moved away from
+func (r *ErasureRepository) EraseUsers(ctx context.Context, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		emails, err := addressesOf(tx, userIDs)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&audit.AuditLogModel{}).
+			Where("user_id IN ?", userIDs).
+			Update("details", erasedDetails).Error; err != nil {
+			return err
+		}
+		if len(emails) > 0 {
+			if err := tx.Where("email IN ?", emails).Delete(&delivery.DeliveryModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("email IN ?", emails).Delete(&suppression.SuppressionModel{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().
+			Where("user_id IN ?", userIDs).
+			Delete(&subscription.SubscriptionModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("id IN ?", userIDs).
+			Delete(&user.UserModel{}).Error
+	})
+}
+
+// addressesOf returns lowercased current and previous addresses of users
+func addressesOf(tx *gorm.DB, userIDs []uint) ([]string, error) {
+	var current []string
+	if err := tx.Unscoped().
+		Model(&user.UserModel{}).
+		Where("id IN ?", userIDs).
+		Pluck("LOWER(email)", &current).Error; err != nil {
+		return nil, err
+	}
+	var previous []string
+	if err := tx.Unscoped().
+		Model(&subscription.SubscriptionModel{}).
+		Where("user_id IN ? AND previous_emails <> ''", userIDs).
+		Pluck("previous_emails", &previous).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(current))
+	var emails []string
+	add := func(email string) {
+		if email != "" && !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	for _, email := range current {
+		add(email)
+	}
+	for _, joined := range previous {
+		sub := subscription.SubscriptionModel{PreviousEmails: joined}
+		for _, email := range sub.PreviousEmailList() {
+			add(strings.ToLower(email))
+		}
+	}
+	return emails, nil
+}
+
+// FindUnconfirmedUserIDs returns up to limit users created before cutoff without active confirmed
+// subscription and without subscription activity since cutoff, so pending re-subscriptions are kept
+func (r *ErasureRepository) FindUnconfirmedUserIDs(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	result := r.DB.WithContext(ctx).
+		Unscoped().
+		Model(&user.UserModel{}).
+		Where("created_at < ?", before).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM subscriptions s
+			WHERE s.user_id = users.id
+			AND ((s.is_confirmed AND s.deleted_at IS NULL) OR s.updated_at >= ? OR s.deleted_at >= ?)
+		)`, before, before).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids)
+	return ids, result.Error
+}
+
+// FindOrphanedUserIDs returns up to limit users created before cutoff that have no subscriptions at all,
+// cutoff protects users created by subscribe request whose subscription isn't stored yet
+func (r *ErasureRepository) FindOrphanedUserIDs(ctx context.Context, createdBefore time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	result := r.DB.WithContext(ctx).
+		Unscoped().
+		Model(&user.UserModel{}).
+		Where("created_at < ?", createdBefore).
+		Where("NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = users.id)").
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids)
+	return ids, result.Error
+}
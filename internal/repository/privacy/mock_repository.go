@@ -0,0 +1,68 @@
+package privacy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MockTokenRepo keeps tokens in memory ignoring ttl
+type MockTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]PrivacyToken
+
+	Err error
+}
+
+func NewMockTokenRepo() *MockTokenRepo {
+	return &MockTokenRepo{tokens: make(map[string]PrivacyToken)}
+}
+
+func (m *MockTokenRepo) Save(_ context.Context, tokenHash string, token PrivacyToken, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.tokens[tokenHash] = token
+	return nil
+}
+
+func (m *MockTokenRepo) Get(_ context.Context, tokenHash string) (*PrivacyToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	token, ok := m.tokens[tokenHash]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+func (m *MockTokenRepo) Delete(_ context.Context, tokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, tokenHash)
+	return nil
+}
+
+type MockErasureRepository struct {
+	ErasedUserIDs            []uint
+	FindUnconfirmedUserIDsFn func(before time.Time, limit int) ([]uint, error)
+	FindOrphanedUserIDsFn    func(createdBefore time.Time, limit int) ([]uint, error)
+}
+
+func (m *MockErasureRepository) EraseUsers(_ context.Context, userIDs []uint) error {
+	m.ErasedUserIDs = append(m.ErasedUserIDs, userIDs...)
+	return nil
+}
+
+func (m *MockErasureRepository) FindUnconfirmedUserIDs(_ context.Context, before time.Time, limit int) ([]uint, error) {
+	return m.FindUnconfirmedUserIDsFn(before, limit)
+}
+
+func (m *MockErasureRepository) FindOrphanedUserIDs(_ context.Context, createdBefore time.Time, limit int) ([]uint, error) {
+	return m.FindOrphanedUserIDsFn(createdBefore, limit)
+}
@@ -0,0 +1,64 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"weatherApi/internal/common/constants"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrTokenNotFound = errors.New("privacy token not found")
+
+// PrivacyToken is pending privacy request, stored under hash of emailed token until it expires
+type PrivacyToken struct {
+	Email  string                  `json:"email"`
+	Action constants.PrivacyAction `json:"action"`
+}
+
+type TokenRepoInterface interface {
+	Save(ctx context.Context, tokenHash string, token PrivacyToken, ttl time.Duration) error
+	Get(ctx context.Context, tokenHash string) (*PrivacyToken, error)
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+type RedisTokenRepository struct {
+	client *redis.Client
+}
+
+func NewTokenRepository(client *redis.Client) *RedisTokenRepository {
+	return &RedisTokenRepository{client: client}
+}
+
+func (r *RedisTokenRepository) getKey(tokenHash string) string {
+	return "privacy:token:" + tokenHash
+}
+
+func (r *RedisTokenRepository) Save(ctx context.Context, tokenHash string, token PrivacyToken, ttl time.Duration) error {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, r.getKey(tokenHash), payload, ttl).Err()
+}
+
+func (r *RedisTokenRepository) Get(ctx context.Context, tokenHash string) (*PrivacyToken, error) {
+	payload, err := r.client.Get(ctx, r.getKey(tokenHash)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var token PrivacyToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RedisTokenRepository) Delete(ctx context.Context, tokenHash string) error {
+	return r.client.Del(ctx, r.getKey(tokenHash)).Err()
+}
@@ -0,0 +1,47 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MockQuotaRepo struct {
+	mu     sync.Mutex
+	counts map[string]int64
+
+	Err error
+}
+
+func NewMockQuotaRepo() *MockQuotaRepo {
+	return &MockQuotaRepo{counts: make(map[string]int64)}
+}
+
+func (m *MockQuotaRepo) key(provider string, day time.Time) string {
+	return provider + ":" + day.UTC().Format(time.DateOnly)
+}
+
+func (m *MockQuotaRepo) Increment(_ context.Context, provider string, day time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.counts[m.key(provider, day)]++
+	return m.counts[m.key(provider, day)], nil
+}
+
+func (m *MockQuotaRepo) Get(_ context.Context, provider string, day time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	return m.counts[m.key(provider, day)], nil
+}
+
+func (m *MockQuotaRepo) Set(provider string, day time.Time, count int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[m.key(provider, day)] = count
+}
@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// counterTTL keeps yesterday's counters around for inspection after reset
+const counterTTL = 48 * time.Hour
+
+type QuotaRepoInterface interface {
+	Increment(ctx context.Context, provider string, day time.Time) (int64, error)
+	Get(ctx context.Context, provider string, day time.Time) (int64, error)
+}
+
+// RedisRepository stores provider calls count per UTC day
+type RedisRepository struct {
+	client *redis.Client
+}
+
+func NewQuotaRepository(client *redis.Client) *RedisRepository {
+	return &RedisRepository{client: client}
+}
+
+func (r *RedisRepository) getKey(provider string, day time.Time) string {
+	return fmt.Sprintf("quota:%s:%s", provider, day.UTC().Format(time.DateOnly))
+}
+
+func (r *RedisRepository) Increment(ctx context.Context, provider string, day time.Time) (int64, error) {
+	key := r.getKey(provider, day)
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, counterTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisRepository) Get(ctx context.Context, provider string, day time.Time) (int64, error) {
+	count, err := r.client.Get(ctx, r.getKey(provider, day)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type mockBucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+// MockRateLimitRepo is in-memory token bucket with the same semantics as Redis script
+type MockRateLimitRepo struct {
+	mu      sync.Mutex
+	buckets map[string]*mockBucket
+
+	Err error
+}
+
+func NewMockRateLimitRepo() *MockRateLimitRepo {
+	return &MockRateLimitRepo{buckets: make(map[string]*mockBucket)}
+}
+
+func (m *MockRateLimitRepo) Take(_ context.Context, bucket string, capacity int, rate float64, now time.Time) (bool, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, 0, m.Err
+	}
+
+	b, ok := m.buckets[bucket]
+	if !ok {
+		b = &mockBucket{tokens: float64(capacity), ts: now}
+		m.buckets[bucket] = b
+	}
+	b.tokens = min(float64(capacity), b.tokens+max(0, now.Sub(b.ts).Seconds())*rate)
+	b.ts = now
+	if b.tokens < 1 {
+		return false, b.tokens, nil
+	}
+	b.tokens--
+	return true, b.tokens, nil
+}
+
+func (m *MockRateLimitRepo) Peek(_ context.Context, bucket string, capacity int, rate float64, now time.Time) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	b, ok := m.buckets[bucket]
+	if !ok {
+		return float64(capacity), nil
+	}
+	return min(float64(capacity), b.tokens+max(0, now.Sub(b.ts).Seconds())*rate), nil
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RateLimitRepoInterface interface {
+	// Take removes a token from bucket refilled with rate tokens per second up to capacity,
+	// returns whether token was taken and tokens left
+	Take(ctx context.Context, bucket string, capacity int, rate float64, now time.Time) (bool, float64, error)
+	// Peek returns tokens left in bucket without taking one
+	Peek(ctx context.Context, bucket string, capacity int, rate float64, now time.Time) (float64, error)
+}
+
+// tokenBucketScript refills and takes from bucket atomically, bucket expires once it would be full again
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate * 1000))
+return {allowed, tostring(tokens)}
+`)
+
+type RedisRepository struct {
+	client *redis.Client
+}
+
+func NewRateLimitRepository(client *redis.Client) *RedisRepository {
+	return &RedisRepository{client: client}
+}
+
+func (r *RedisRepository) getKey(bucket string) string {
+	return "ratelimit:" + bucket
+}
+
+func (r *RedisRepository) Take(ctx context.Context, bucket string, capacity int, rate float64, now time.Time) (bool, float64, error) {
+	result, err := tokenBucketScript.Run(ctx, r.client, []string{r.getKey(bucket)},
+		capacity, strconv.FormatFloat(rate, 'f', -1, 64), now.UnixMilli(),
+	).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	allowed, _ := result[0].(int64)
+	tokens, err := strconv.ParseFloat(result[1].(string), 64)
+	if err != nil {
+		return false, 0, err
+	}
+	return allowed == 1, tokens, nil
+}
+
+func (r *RedisRepository) Peek(ctx context.Context, bucket string, capacity int, rate float64, now time.Time) (float64, error) {
+	values, err := r.client.HMGet(ctx, r.getKey(bucket), "tokens", "ts").Result()
+	if err != nil {
+		return 0, err
+	}
+	tokensValue, ok := values[0].(string)
+	tsValue, tsOk := values[1].(string)
+	if !ok || !tsOk {
+		return float64(capacity), nil
+	}
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return 0, err
+	}
+	ts, err := strconv.ParseInt(tsValue, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := max(0, float64(now.UnixMilli()-ts)) / 1000
+	return min(float64(capacity), tokens+elapsed*rate), nil
+}
@@ -1,6 +1,7 @@
 package subscription
 
 import (
+	"strings"
 	"time"
 
 	"weatherApi/internal/common/constants"
@@ -14,16 +15,73 @@ type SubscriptionModel struct {
 
 	City      string              `gorm:"size:32;not null"`
 	Frequency constants.Frequency `gorm:"type:VARCHAR(10);not null;default:'daily'"`
+	Units     constants.Units     `gorm:"type:VARCHAR(10);not null;default:'metric'"`
+	Lang      string              `gorm:"size:8;not null;default:'en'"`
+
+	IncludeAirQuality bool `gorm:"not null;default:false"`
+	AQIAlertThreshold *int `gorm:"column:aqi_alert_threshold"`
 
 	UserID uint
 	User   user.UserModel `gorm:"foreignKey:UserID"`
 
-	IsConfirmed  bool      `gorm:"default:false"`
-	ConfirmToken string    `gorm:"uniqueIndex;size:64"`
+	IsConfirmed bool `gorm:"default:false"`
+	// LegacyConfirmToken is UUID sent in confirmation and unsubscribe links before tokens were signed, it's only
+	// read so links emailed before the upgrade keep working and the column is dropped once they are gone
+	LegacyConfirmToken *string `gorm:"column:confirm_token;size:64"`
+	// TokenVersion is signed into every link token, it's bumped when subscription moves to another address
+	// or is restored after unsubscribe, so links emailed before are rejected
+	TokenVersion uint `gorm:"not null;default:0"`
+	// TokenExpires is when confirmation token expires, tokens signed with other expiry are rejected
 	TokenExpires time.Time `gorm:"not null"`
 	ConfirmedAt  *time.Time
+	// LastSentAt is when weather email task was last queued for subscription
+	LastSentAt *time.Time
+	// ReminderSentAt is when confirmation reminder was sent, at most one is sent per subscription
+	ReminderSentAt *time.Time
+	// PausedAt is set while emails are not delivered, e.g. after address started bouncing
+	PausedAt    *time.Time
+	PauseReason *constants.PauseReason `gorm:"size:32"`
+	// ResumeAt ends pause automatically, pause without it lasts until resumed
+	ResumeAt *time.Time
+	// PendingEmail is address subscription moves to once it's confirmed, current address keeps receiving
+	// emails until then. Email change token is bound to EmailChangeExpires like confirm token to TokenExpires
+	PendingEmail       *string `gorm:"size:255"`
+	EmailChangeExpires *time.Time
+	// PreviousEmails lists lowercased addresses subscription was moved away from, comma separated,
+	// so erasure also reaches emails delivered to them
+	PreviousEmails string `gorm:"not null;default:''"`
 }
 
 func (SubscriptionModel) TableName() string {
 	return "subscriptions"
 }
+
+// emailSeparator joins previous addresses of subscription in a single column
+const emailSeparator = ","
+
+// AddPreviousEmail remembers address subscription is moved away from
+func (m *SubscriptionModel) AddPreviousEmail(email string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, previous := range m.PreviousEmailList() {
+		if previous == email {
+			return
+		}
+	}
+	if m.PreviousEmails != "" {
+		m.PreviousEmails += emailSeparator
+	}
+	m.PreviousEmails += email
+}
+
+// PreviousEmailList returns addresses subscription was moved away from, oldest first
+func (m *SubscriptionModel) PreviousEmailList() []string {
+	if m.PreviousEmails == "" {
+		return nil
+	}
+	return strings.Split(m.PreviousEmails, emailSeparator)
+}
+
+// IsPaused reports whether emails are held back at now, matches FindDuePage filter
+func (m *SubscriptionModel) IsPaused(now time.Time) bool {
+	return m.PausedAt != nil && (m.ResumeAt == nil || now.Before(*m.ResumeAt))
+}
@@ -2,6 +2,8 @@ package subscription
 
 import (
 	"context"
+	"errors"
+	"time"
 	"weatherApi/internal/common/constants"
 	"weatherApi/internal/repository/base"
 
@@ -18,16 +20,175 @@ func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
 	}
 }
 
-func (r *SubscriptionRepository) FindAllSubscriptionsByFrequency(
+// DueOrder sets how due subscriptions are paged, each order keeps subscriptions sent together next to each other
+type DueOrder int
+
+const (
+	// DueByCity orders by normalized city, then id
+	DueByCity DueOrder = iota
+	// DueByUser orders by user, then id
+	DueByUser
+)
+
+// DueCursor is keyset position after the last subscription of previous page, zero value starts from the beginning
+type DueCursor struct {
+	City   string
+	UserID uint
+	ID     uint
+}
+
+// CursorAfter returns cursor of the page ending with sub
+func CursorAfter(sub SubscriptionModel) DueCursor {
+	return DueCursor{City: sub.City, UserID: sub.UserID, ID: sub.ID}
+}
+
+// FindDuePage returns up to limit confirmed and not paused subscriptions with frequency following after in order,
+// paging by key instead of offset keeps every page cheap and doesn't skip rows when earlier ones change
+func (r *SubscriptionRepository) FindDuePage(
 	ctx context.Context,
 	frequency constants.Frequency,
+	order DueOrder,
+	after DueCursor,
+	limit int,
 ) ([]SubscriptionModel, error) {
 	var entities []SubscriptionModel
 
-	result := r.DB.WithContext(ctx).
+	query := r.DB.WithContext(ctx).
 		Preload("User").
 		Where("frequency = ? AND is_confirmed = ?", frequency, true).
+		Where("paused_at IS NULL OR resume_at <= ?", time.Now())
+	switch order {
+	case DueByUser:
+		query = query.
+			Where("(user_id, id) > (?, ?)", after.UserID, after.ID).
+			Order("user_id, id")
+	default:
+		query = query.
+			Where("(LOWER(TRIM(city)), id) > (LOWER(TRIM(?)), ?)", after.City, after.ID).
+			Order("LOWER(TRIM(city)), id")
+	}
+	result := query.Limit(limit).Find(&entities)
+
+	return entities, result.Error
+}
+
+// FindAllByUserIDs returns subscriptions of users including unsubscribed (soft deleted) ones, newest first
+func (r *SubscriptionRepository) FindAllByUserIDs(ctx context.Context, userIDs []uint) ([]SubscriptionModel, error) {
+	var entities []SubscriptionModel
+
+	result := r.DB.WithContext(ctx).
+		Unscoped().
+		Where("user_id IN ?", userIDs).
+		Order("id DESC").
+		Find(&entities)
+
+	return entities, result.Error
+}
+
+// FindOneWithUser returns active subscription by id with its user loaded
+func (r *SubscriptionRepository) FindOneWithUser(ctx context.Context, id uint) (*SubscriptionModel, error) {
+	var entity SubscriptionModel
+
+	result := r.DB.WithContext(ctx).
+		Preload("User").
+		Where("id = ?", id).
+		First(&entity)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, base.ErrNotFound
+	}
+	return &entity, result.Error
+}
+
+// FindLatestDeleted returns the most recently unsubscribed (soft deleted) subscription of user to city,
+// city is compared case and space insensitive
+func (r *SubscriptionRepository) FindLatestDeleted(ctx context.Context, userID uint, city string) (*SubscriptionModel, error) {
+	var entity SubscriptionModel
+
+	result := r.DB.WithContext(ctx).
+		Unscoped().
+		Where("user_id = ? AND LOWER(TRIM(city)) = LOWER(TRIM(?)) AND deleted_at IS NOT NULL", userID, city).
+		Order("deleted_at DESC, id DESC").
+		First(&entity)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, base.ErrNotFound
+	}
+	return &entity, result.Error
+}
+
+// Restore saves soft deleted subscription making it active again, history columns are kept
+func (r *SubscriptionRepository) Restore(ctx context.Context, entity *SubscriptionModel) error {
+	entity.DeletedAt = gorm.DeletedAt{}
+	return r.DB.WithContext(ctx).Unscoped().Save(entity).Error
+}
+
+// MarkSent stores time of the last queued weather email for subscriptions
+func (r *SubscriptionRepository) MarkSent(ctx context.Context, ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).
+		Model(&SubscriptionModel{}).
+		Where("id IN ?", ids).
+		UpdateColumn("last_sent_at", at).Error
+}
+
+// FindExpiringUnconfirmed returns up to limit unconfirmed subscriptions with token expiring in (from, to]
+// which weren't reminded yet, with user loaded
+func (r *SubscriptionRepository) FindExpiringUnconfirmed(
+	ctx context.Context,
+	from, to time.Time,
+	limit int,
+) ([]SubscriptionModel, error) {
+	var entities []SubscriptionModel
+
+	result := r.DB.WithContext(ctx).
+		Preload("User").
+		Where("is_confirmed = ? AND reminder_sent_at IS NULL AND token_expires > ? AND token_expires <= ?", false, from, to).
+		Order("token_expires").
+		Limit(limit).
 		Find(&entities)
 
 	return entities, result.Error
 }
+
+// MarkReminded stores time confirmation reminder was sent for subscriptions
+func (r *SubscriptionRepository) MarkReminded(ctx context.Context, ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).
+		Model(&SubscriptionModel{}).
+		Where("id IN ?", ids).
+		UpdateColumn("reminder_sent_at", at).Error
+}
+
+// DeleteExpiredUnconfirmed hard-deletes up to limit unconfirmed subscriptions, unsubscribed ones included,
+// whose token expired before cutoff, returns number of deleted rows
+func (r *SubscriptionRepository) DeleteExpiredUnconfirmed(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := r.DB.WithContext(ctx).Exec(`
+		DELETE FROM subscriptions WHERE id IN (
+			SELECT id FROM subscriptions
+			WHERE is_confirmed = FALSE AND token_expires < ?
+			ORDER BY id
+			LIMIT ?
+		)`, before, limit)
+	return result.RowsAffected, result.Error
+}
+
+// PauseByEmail pauses all subscriptions of user with email which aren't paused yet or are paused by subscriber,
+// such pause has no resume date. Returns number of paused subscriptions
+func (r *SubscriptionRepository) PauseByEmail(
+	ctx context.Context,
+	email string,
+	reason constants.PauseReason,
+	at time.Time,
+) (int64, error) {
+	result := r.DB.WithContext(ctx).
+		Model(&SubscriptionModel{}).
+		Where("(paused_at IS NULL OR pause_reason = ?) AND user_id IN (SELECT id FROM users WHERE lower(email) = lower(?))",
+			constants.PauseUser, email).
+		UpdateColumns(map[string]any{"paused_at": at, "pause_reason": reason, "resume_at": nil})
+	return result.RowsAffected, result.Error
+}
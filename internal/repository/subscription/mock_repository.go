@@ -2,15 +2,34 @@ package subscription
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/base"
 )
 
 type MockSubscriptionRepository struct {
-	FindOneOrNoneFn                   func(query any, args ...any) (*SubscriptionModel, error)
-	CreateOneFn                       func(entity *SubscriptionModel) error
-	UpdateFn                          func(entity *SubscriptionModel) error
-	DeleteFn                          func(entity *SubscriptionModel) error
-	FindAllSubscriptionsByFrequencyFn func() ([]SubscriptionModel, error)
+	// Due is paged by FindDuePage, DueErr fails it once DueErrAfter pages were served and DuePages counts served pages
+	Due         []SubscriptionModel
+	DueErr      error
+	DueErrAfter int
+	DuePages    int
+
+	FindOneOrNoneFn            func(query any, args ...any) (*SubscriptionModel, error)
+	CreateOneFn                func(entity *SubscriptionModel) error
+	UpdateFn                   func(entity *SubscriptionModel) error
+	DeleteFn                   func(entity *SubscriptionModel) error
+	FindAllByUserIDsFn         func(userIDs []uint) ([]SubscriptionModel, error)
+	FindOneWithUserFn          func(id uint) (*SubscriptionModel, error)
+	MarkSentFn                 func(ids []uint, at time.Time) error
+	FindExpiringUnconfirmedFn  func(from, to time.Time, limit int) ([]SubscriptionModel, error)
+	MarkRemindedFn             func(ids []uint, at time.Time) error
+	DeleteExpiredUnconfirmedFn func(before time.Time, limit int) (int64, error)
+	FindLatestDeletedFn        func(userID uint, city string) (*SubscriptionModel, error)
+	RestoreFn                  func(entity *SubscriptionModel) error
+	PauseByEmailFn             func(email string, reason constants.PauseReason, at time.Time) (int64, error)
 }
 
 func (m *MockSubscriptionRepository) FindOneOrNone(_ context.Context, q any, args ...any) (*SubscriptionModel, error) {
@@ -37,6 +56,86 @@ func (m *MockSubscriptionRepository) FindOneOrCreate(
 	return &SubscriptionModel{}, nil
 }
 
-func (m *MockSubscriptionRepository) FindAllSubscriptionsByFrequency(ctx context.Context, frequency constants.Frequency) ([]SubscriptionModel, error) {
-	return m.FindAllSubscriptionsByFrequencyFn()
+func (m *MockSubscriptionRepository) FindDuePage(
+	_ context.Context,
+	_ constants.Frequency,
+	order DueOrder,
+	after DueCursor,
+	limit int,
+) ([]SubscriptionModel, error) {
+	if m.DueErr != nil && m.DuePages >= m.DueErrAfter {
+		return nil, m.DueErr
+	}
+	m.DuePages++
+	less := func(a, b DueCursor) bool {
+		if order == DueByUser && a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		if cityA, cityB := normalizeCity(a.City), normalizeCity(b.City); order == DueByCity && cityA != cityB {
+			return cityA < cityB
+		}
+		return a.ID < b.ID
+	}
+	sorted := slices.Clone(m.Due)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(CursorAfter(sorted[i]), CursorAfter(sorted[j]))
+	})
+	var page []SubscriptionModel
+	for _, sub := range sorted {
+		if len(page) < limit && less(after, CursorAfter(sub)) {
+			page = append(page, sub)
+		}
+	}
+	return page, nil
+}
+
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+func (m *MockSubscriptionRepository) FindAllByUserIDs(_ context.Context, userIDs []uint) ([]SubscriptionModel, error) {
+	return m.FindAllByUserIDsFn(userIDs)
+}
+
+func (m *MockSubscriptionRepository) FindOneWithUser(_ context.Context, id uint) (*SubscriptionModel, error) {
+	return m.FindOneWithUserFn(id)
+}
+
+func (m *MockSubscriptionRepository) MarkSent(_ context.Context, ids []uint, at time.Time) error {
+	if m.MarkSentFn == nil {
+		return nil
+	}
+	return m.MarkSentFn(ids, at)
+}
+
+func (m *MockSubscriptionRepository) FindExpiringUnconfirmed(_ context.Context, from, to time.Time, limit int) ([]SubscriptionModel, error) {
+	return m.FindExpiringUnconfirmedFn(from, to, limit)
+}
+
+func (m *MockSubscriptionRepository) MarkReminded(_ context.Context, ids []uint, at time.Time) error {
+	return m.MarkRemindedFn(ids, at)
+}
+
+func (m *MockSubscriptionRepository) DeleteExpiredUnconfirmed(_ context.Context, before time.Time, limit int) (int64, error) {
+	return m.DeleteExpiredUnconfirmedFn(before, limit)
+}
+
+func (m *MockSubscriptionRepository) PauseByEmail(
+	_ context.Context,
+	email string,
+	reason constants.PauseReason,
+	at time.Time,
+) (int64, error) {
+	return m.PauseByEmailFn(email, reason, at)
+}
+
+func (m *MockSubscriptionRepository) FindLatestDeleted(_ context.Context, userID uint, city string) (*SubscriptionModel, error) {
+	if m.FindLatestDeletedFn == nil {
+		return nil, base.ErrNotFound
+	}
+	return m.FindLatestDeletedFn(userID, city)
+}
+
+func (m *MockSubscriptionRepository) Restore(_ context.Context, entity *SubscriptionModel) error {
+	return m.RestoreFn(entity)
 }
@@ -0,0 +1,26 @@
+package suppression
+
+import (
+	"time"
+	"weatherApi/internal/common/constants"
+)
+
+// SuppressionModel accumulates delivery feedback per address, emails aren't sent once SuppressedAt is set
+type SuppressionModel struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Email        string                   `gorm:"size:255;uniqueIndex;not null"`
+	HardBounces  int                      `gorm:"not null;default:0"`
+	Complaints   int                      `gorm:"not null;default:0"`
+	LastEvent    constants.EmailEventType `gorm:"size:16;not null"`
+	LastEventAt  time.Time                `gorm:"not null"`
+	Source       string                   `gorm:"size:32;not null"`
+	Details      string                   `gorm:"not null;default:''"`
+	SuppressedAt *time.Time
+}
+
+func (SuppressionModel) TableName() string {
+	return "email_suppressions"
+}
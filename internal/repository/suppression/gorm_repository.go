@@ -0,0 +1,89 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/repository/base"
+
+	"gorm.io/gorm"
+)
+
+type SuppressionRepositoryInterface interface {
+	RecordEvent(ctx context.Context, event dto.EmailEvent, hardBounceLimit int) (*SuppressionModel, error)
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+type SuppressionRepository struct {
+	*base.BaseRepository[SuppressionModel]
+}
+
+func NewSuppressionRepository(db *gorm.DB) *SuppressionRepository {
+	return &SuppressionRepository{
+		BaseRepository: base.NewRepository[SuppressionModel](db),
+	}
+}
+
+// RecordEvent adds hard bounce or complaint to address counters in a single upsert, address is suppressed
+// on first complaint or once hardBounceLimit hard bounces are reached and stays suppressed afterwards
+func (r *SuppressionRepository) RecordEvent(
+	ctx context.Context,
+	event dto.EmailEvent,
+	hardBounceLimit int,
+) (*SuppressionModel, error) {
+	hardBounces, complaints := 0, 0
+	switch event.Type {
+	case constants.EmailHardBounce:
+		hardBounces = 1
+	case constants.EmailComplaint:
+		complaints = 1
+	default:
+		return nil, errors.New("only hard bounces and complaints are recorded")
+	}
+
+	var entity SuppressionModel
+	result := r.DB.WithContext(ctx).Raw(`
+		INSERT INTO email_suppressions AS s
+			(created_at, updated_at, email, hard_bounces, complaints, last_event, last_event_at, source, details, suppressed_at)
+		VALUES (now(), now(), @email, @hard, @complaints, @event, @at, @source, @details,
+			CASE WHEN @complaints > 0 OR @hard >= @limit THEN @at::timestamp END)
+		ON CONFLICT (email) DO UPDATE SET
+			updated_at = now(),
+			hard_bounces = s.hard_bounces + EXCLUDED.hard_bounces,
+			complaints = s.complaints + EXCLUDED.complaints,
+			last_event = EXCLUDED.last_event,
+			last_event_at = EXCLUDED.last_event_at,
+			source = EXCLUDED.source,
+			details = EXCLUDED.details,
+			suppressed_at = COALESCE(s.suppressed_at, CASE
+				WHEN EXCLUDED.complaints > 0 OR s.hard_bounces + EXCLUDED.hard_bounces >= @limit THEN EXCLUDED.last_event_at
+			END)
+		RETURNING *`,
+		map[string]any{
+			"email":      normalizeEmail(event.Email),
+			"hard":       hardBounces,
+			"complaints": complaints,
+			"event":      event.Type,
+			"at":         event.OccurredAt,
+			"source":     event.Source,
+			"details":    event.Details,
+			"limit":      hardBounceLimit,
+		},
+	).Scan(&entity)
+	return &entity, result.Error
+}
+
+func (r *SuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).
+		Model(&SuppressionModel{}).
+		Where("email = ? AND suppressed_at IS NOT NULL", normalizeEmail(email)).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,63 @@
+package suppression
+
+import (
+	"context"
+	"sync"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+// MockSuppressionRepository keeps counters in memory and applies the same suppression rules as the database
+type MockSuppressionRepository struct {
+	mu      sync.Mutex
+	Entries map[string]*SuppressionModel
+	Err     error
+}
+
+func (m *MockSuppressionRepository) RecordEvent(
+	_ context.Context,
+	event dto.EmailEvent,
+	hardBounceLimit int,
+) (*SuppressionModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.Entries == nil {
+		m.Entries = make(map[string]*SuppressionModel)
+	}
+	email := normalizeEmail(event.Email)
+	entity, ok := m.Entries[email]
+	if !ok {
+		entity = &SuppressionModel{ID: uint(len(m.Entries) + 1), Email: email, CreatedAt: event.OccurredAt}
+		m.Entries[email] = entity
+	}
+	switch event.Type {
+	case constants.EmailHardBounce:
+		entity.HardBounces++
+	case constants.EmailComplaint:
+		entity.Complaints++
+	}
+	entity.UpdatedAt = event.OccurredAt
+	entity.LastEvent = event.Type
+	entity.LastEventAt = event.OccurredAt
+	entity.Source = event.Source
+	entity.Details = event.Details
+	if entity.SuppressedAt == nil && (entity.Complaints > 0 || entity.HardBounces >= hardBounceLimit) {
+		at := event.OccurredAt
+		entity.SuppressedAt = &at
+	}
+	copied := *entity
+	return &copied, nil
+}
+
+func (m *MockSuppressionRepository) IsSuppressed(_ context.Context, email string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return false, m.Err
+	}
+	entity, ok := m.Entries[normalizeEmail(email)]
+	return ok && entity.SuppressedAt != nil, nil
+}
@@ -4,7 +4,7 @@ import "gorm.io/gorm"
 
 type UserModel struct {
 	gorm.Model
-	Email string `gorm:"unique;size:32"`
+	Email string `gorm:"unique;size:255"`
 }
 
 func (UserModel) TableName() string {
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"strings"
 	"weatherApi/internal/repository/base"
 
 	"gorm.io/gorm"
@@ -13,6 +14,7 @@ type UserRepositoryInterface interface {
 	Update(ctx context.Context, entity *UserModel) error
 	Delete(ctx context.Context, entity *UserModel) error
 	FindOneOrCreate(ctx context.Context, conditions map[string]any, entity *UserModel) (*UserModel, error)
+	SearchByEmail(ctx context.Context, query string, limit int) ([]UserModel, error)
 }
 
 type UserRepository struct {
@@ -24,3 +26,21 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 		BaseRepository: base.NewRepository[UserModel](db),
 	}
 }
+
+// SearchByEmail returns up to limit users whose email contains query, case-insensitive
+func (r *UserRepository) SearchByEmail(ctx context.Context, query string, limit int) ([]UserModel, error) {
+	var entities []UserModel
+	pattern := "%" + escapeLike(strings.TrimSpace(query)) + "%"
+
+	result := r.DB.WithContext(ctx).
+		Where("email ILIKE ?", pattern).
+		Order("email").
+		Limit(limit).
+		Find(&entities)
+
+	return entities, result.Error
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -4,9 +4,14 @@ import "context"
 
 type MockUserRepository struct {
 	FindOneOrCreateFn func(conditions map[string]any, entity *UserModel) (*UserModel, error)
+	SearchByEmailFn   func(query string, limit int) ([]UserModel, error)
+	FindOneOrNoneFn   func(query any, args ...any) (*UserModel, error)
 }
 
 func (m *MockUserRepository) FindOneOrNone(ctx context.Context, q any, args ...any) (*UserModel, error) {
+	if m.FindOneOrNoneFn != nil {
+		return m.FindOneOrNoneFn(q, args...)
+	}
 	return &UserModel{}, nil
 }
 
@@ -25,3 +30,7 @@ func (m *MockUserRepository) Delete(ctx context.Context, e *UserModel) error {
 func (m *MockUserRepository) FindOneOrCreate(ctx context.Context, c map[string]any, e *UserModel) (*UserModel, error) {
 	return m.FindOneOrCreateFn(c, e)
 }
+
+func (m *MockUserRepository) SearchByEmail(ctx context.Context, query string, limit int) ([]UserModel, error) {
+	return m.SearchByEmailFn(query, limit)
+}
@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"weatherApi/internal/appctx"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/common/tokens"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/subscription"
+	serviceAirQuality "weatherApi/internal/service/airquality"
+	serviceWeather "weatherApi/internal/service/weather"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type DeliveryRepositoryInterface interface {
+	MarkSent(ctx context.Context, ids []uint, at time.Time) error
+}
+
+// Dispatcher fetches weather for subscriptions sharing city and options and queues a single
+// email task for all of them, used by scheduled jobs and by admin "send now" action. In digest mode
+// scheduled jobs queue one email per user instead, combining all cities due in the run
+type Dispatcher struct {
+	log            *logger.Logger
+	deliveryRepo   DeliveryRepositoryInterface
+	publisher      broker.EventPublisher
+	weatherService *serviceWeather.Service
+	airQuality     *serviceAirQuality.Service
+	signer         *tokens.Signer
+}
+
+func NewDispatcher(
+	log *logger.Logger,
+	deliveryRepo DeliveryRepositoryInterface,
+	publisher broker.EventPublisher,
+	weatherService *serviceWeather.Service,
+	airQualityService *serviceAirQuality.Service,
+	signer *tokens.Signer,
+) *Dispatcher {
+	return &Dispatcher{
+		log:            log,
+		deliveryRepo:   deliveryRepo,
+		publisher:      publisher,
+		weatherService: weatherService,
+		airQuality:     airQualityService,
+		signer:         signer,
+	}
+}
+
+// Dispatch sends weather to subs, all of them must belong to the same notification group,
+// failures are reported to DLQ and returned
+func (d *Dispatcher) Dispatch(ctx context.Context, subs []subscription.SubscriptionModel) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	group := groupOf(subs[0])
+	city := group.city
+
+	weather, appErr := d.weatherService.GetWeather(ctx, dto.NewCityLocation(city), group.options)
+	if appErr != nil {
+		return d.HandleError(fmt.Sprintf("failed to fetch weather for city=%s", city), appErr)
+	}
+
+	users := make([]dto.UserData, len(subs))
+	ids := make([]uint, len(subs))
+	wantsAirQuality := false
+	for i, sub := range subs {
+		user, err := d.userData(sub, group.options.Lang)
+		if err != nil {
+			return d.HandleError(fmt.Sprintf("failed to sign tokens for subscription=%d", sub.ID), err)
+		}
+		users[i] = *user
+		ids[i] = sub.ID
+		wantsAirQuality = wantsAirQuality || users[i].WantsAirQuality()
+	}
+
+	task := dto.WeatherSubData{
+		City:    subs[0].City,
+		Users:   users,
+		Weather: *weather,
+	}
+	if wantsAirQuality && d.airQuality != nil {
+		// weather is still delivered when air quality is unavailable
+		airQuality, err := d.airQuality.GetAirQuality(ctx, dto.NewCityLocation(city))
+		if err != nil {
+			d.log.FromContext(ctx).Error().Err(err).Msgf("Failed to fetch air quality for city=%s", city)
+		} else {
+			task.AirQuality = airQuality
+		}
+	}
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return d.HandleError(fmt.Sprintf("error marshaling event for %s", city), err)
+	}
+	traceID := appctx.GetTraceID(ctx)
+	if err := d.publisher.Publish(broker.SendSubscriptionWeatherData, payload, broker.WithHeaders(amqp.Table{constants.HdrTraceID: traceID})); err != nil {
+		return d.HandleError(fmt.Sprintf("failed to publish notification for %s", city), err)
+	}
+
+	// email is already queued, failing to record it must not cause a resend
+	if err := d.deliveryRepo.MarkSent(ctx, ids, time.Now()); err != nil {
+		d.log.FromContext(ctx).Error().Err(err).Msgf("Failed to mark subscriptions for %s as sent", city)
+	}
+	return nil
+}
+
+// DispatchDigest sends weather of all subs of one user as a single email with a section per city.
+// Cities failing to fetch are left out and retried on the next run, error is returned only when
+// nothing could be sent
+func (d *Dispatcher) DispatchDigest(ctx context.Context, subs []subscription.SubscriptionModel) error {
+	if len(subs) == 0 {
+		return nil
+	}
+	log := d.log.FromContext(ctx)
+	email := subs[0].User.Email
+
+	task := dto.WeatherDigestTask{
+		Email: email,
+		Lang:  groupOf(subs[0]).options.Lang,
+	}
+	ids := make([]uint, 0, len(subs))
+	for _, sub := range subs {
+		group := groupOf(sub)
+		weather, appErr := d.weatherService.GetWeather(ctx, dto.NewCityLocation(group.city), group.options)
+		if appErr != nil {
+			log.Error().Err(appErr).Msgf("Failed to fetch weather for city=%s, leaving it out of digest", group.city)
+			continue
+		}
+		user, err := d.userData(sub, group.options.Lang)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to sign tokens for subscription=%d, leaving it out of digest", sub.ID)
+			continue
+		}
+
+		section := dto.DigestSection{
+			City:    sub.City,
+			User:    *user,
+			Weather: *weather,
+		}
+		if user.WantsAirQuality() && d.airQuality != nil {
+			airQuality, err := d.airQuality.GetAirQuality(ctx, dto.NewCityLocation(group.city))
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to fetch air quality for city=%s", group.city)
+			} else {
+				section.AirQuality = airQuality
+			}
+		}
+		task.Sections = append(task.Sections, section)
+		ids = append(ids, sub.ID)
+	}
+	if len(task.Sections) == 0 {
+		return d.handleDigestError(fmt.Sprintf("failed to build digest for %s", email), errors.New("no city weather available"))
+	}
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return d.handleDigestError(fmt.Sprintf("error marshaling digest for %s", email), err)
+	}
+	traceID := appctx.GetTraceID(ctx)
+	if err := d.publisher.Publish(broker.SendDigestWeatherData, payload, broker.WithHeaders(amqp.Table{constants.HdrTraceID: traceID})); err != nil {
+		return d.handleDigestError(fmt.Sprintf("failed to publish digest for %s", email), err)
+	}
+
+	if err := d.deliveryRepo.MarkSent(ctx, ids, time.Now()); err != nil {
+		log.Error().Err(err).Msgf("Failed to mark digest subscriptions of %s as sent", email)
+	}
+	return nil
+}
+
+// HandleError logs failure and publishes its description to DLQ, err is returned unchanged
+func (d *Dispatcher) HandleError(msg string, err error) error {
+	d.log.Base().Error().Err(err).Msg(msg)
+	if dlqErr := d.publisher.Publish(broker.SendSubscriptionWeatherData.DLQ(), []byte(msg)); dlqErr != nil {
+		d.log.Base().Error().Err(dlqErr).Msg("error sending event to DLQ")
+	}
+	return err
+}
+
+func (d *Dispatcher) handleDigestError(msg string, err error) error {
+	d.log.Base().Error().Err(err).Msg(msg)
+	if dlqErr := d.publisher.Publish(broker.SendDigestWeatherData.DLQ(), []byte(msg)); dlqErr != nil {
+		d.log.Base().Error().Err(dlqErr).Msg("error sending event to DLQ")
+	}
+	return err
+}
+
+// userData issues links of sub which are embedded into weather email
+func (d *Dispatcher) userData(sub subscription.SubscriptionModel, lang string) (*dto.UserData, error) {
+	unsubscribeToken, err := d.signer.Issue(tokens.PurposeUnsubscribe, sub.ID, sub.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	manageToken, err := d.signer.Issue(tokens.PurposeManage, sub.ID, sub.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserData{
+		Email:             sub.User.Email,
+		UnsubscribeToken:  unsubscribeToken,
+		ManageToken:       manageToken,
+		Lang:              lang,
+		IncludeAirQuality: sub.IncludeAirQuality,
+		AQIAlertThreshold: sub.AQIAlertThreshold,
+	}, nil
+}
+
+func groupOf(sub subscription.SubscriptionModel) notificationGroup {
+	return notificationGroup{
+		city:    strings.ToLower(strings.TrimSpace(sub.City)),
+		options: dto.WeatherOptions{Units: sub.Units, Lang: sub.Lang}.Normalize(),
+	}
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"time"
+	"weatherApi/internal/appctx"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/jobrun"
+
+	"github.com/google/uuid"
+)
+
+type JobRunRepositoryInterface interface {
+	Claim(ctx context.Context, run *jobrun.JobRunModel, staleBefore time.Time) (bool, error)
+	Heartbeat(ctx context.Context, run *jobrun.JobRunModel, at time.Time) (bool, error)
+	Finish(ctx context.Context, run *jobrun.JobRunModel) (bool, error)
+	LatestSlot(ctx context.Context, job string, staleBefore time.Time) (*time.Time, error)
+}
+
+// RunStats summarizes a single notification run
+type RunStats struct {
+	Cities     int
+	Recipients int
+	Failures   int
+}
+
+// WithRunHistory records every notification run, so each slot is sent once across restarts and replicas.
+// Slot missed while no instance was running is caught up at startup when it is not older than catchUpWindow,
+// zero window disables catch-up. Running run sends heartbeat every third of runTimeout, run not heard of
+// for runTimeout is taken for crashed and its slot counts as missed, zero timeout never gives up on running runs.
+// Failed runs are not retried, as part of their emails may have been sent already
+func (s *Service) WithRunHistory(runs JobRunRepositoryInterface, catchUpWindow, runTimeout time.Duration) *Service {
+	s.runs = runs
+	s.catchUpWindow = catchUpWindow
+	s.runTimeout = runTimeout
+	return s
+}
+
+// staleBefore returns time before which run still running and not heard of since is taken for crashed
+func (s *Service) staleBefore() time.Time {
+	if s.runTimeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-s.runTimeout)
+}
+
+// slotOf returns scheduled time of the latest frequency run due at now, false when there is none,
+// hourly emails are not sent at dailyHour as daily ones go out then
+func slotOf(frequency constants.Frequency, now time.Time) (time.Time, bool) {
+	hour := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	switch frequency {
+	case constants.FrequencyHourly:
+		return hour, hour.Hour() != dailyHour
+	case constants.FrequencyDaily:
+		slot := time.Date(now.Year(), now.Month(), now.Day(), dailyHour, 0, 0, 0, now.Location())
+		if now.Before(slot) {
+			slot = slot.AddDate(0, 0, -1)
+		}
+		return slot, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// runNotification sends notifications of slot due at now and records the run,
+// slot already claimed by another run is skipped. Sending is cancelled once another instance took the run over
+func (s *Service) runNotification(ctx context.Context, frequency constants.Frequency, now time.Time, catchUp bool) error {
+	log := s.log.FromContext(ctx)
+	slot, ok := slotOf(frequency, now)
+	if !ok {
+		log.Info().Msgf("%s job skipped, cause of daily job", frequency)
+		return nil
+	}
+	if s.runs == nil {
+		_, err := s.SendNotification(ctx, frequency)
+		return err
+	}
+
+	run := &jobrun.JobRunModel{
+		Job:      string(frequency),
+		Slot:     slot,
+		CatchUp:  catchUp,
+		Status:   constants.JobRunRunning,
+		Instance: s.instance(),
+		// started_at fences updates of the run, so it's kept in precision of database
+		StartedAt: time.Now().Truncate(time.Microsecond),
+	}
+	claimed, err := s.runs.Claim(ctx, run, s.staleBefore())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		log.Info().Msgf("%s run of %s already recorded, skipped", frequency, slot.Format(time.RFC3339))
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopHeartbeat := s.heartbeat(runCtx, cancel, run)
+	stats, sendErr := s.SendNotification(runCtx, frequency)
+	stopHeartbeat()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Cities = stats.Cities
+	run.Recipients = stats.Recipients
+	run.Failures = stats.Failures
+	run.Status = constants.JobRunSucceeded
+	if sendErr != nil {
+		run.Status = constants.JobRunFailed
+		run.Error = sendErr.Error()
+	}
+	finished, err := s.runs.Finish(ctx, run)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to record %s run %d", frequency, run.ID)
+	} else if !finished {
+		log.Warn().Msgf("%s run %d was taken over by another instance, its outcome is not recorded", frequency, run.ID)
+	}
+	return sendErr
+}
+
+// heartbeat keeps claimed run from being taken for crashed while it is sent and cancels sending once
+// another instance took the run over anyway. Returned func stops heartbeat
+func (s *Service) heartbeat(ctx context.Context, cancel context.CancelFunc, run *jobrun.JobRunModel) func() {
+	if s.runTimeout <= 0 {
+		return func() {}
+	}
+	log := s.log.FromContext(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.runTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				alive, err := s.runs.Heartbeat(ctx, run, time.Now())
+				if err != nil {
+					log.Warn().Err(err).Msgf("Failed to record heartbeat of %s run %d", run.Job, run.ID)
+					continue
+				}
+				if !alive {
+					log.Error().Msgf("%s run %d was taken over by another instance, sending is stopped", run.Job, run.ID)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// CatchUp runs the latest slot due at now of each notification job which was missed, i.e. is newer than
+// the last recorded run and not older than catch-up window. Run of crashed instance not heard of for run timeout
+// doesn't count as recorded, failed run does. Jobs that never ran have nothing to catch up
+func (s *Service) CatchUp(ctx context.Context, now time.Time) {
+	if s.runs == nil || s.catchUpWindow <= 0 {
+		return
+	}
+	log := s.log.FromContext(ctx)
+	for _, frequency := range []constants.Frequency{constants.FrequencyDaily, constants.FrequencyHourly} {
+		slot, ok := slotOf(frequency, now)
+		if !ok || now.Sub(slot) > s.catchUpWindow {
+			continue
+		}
+		latest, err := s.runs.LatestSlot(ctx, string(frequency), s.staleBefore())
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to check missed %s runs", frequency)
+			continue
+		}
+		if latest == nil || !latest.Before(slot) {
+			continue
+		}
+		log.Warn().Msgf("Catching up %s run of %s missed since %s", frequency, slot.Format(time.RFC3339), latest.Format(time.RFC3339))
+		if err := s.runNotification(ctx, frequency, now, true); err != nil {
+			log.Error().Err(err).Msgf("Error processing %s catch-up", frequency)
+		}
+	}
+}
+
+// startCatchUp catches up missed slots in background, with leader election it is done by the instance
+// once it becomes leader
+func (s *Service) startCatchUp() {
+	catchUp := func() {
+		s.CatchUp(appctx.SetTraceID(s.ctx, uuid.NewString()), time.Now())
+	}
+	if s.leader != nil {
+		s.leader.OnElected(catchUp)
+		return
+	}
+	go catchUp()
+}
+
+func (s *Service) instance() string {
+	if s.leader != nil {
+		return s.leader.Holder()
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/metrics"
+
+	"github.com/google/uuid"
+)
+
+const defaultLeaseTTL = 15 * time.Second
+
+type LeaseRepositoryInterface interface {
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+}
+
+// LeaderElector keeps scheduler lease while this instance is alive, so only one of replicas runs jobs.
+// Lease is renewed every third of TTL, when leader dies another instance takes over once lease expires
+type LeaderElector struct {
+	log     *logger.Logger
+	leases  LeaseRepositoryInterface
+	metrics *metrics.SchedulerMetrics
+	name    string
+	holder  string
+	ttl     time.Duration
+
+	leader    atomic.Bool
+	onElected func()
+	stop      context.CancelFunc
+	done      sync.WaitGroup
+}
+
+// NewLeaderElector creates elector of lease name for instance named holder, empty holder is derived
+// from hostname, metrics are optional
+func NewLeaderElector(log *logger.Logger, leases LeaseRepositoryInterface, name, holder string, ttl time.Duration, metrics *metrics.SchedulerMetrics) *LeaderElector {
+	if holder == "" {
+		hostname, _ := os.Hostname()
+		holder = hostname + "-" + uuid.NewString()[:8]
+	}
+	if ttl <= 0 {
+		ttl = defaultLeaseTTL
+	}
+	return &LeaderElector{
+		log:     log,
+		leases:  leases,
+		metrics: metrics,
+		name:    name,
+		holder:  holder,
+		ttl:     ttl,
+	}
+}
+
+func (e *LeaderElector) Holder() string {
+	return e.holder
+}
+
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// OnElected registers fn to run in background every time this instance becomes leader,
+// must be called before Start
+func (e *LeaderElector) OnElected(fn func()) {
+	e.onElected = fn
+}
+
+// Start campaigns for lease in background until Stop is called
+func (e *LeaderElector) Start(ctx context.Context) {
+	ctx, e.stop = context.WithCancel(ctx)
+	e.done.Add(1)
+	go func() {
+		defer e.done.Done()
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+		for {
+			e.Campaign(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Campaign acquires or renews lease once. Instance which failed to reach lease store steps down,
+// running jobs twice is worse than skipping them until store is back
+func (e *LeaderElector) Campaign(ctx context.Context) {
+	acquired, err := e.leases.Acquire(ctx, e.name, e.holder, e.ttl)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		e.log.Base().Error().Err(err).Msgf("Failed to renew scheduler lease for %s", e.holder)
+		acquired = false
+	}
+	e.setLeader(acquired)
+}
+
+// Stop ends campaign and releases lease, so another instance takes over without waiting for expiry
+func (e *LeaderElector) Stop(ctx context.Context) error {
+	if e.stop != nil {
+		e.stop()
+		e.done.Wait()
+	}
+	if !e.leader.Load() {
+		return nil
+	}
+	e.setLeader(false)
+	return e.leases.Release(ctx, e.name, e.holder)
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	if leader {
+		e.log.Base().Info().Msgf("Instance %s became scheduler leader", e.holder)
+		if e.onElected != nil {
+			go e.onElected()
+		}
+	} else {
+		e.log.Base().Warn().Msgf("Instance %s is no longer scheduler leader", e.holder)
+	}
+	if e.metrics != nil {
+		e.metrics.SetLeader(leader)
+	}
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+	"weatherApi/internal/metrics"
+	"weatherApi/internal/repository/subscription"
+
+	appErrors "weatherApi/internal/common/errors"
+)
+
+const defaultMaintenanceBatch = 500
+
+type MaintenanceRepositoryInterface interface {
+	FindExpiringUnconfirmed(ctx context.Context, from, to time.Time, limit int) ([]subscription.SubscriptionModel, error)
+	MarkReminded(ctx context.Context, ids []uint, at time.Time) error
+	DeleteExpiredUnconfirmed(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type OrphanRepositoryInterface interface {
+	FindOrphanedUserIDs(ctx context.Context, createdBefore time.Time, limit int) ([]uint, error)
+	EraseUsers(ctx context.Context, userIDs []uint) error
+}
+
+type ReminderSenderInterface interface {
+	ResendConfirmation(ctx context.Context, sub *subscription.SubscriptionModel) *appErrors.AppError
+}
+
+// Maintenance configures cleanup of unconfirmed subscriptions. Expired rows are kept for ExpiredGrace,
+// so support can still resend confirmation, zero ReminderBefore disables reminders and metrics are optional.
+// Non positive Interval disables periodic job, RunMaintenance can still be called directly
+type Maintenance struct {
+	Subscriptions MaintenanceRepositoryInterface
+	Users         OrphanRepositoryInterface
+	Reminders     ReminderSenderInterface
+	Metrics       *metrics.MaintenanceMetrics
+
+	Interval       time.Duration
+	ExpiredGrace   time.Duration
+	ReminderBefore time.Duration
+	BatchSize      int
+}
+
+type MaintenanceResult struct {
+	Reminded             int
+	DeletedSubscriptions int
+	DeletedUsers         int
+}
+
+// WithMaintenance enables periodic cleanup of expired unconfirmed subscriptions and orphaned users
+func (s *Service) WithMaintenance(maintenance Maintenance) *Service {
+	if maintenance.BatchSize <= 0 {
+		maintenance.BatchSize = defaultMaintenanceBatch
+	}
+	s.maintenance = &maintenance
+	return s
+}
+
+// RunMaintenance sends due reminders, then deletes expired subscriptions and users left without any,
+// each step works in batches until nothing is left
+func (s *Service) RunMaintenance(ctx context.Context) (MaintenanceResult, error) {
+	var result MaintenanceResult
+	m := s.maintenance
+	now := time.Now()
+
+	if m.ReminderBefore > 0 {
+		reminded, err := s.sendReminders(ctx, now)
+		result.Reminded = reminded
+		if err != nil {
+			return s.finishMaintenance(result, err)
+		}
+	}
+
+	for {
+		deleted, err := m.Subscriptions.DeleteExpiredUnconfirmed(ctx, now.Add(-m.ExpiredGrace), m.BatchSize)
+		result.DeletedSubscriptions += int(deleted)
+		if err != nil {
+			return s.finishMaintenance(result, err)
+		}
+		if int(deleted) < m.BatchSize {
+			break
+		}
+	}
+
+	for {
+		ids, err := m.Users.FindOrphanedUserIDs(ctx, now.Add(-m.ExpiredGrace), m.BatchSize)
+		if err != nil {
+			return s.finishMaintenance(result, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if err := m.Users.EraseUsers(ctx, ids); err != nil {
+			return s.finishMaintenance(result, err)
+		}
+		result.DeletedUsers += len(ids)
+		if len(ids) < m.BatchSize {
+			break
+		}
+	}
+	return s.finishMaintenance(result, nil)
+}
+
+// sendReminders reminds once about tokens expiring within ReminderBefore, failed sends are not retried
+// so a broken address can't block the batch
+func (s *Service) sendReminders(ctx context.Context, now time.Time) (int, error) {
+	m := s.maintenance
+	log := s.log.FromContext(ctx)
+	reminded := 0
+	for {
+		subs, err := m.Subscriptions.FindExpiringUnconfirmed(ctx, now, now.Add(m.ReminderBefore), m.BatchSize)
+		if err != nil {
+			return reminded, err
+		}
+		if len(subs) == 0 {
+			return reminded, nil
+		}
+		ids := make([]uint, len(subs))
+		for i := range subs {
+			ids[i] = subs[i].ID
+			if appErr := m.Reminders.ResendConfirmation(ctx, &subs[i]); appErr != nil {
+				log.Error().Err(appErr).Msgf("Failed to send confirmation reminder for subscription %d", subs[i].ID)
+				s.incReminder("failed")
+				continue
+			}
+			reminded++
+			s.incReminder("sent")
+		}
+		if err := m.Subscriptions.MarkReminded(ctx, ids, now); err != nil {
+			return reminded, err
+		}
+		if len(subs) < m.BatchSize {
+			return reminded, nil
+		}
+	}
+}
+
+func (s *Service) finishMaintenance(result MaintenanceResult, err error) (MaintenanceResult, error) {
+	if m := s.maintenance.Metrics; m != nil {
+		m.AddDeleted("subscription", result.DeletedSubscriptions)
+		m.AddDeleted("user", result.DeletedUsers)
+		if err != nil {
+			m.IncRun("failed")
+		} else {
+			m.IncRun("succeeded")
+		}
+	}
+	return result, err
+}
+
+func (s *Service) incReminder(status string) {
+	if s.maintenance.Metrics != nil {
+		s.maintenance.Metrics.IncReminder(status)
+	}
+}
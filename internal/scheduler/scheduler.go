@@ -2,46 +2,70 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 	"weatherApi/internal/appctx"
-	"weatherApi/internal/broker"
 	"weatherApi/internal/common/constants"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/repository/subscription"
-	serviceWeather "weatherApi/internal/service/weather"
-
-	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/google/uuid"
 
 	"github.com/go-co-op/gocron/v2"
 )
 
-const maxConcurrentJobs = 5
+const (
+	maxConcurrentJobs = 5
+	// defaultPageSize is number of subscriptions loaded at once by notification jobs
+	defaultPageSize = 500
+	// dailyHour is local hour of daily emails
+	dailyHour = 9
+)
 
 type SubscriptionRepositoryInterface interface {
-	FindAllSubscriptionsByFrequency(ctx context.Context, frequency constants.Frequency) ([]subscription.SubscriptionModel, error)
+	FindDuePage(
+		ctx context.Context,
+		frequency constants.Frequency,
+		order subscription.DueOrder,
+		after subscription.DueCursor,
+		limit int,
+	) ([]subscription.SubscriptionModel, error)
+}
+
+// RetentionPurgerInterface erases personal data kept longer than needed, returns number of erased users
+type RetentionPurgerInterface interface {
+	PurgeUnconfirmed(ctx context.Context) (int, error)
+}
+
+// notificationGroup groups subscriptions which can share the same weather reading
+type notificationGroup struct {
+	city    string
+	options dto.WeatherOptions
 }
 
 type Service struct {
 	log              *logger.Logger
 	subscriptionRepo SubscriptionRepositoryInterface
-	publisher        broker.EventPublisher
+	dispatcher       *Dispatcher
+	retention        RetentionPurgerInterface
+	maintenance      *Maintenance
+	digest           bool
+	notifications    bool
+	leader           *LeaderElector
+	runs             JobRunRepositoryInterface
+	catchUpWindow    time.Duration
+	runTimeout       time.Duration
+	pageSize         int
 	scheduler        gocron.Scheduler
-	weatherService   *serviceWeather.Service
 	ctx              context.Context
 }
 
 func NewService(
 	log *logger.Logger,
 	subscriptionRepo SubscriptionRepositoryInterface,
-	publisher broker.EventPublisher,
-	weatherService *serviceWeather.Service,
+	dispatcher *Dispatcher,
 	ctx context.Context,
 ) (*Service, error) {
 	sched, err := gocron.NewScheduler()
@@ -52,120 +76,281 @@ func NewService(
 	return &Service{
 		log:              log,
 		subscriptionRepo: subscriptionRepo,
-		publisher:        publisher,
+		dispatcher:       dispatcher,
+		notifications:    true,
+		pageSize:         defaultPageSize,
 		scheduler:        sched,
-		weatherService:   weatherService,
 		ctx:              ctx,
 	}, nil
 }
 
+// WithRetention enables daily purge of users who never confirmed subscription
+func (s *Service) WithRetention(purger RetentionPurgerInterface) *Service {
+	s.retention = purger
+	return s
+}
+
+// WithDigest makes scheduled jobs send one email per user with a section per subscribed city
+// instead of one email per city. Each run only sees subscriptions of its frequency, so cities
+// subscribed hourly and daily are not combined
+func (s *Service) WithDigest(enabled bool) *Service {
+	s.digest = enabled
+	return s
+}
+
+// WithPageSize sets how many subscriptions notification jobs load at once, non positive size keeps default
+func (s *Service) WithPageSize(size int) *Service {
+	if size > 0 {
+		s.pageSize = size
+	}
+	return s
+}
+
+// WithNotifications enables hourly and daily weather emails, they are on by default and can be
+// turned off when another service sends them
+func (s *Service) WithNotifications(enabled bool) *Service {
+	s.notifications = enabled
+	return s
+}
+
+// WithLeaderElection makes jobs run only on the instance holding scheduler lease,
+// so replicas of api service don't send every email several times
+func (s *Service) WithLeaderElection(elector *LeaderElector) *Service {
+	s.leader = elector
+	return s
+}
+
+// leaderOnly skips job on instances which are not scheduler leader
+func (s *Service) leaderOnly(job string, fn func()) func() {
+	return func() {
+		if s.leader != nil && !s.leader.IsLeader() {
+			s.log.Base().Debug().Msgf("%s job skipped, instance is not scheduler leader", job)
+			return
+		}
+		fn()
+	}
+}
+
 func (s *Service) Start() error {
+	if s.notifications {
+		if err := s.startNotificationJobs(); err != nil {
+			return err
+		}
+		s.startCatchUp()
+	}
+
+	if s.leader != nil {
+		s.leader.Start(s.ctx)
+	}
+
+	if s.retention != nil {
+		_, err := s.scheduler.NewJob(
+			gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(3, 0, 0))),
+			gocron.NewTask(s.leaderOnly("Retention", func() {
+				ctx := appctx.SetTraceID(context.Background(), uuid.NewString())
+				log := s.log.FromContext(ctx)
+				purged, err := s.retention.PurgeUnconfirmed(ctx)
+				if err != nil {
+					log.Error().Err(err).Msgf("Retention job failed after purging %d users", purged)
+					return
+				}
+				log.Info().Msgf("Retention job purged %d unconfirmed users", purged)
+			})),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.maintenance != nil && s.maintenance.Interval <= 0 {
+		s.log.Base().Warn().Msg("Maintenance job is disabled, interval is not positive")
+	}
+	if s.maintenance != nil && s.maintenance.Interval > 0 {
+		_, err := s.scheduler.NewJob(
+			gocron.DurationJob(s.maintenance.Interval),
+			gocron.NewTask(s.leaderOnly("Maintenance", func() {
+				ctx := appctx.SetTraceID(context.Background(), uuid.NewString())
+				log := s.log.FromContext(ctx)
+				result, err := s.RunMaintenance(ctx)
+				if err != nil {
+					log.Error().Err(err).Msgf("Maintenance job failed: %+v", result)
+					return
+				}
+				log.Info().Msgf("Maintenance job finished: %+v", result)
+			})),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.scheduler.Start()
+	return nil
+}
+
+// startNotificationJobs schedules weather emails at the start of every hour and daily at dailyHour,
+// hourly job yields to daily one at its hour
+func (s *Service) startNotificationJobs() error {
 	_, err := s.scheduler.NewJob(
-		gocron.DurationJob(time.Hour),
-		gocron.NewTask(func() {
+		gocron.CronJob("0 * * * *", false),
+		gocron.NewTask(s.leaderOnly("Hourly", func() {
 			ctx := appctx.SetTraceID(context.Background(), uuid.NewString())
 			log := s.log.FromContext(ctx)
 			log.Info().Msg("Hourly job started")
-			if err := s.SendNotification(ctx, constants.FrequencyHourly); err != nil {
+			if err := s.runNotification(ctx, constants.FrequencyHourly, time.Now(), false); err != nil {
 				log.Error().Err(err).Msg("Error processing hourly notification")
 			}
-		}),
+		})),
 	)
 	if err != nil {
 		return err
 	}
 
 	_, err = s.scheduler.NewJob(
-		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(9, 0, 0))),
-		gocron.NewTask(func() {
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(dailyHour, 0, 0))),
+		gocron.NewTask(s.leaderOnly("Daily", func() {
 			ctx := appctx.SetTraceID(context.Background(), uuid.NewString())
 			log := s.log.FromContext(ctx)
 			log.Info().Msg("Daily job started")
-			if err := s.SendNotification(ctx, constants.FrequencyDaily); err != nil {
+			if err := s.runNotification(ctx, constants.FrequencyDaily, time.Now(), false); err != nil {
 				log.Error().Err(err).Msg("Error processing daily notification")
 			}
-		}),
+		})),
 	)
-	if err != nil {
-		return err
-	}
-
-	s.scheduler.Start()
-	return nil
+	return err
 }
 
 func (s *Service) Stop() error {
 	s.log.Base().Info().Msg("Shutting down scheduler...")
-	return s.scheduler.Shutdown()
+	err := s.scheduler.Shutdown()
+	if s.leader != nil {
+		// service context is already cancelled on shutdown, lease is released with a fresh one
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if releaseErr := s.leader.Stop(ctx); releaseErr != nil {
+			s.log.Base().Error().Err(releaseErr).Msg("Failed to release scheduler lease")
+		}
+	}
+	return err
 }
 
-func (s *Service) SendNotification(ctx context.Context, frequency constants.Frequency) error {
-	now := time.Now()
+// SendNotification queues weather emails of all due subscriptions with frequency. Subscriptions are loaded
+// page by page in order keeping each batch together, so only the batch being collected and batches in flight
+// are held in memory. Error is returned when subscriptions could not be loaded or some of them were not queued
+func (s *Service) SendNotification(ctx context.Context, frequency constants.Frequency) (RunStats, error) {
 	log := s.log.FromContext(ctx)
-	if frequency == constants.FrequencyHourly && now.Hour() == 9 {
-		log.Info().Msg("Hourly job skipped, cause of dayly job!")
-		return nil
-	}
 	log.Info().Msgf("Sending notifications for %s frequency...", frequency)
 
-	subs, err := s.subscriptionRepo.FindAllSubscriptionsByFrequency(ctx, frequency)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to get subscriptions")
-	}
-
-	cityToEmails := make(map[string][]subscription.SubscriptionModel)
-	for _, sub := range subs {
-		city := strings.ToLower(strings.TrimSpace(sub.City))
-		cityToEmails[city] = append(cityToEmails[city], sub)
+	order := subscription.DueByCity
+	sameBatch := func(a, b subscription.SubscriptionModel) bool { return groupOf(a).city == groupOf(b).city }
+	split := groupByCity
+	dispatch := s.dispatcher.Dispatch
+	if s.digest {
+		order = subscription.DueByUser
+		sameBatch = func(a, b subscription.SubscriptionModel) bool { return a.UserID == b.UserID }
+		split = groupByUser
+		dispatch = s.dispatcher.DispatchDigest
 	}
 
+	var stats RunStats
 	var wg sync.WaitGroup
+	var mu sync.Mutex
 	semaphore := make(chan struct{}, maxConcurrentJobs)
+	send := func(subs []subscription.SubscriptionModel) {
+		for _, batch := range split(subs) {
+			wg.Add(1)
 
-	for city, subs := range cityToEmails {
-		wg.Add(1)
+			semaphore <- struct{}{}
 
-		semaphore <- struct{}{}
-
-		go func(ctx context.Context, city string, subs []subscription.SubscriptionModel) {
-			defer wg.Done()
-			defer func() { <-semaphore }()
-
-			weather, err := s.weatherService.GetWeather(ctx, city)
-			if err != nil {
-				s.HandleError(fmt.Sprintf("failed to fetch weather for city=%s", city), err)
-				return
-			}
+			go func(ctx context.Context, subs []subscription.SubscriptionModel) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				err := dispatch(ctx, subs)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					stats.Failures += len(subs)
+				} else {
+					stats.Recipients += len(subs)
+				}
+			}(ctx, batch)
+		}
+	}
 
-			users := make([]dto.UserData, len(subs))
-			for i, sub := range subs {
-				users[i] = dto.UserData{Email: sub.User.Email, Token: sub.ConfirmToken}
+	total := 0
+	cities := make(map[string]struct{})
+	var pending []subscription.SubscriptionModel
+	after := subscription.DueCursor{}
+	for {
+		page, err := s.subscriptionRepo.FindDuePage(ctx, frequency, order, after, s.pageSize)
+		if err != nil {
+			// subscriptions already loaded are still sent, the last batch may lack ones of the page which
+			// failed to load. Subscriptions not loaded miss this slot, as failed runs are not retried
+			send(pending)
+			wg.Wait()
+			stats.Cities = len(cities)
+			return stats, fmt.Errorf("failed to load subscriptions after %d: %w", total, err)
+		}
+		for _, sub := range page {
+			if len(pending) > 0 && !sameBatch(pending[0], sub) {
+				send(pending)
+				pending = nil
 			}
+			pending = append(pending, sub)
+			cities[groupOf(sub).city] = struct{}{}
+			total++
+		}
+		if len(page) < s.pageSize {
+			break
+		}
+		after = subscription.CursorAfter(page[len(page)-1])
+	}
+	send(pending)
 
-			task := dto.WeatherSubData{
-				Users:   users,
-				Weather: *weather,
-			}
+	wg.Wait()
+	stats.Cities = len(cities)
+	if stats.Failures > 0 {
+		return stats, fmt.Errorf("%d of %d subscriptions were not queued", stats.Failures, total)
+	}
+	return stats, nil
+}
 
-			payload, marshalErr := json.Marshal(task)
-			if marshalErr != nil {
-				s.HandleError(fmt.Sprintf("error marshaling event for %s", city), marshalErr)
-				return
-			}
-			traceID := appctx.GetTraceID(ctx)
-			if err := s.publisher.Publish(broker.SendSubscriptionWeatherData, payload, broker.WithHeaders(amqp.Table{constants.HdrTraceID: traceID})); err != nil {
-				s.HandleError(fmt.Sprintf("failed to publish notification for %s", city), err)
-			}
-		}(ctx, city, subs)
+// groupByCity batches subscriptions sharing weather reading, each batch is sent as one task
+func groupByCity(subs []subscription.SubscriptionModel) [][]subscription.SubscriptionModel {
+	groups := make(map[notificationGroup][]subscription.SubscriptionModel)
+	var order []notificationGroup
+	for _, sub := range subs {
+		group := groupOf(sub)
+		if _, ok := groups[group]; !ok {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], sub)
 	}
+	batches := make([][]subscription.SubscriptionModel, len(order))
+	for i, group := range order {
+		batches[i] = groups[group]
+	}
+	return batches
+}
 
-	wg.Wait()
-	return nil
+// groupByUser batches all due subscriptions of each user into a digest
+func groupByUser(subs []subscription.SubscriptionModel) [][]subscription.SubscriptionModel {
+	groups := make(map[uint][]subscription.SubscriptionModel)
+	var order []uint
+	for _, sub := range subs {
+		if _, ok := groups[sub.UserID]; !ok {
+			order = append(order, sub.UserID)
+		}
+		groups[sub.UserID] = append(groups[sub.UserID], sub)
+	}
+	batches := make([][]subscription.SubscriptionModel, len(order))
+	for i, userID := range order {
+		batches[i] = groups[userID]
+	}
+	return batches
 }
 
 func (s *Service) HandleError(msg string, err error) {
-	s.log.Base().Error().Err(err).Msg(msg)
-	if err := s.publisher.Publish(broker.SendSubscriptionWeatherData.DLQ(), []byte(msg)); err != nil {
-		s.log.Base().Error().Err(err).Msg("error sending event to DLQ")
-	}
+	_ = s.dispatcher.HandleError(msg, err)
 }
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"weatherApi/internal/config"
+	"weatherApi/internal/provider"
+
+	serviceSubscription "weatherApi/internal/service/subscription"
+)
+
+// newCaptchaVerifier returns nil when captcha is disabled, interface is returned untyped to keep nil check valid
+func newCaptchaVerifier(cfg *config.ApiServiceConfig) serviceSubscription.CaptchaVerifierInterface {
+	switch cfg.CaptchaMode {
+	case config.CaptchaModeSiteVerify:
+		return provider.NewSiteVerifyCaptchaVerifier(cfg.CaptchaVerifyURL, cfg.CaptchaSecret)
+	case config.CaptchaModeLocal:
+		return provider.NewLocalCaptchaVerifier(cfg.CaptchaSecret)
+	default:
+		return nil
+	}
+}
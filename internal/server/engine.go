@@ -0,0 +1,21 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewEngine creates gin engine which reads client IP from X-Forwarded-For and X-Real-IP only when request comes
+// from one of trustedProxies, comma separated IPs or CIDRs. With none of them remote address is the client IP,
+// so per IP limits can't be dodged by forging forwarding headers
+func NewEngine(trustedProxies string) (*gin.Engine, error) {
+	r := gin.New()
+	var proxies []string
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return r, r.SetTrustedProxies(proxies)
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"weatherApi/internal/metrics"
 	"weatherApi/internal/middleware"
+	"weatherApi/internal/provider"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -18,7 +19,10 @@ import (
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
-	r := gin.New()
+	r, err := NewEngine(s.config.TrustedProxies)
+	if err != nil {
+		s.log.Base().Fatal().Err(err).Msg("Invalid TRUSTED_PROXIES")
+	}
 	httpMetrics := metrics.NewHTTPMetrics()
 	httpMetrics.Register(prometheus.DefaultRegisterer)
 	r.Use(gin.Recovery())
@@ -28,22 +32,74 @@ func (s *Server) RegisterRoutes() http.Handler {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", middleware.APIKeyHeader, routes.AdminActorHeader},
+		ExposeHeaders:    []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 	}))
 
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	subscriptionHandler := routes.NewSubscriptionHandler(s.log, s.SubscriptionService)
+	// List-Unsubscribe URL of weather emails, GET falls through to frontend landing page
+	r.POST("/unsubscribe/:token", subscriptionHandler.OneClickUnsubscribe)
+
 	api := r.Group("/api/v1")
+	// routes calling providers or sending emails, confirm, unsubscribe and privacy links from emails stay open
+	limited := api.Group("",
+		middleware.APIKeyMiddleware(s.APIKeyService, s.config.APIKeysRequired, s.RateLimitService, s.config.RateLimitIPPerMinute),
+		middleware.RateLimitMiddleware(s.RateLimitService, s.config.RateLimitIPPerMinute),
+	)
 	{
-		weatherHandler := routes.NewWeatherHandler(s.log, s.WeatherService)
+		weatherHandler := routes.NewWeatherHandler(s.log, s.WeatherService, s.geoLocatorOrNil())
 		api.GET("/health", s.healthHandler)
-		api.GET("/weather", weatherHandler.GetWeather)
+		limited.GET("/weather", weatherHandler.GetWeather)
+
+		historyHandler := routes.NewHistoryHandler(s.log, s.HistoryService, s.geoLocatorOrNil())
+		limited.GET("/weather/history", historyHandler.GetHistory)
+
+		airQualityHandler := routes.NewAirQualityHandler(s.log, s.AirQualityService, s.geoLocatorOrNil())
+		limited.GET("/air-quality", airQualityHandler.GetAirQuality)
 
-		subscriptionHandler := routes.NewSubscriptionHandler(s.log, s.SubscriptionService)
-		api.POST("/subscribe", subscriptionHandler.Subscribe)
+		limited.POST("/subscribe", subscriptionHandler.Subscribe)
 		api.GET("/confirm/:token", subscriptionHandler.ConfirmSubscription)
-		api.GET("/unsubscribe/:token", subscriptionHandler.Unsubscribe)
+		api.GET("/unsubscribe/:token", subscriptionHandler.UnsubscribePreview)
+		api.POST("/unsubscribe/:token", subscriptionHandler.Unsubscribe)
+		api.GET("/manage/:token", subscriptionHandler.Settings)
+		limited.POST("/manage/:token/email", subscriptionHandler.RequestEmailChange)
+		api.POST("/manage/:token/pause", subscriptionHandler.Pause)
+		api.POST("/manage/:token/resume", subscriptionHandler.Resume)
+
+		privacyHandler := routes.NewPrivacyHandler(s.log, s.PrivacyService)
+		limited.POST("/privacy/requests", privacyHandler.RequestLink)
+		api.GET("/privacy/export/:token", privacyHandler.Export)
+		api.POST("/privacy/erase/:token", privacyHandler.Erase)
+	}
+
+	admin := api.Group("/admin", middleware.AdminAuthMiddleware(s.config.AdminToken))
+	{
+		adminHandler := routes.NewAdminHandler(s.log, s.QuotaService)
+		admin.GET("/quota", adminHandler.GetQuota)
+
+		apiKeyHandler := routes.NewAPIKeyHandler(s.log, s.APIKeyService)
+		admin.POST("/api-keys", apiKeyHandler.Create)
+		admin.GET("/api-keys", apiKeyHandler.List)
+		admin.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+
+		subscriptionAdminHandler := routes.NewSubscriptionAdminHandler(s.log, s.AdminService)
+		admin.GET("/users", subscriptionAdminHandler.SearchUsers)
+		admin.POST("/subscriptions/:id/confirm", subscriptionAdminHandler.ForceConfirm)
+		admin.DELETE("/subscriptions/:id", subscriptionAdminHandler.ForceUnsubscribe)
+		admin.POST("/subscriptions/:id/resend-confirmation", subscriptionAdminHandler.ResendConfirmation)
+		admin.POST("/subscriptions/:id/send", subscriptionAdminHandler.SendNow)
+		admin.GET("/audit-log", subscriptionAdminHandler.AuditLog)
+		admin.GET("/jobs/runs", subscriptionAdminHandler.JobRuns)
+	}
+
+	webhooks := api.Group("/webhooks", middleware.WebhookSecretMiddleware(s.config.EmailWebhookSecret))
+	{
+		emailWebhookHandler := routes.NewEmailWebhookHandler(s.log, s.SuppressionService)
+		webhooks.POST("/email-events", emailWebhookHandler.EmailEvents)
+		webhooks.POST("/bounce-email", emailWebhookHandler.BounceEmail)
 	}
 
 	webDir := filepath.Join(s.config.RootDir, "web")
@@ -68,3 +124,11 @@ func (s *Server) RegisterRoutes() http.Handler {
 func (s *Server) healthHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, s.HealthCheckService.Health())
 }
+
+// geoLocatorOrNil avoids passing typed nil pointer as interface to handlers
+func (s *Server) geoLocatorOrNil() provider.GeoLocatorInterface {
+	if s.geoLocator == nil {
+		return nil
+	}
+	return s.geoLocator
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+	"weatherApi/internal/logger"
+
+	"weatherApi/internal/service/quota"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	log          *logger.Logger
+	quotaService *quota.Service
+}
+
+func NewAdminHandler(log *logger.Logger, quotaService *quota.Service) *AdminHandler {
+	return &AdminHandler{
+		log:          log,
+		quotaService: quotaService,
+	}
+}
+
+func (h *AdminHandler) GetQuota(c *gin.Context) {
+	usage, err := h.quotaService.Usage(c.Request.Context())
+	if err != nil {
+		h.log.FromContext(c.Request.Context()).Error().Err(err).Msg("Failed to get providers quota")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"providers": usage})
+}
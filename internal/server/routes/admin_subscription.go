@@ -0,0 +1,146 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/service/admin"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminActorHeader optionally names operator in audit log, admin token is shared
+const AdminActorHeader = "X-Admin-User"
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type SubscriptionAdminHandler struct {
+	log     *logger.Logger
+	service *admin.Service
+}
+
+func NewSubscriptionAdminHandler(log *logger.Logger, adminService *admin.Service) *SubscriptionAdminHandler {
+	return &SubscriptionAdminHandler{
+		log:     log,
+		service: adminService,
+	}
+}
+
+func (h *SubscriptionAdminHandler) SearchUsers(c *gin.Context) {
+	users, err := h.service.SearchUsers(c.Request.Context(), adminActor(c), c.Query("email"))
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+func (h *SubscriptionAdminHandler) ForceConfirm(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.service.ForceConfirm(c.Request.Context(), adminActor(c), id)
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *SubscriptionAdminHandler) ForceUnsubscribe(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.ForceUnsubscribe(c.Request.Context(), adminActor(c), id); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SubscriptionAdminHandler) ResendConfirmation(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.ResendConfirmation(c.Request.Context(), adminActor(c), id); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Confirmation email queued"})
+}
+
+func (h *SubscriptionAdminHandler) SendNow(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.SendNow(c.Request.Context(), adminActor(c), id); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Weather email queued"})
+}
+
+func (h *SubscriptionAdminHandler) AuditLog(c *gin.Context) {
+	limit, ok := listLimit(c)
+	if !ok {
+		return
+	}
+	entries, err := h.service.AuditLog(c.Request.Context(), limit)
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+func (h *SubscriptionAdminHandler) JobRuns(c *gin.Context) {
+	limit, ok := listLimit(c)
+	if !ok {
+		return
+	}
+	runs, err := h.service.JobRuns(c.Request.Context(), c.Query("job"), limit)
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// listLimit reads optional limit query of admin lists, responds with bad request when it is invalid
+func listLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultListLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxListLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return 0, false
+	}
+	return limit, true
+}
+
+func adminActor(c *gin.Context) dto.AdminActor {
+	name := c.GetHeader(AdminActorHeader)
+	if name == "" || len(name) > 64 {
+		name = "admin"
+	}
+	return dto.AdminActor{Name: name, RemoteIP: c.ClientIP()}
+}
+
+func subscriptionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"net/http"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+
+	"weatherApi/internal/service/airquality"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AirQualityHandler struct {
+	log        *logger.Logger
+	service    *airquality.Service
+	geoLocator provider.GeoLocatorInterface
+}
+
+func NewAirQualityHandler(log *logger.Logger, airQualityService *airquality.Service, geoLocator provider.GeoLocatorInterface) *AirQualityHandler {
+	return &AirQualityHandler{
+		log:        log,
+		service:    airQualityService,
+		geoLocator: geoLocator,
+	}
+}
+
+func (h *AirQualityHandler) GetAirQuality(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+
+	location, err := resolveLocation(c, h.geoLocator)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve location")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	log.Info().Msgf("Handling get air quality for %s", location)
+
+	response, err := h.service.GetAirQuality(c.Request.Context(), *location)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get air quality for %s", location)
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/service/apikey"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	log     *logger.Logger
+	service *apikey.Service
+}
+
+func NewAPIKeyHandler(log *logger.Logger, apiKeyService *apikey.Service) *APIKeyHandler {
+	return &APIKeyHandler{
+		log:     log,
+		service: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	created, err := h.service.Issue(c.Request.Context(), &req)
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, parseErr := strconv.ParseUint(c.Param("id"), 10, 64)
+	if parseErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := h.service.Revoke(c.Request.Context(), uint(id)); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
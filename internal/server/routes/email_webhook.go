@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"io"
+	"net/http"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/service/suppression"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodyBytes bounds webhook payloads, bounce emails may quote the original message
+const maxWebhookBodyBytes = 1 << 20
+
+type EmailWebhookHandler struct {
+	log     *logger.Logger
+	service *suppression.Service
+}
+
+func NewEmailWebhookHandler(log *logger.Logger, suppressionService *suppression.Service) *EmailWebhookHandler {
+	return &EmailWebhookHandler{
+		log:     log,
+		service: suppressionService,
+	}
+}
+
+// EmailEvents accepts bounce and complaint events posted by mail provider as JSON
+func (h *EmailWebhookHandler) EmailEvents(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := h.service.RecordWebhook(c.Request.Context(), body); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// BounceEmail accepts raw RFC 822 delivery status notification forwarded by inbound mail relay
+func (h *EmailWebhookHandler) BounceEmail(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes)
+	if err := h.service.RecordBounceEmail(c.Request.Context(), body); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
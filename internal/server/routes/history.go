@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+
+	"weatherApi/internal/service/history"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultHistoryRange = 24 * time.Hour
+
+type HistoryHandler struct {
+	log        *logger.Logger
+	service    *history.Service
+	geoLocator provider.GeoLocatorInterface
+}
+
+func NewHistoryHandler(log *logger.Logger, historyService *history.Service, geoLocator provider.GeoLocatorInterface) *HistoryHandler {
+	return &HistoryHandler{
+		log:        log,
+		service:    historyService,
+		geoLocator: geoLocator,
+	}
+}
+
+// GetHistory returns aggregated observations, by default for the last 24 hours with hourly granularity
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+
+	location, err := resolveLocation(c, h.geoLocator)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve location")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+
+	query := dto.HistoryQuery{
+		Location:    *location,
+		Granularity: constants.GranularityHour,
+		To:          time.Now().UTC(),
+	}
+	if granularity := c.Query("granularity"); granularity != "" {
+		query.Granularity = constants.Granularity(granularity)
+		if !query.Granularity.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be one of hour, day"})
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		parsed, parseErr := parseHistoryTime(to)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": parseErr.Error()})
+			return
+		}
+		query.To = parsed
+	}
+	query.From = query.To.Add(-defaultHistoryRange)
+	if from := c.Query("from"); from != "" {
+		parsed, parseErr := parseHistoryTime(from)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": parseErr.Error()})
+			return
+		}
+		query.From = parsed
+	}
+	log.Info().Msgf("Handling get weather history for %s", location)
+
+	response, err := h.service.GetHistory(c.Request.Context(), query)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get weather history for %s", location)
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseHistoryTime accepts RFC3339 timestamp or a date, which is treated as UTC midnight
+func parseHistoryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339 or YYYY-MM-DD", value)
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/provider"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const autoLocationIP = "ip"
+
+// resolveLocation builds location from query: ?auto=ip, ?lat=&lon= or ?city=
+func resolveLocation(c *gin.Context, geoLocator provider.GeoLocatorInterface) (*dto.Location, *appErrors.AppError) {
+	if auto := c.Query("auto"); auto != "" {
+		if auto != autoLocationIP {
+			return nil, appErrors.New(http.StatusBadRequest, "auto supports only 'ip' value", nil)
+		}
+		if geoLocator == nil {
+			return nil, serviceErrors.ErrGeoIPNotConfigured
+		}
+		return geoLocator.Locate(c.Request.Context(), c.ClientIP())
+	}
+
+	latRaw, lonRaw := c.Query("lat"), c.Query("lon")
+	if latRaw != "" || lonRaw != "" {
+		lat, errLat := strconv.ParseFloat(latRaw, 64)
+		lon, errLon := strconv.ParseFloat(lonRaw, 64)
+		if errLat != nil || errLon != nil {
+			return nil, appErrors.New(http.StatusBadRequest, "lat and lon must be valid numbers", nil)
+		}
+		location := dto.NewCoordinatesLocation(lat, lon)
+		if err := location.Validate(); err != nil {
+			return nil, appErrors.New(http.StatusBadRequest, "lat or lon is out of range", err)
+		}
+		return &location, nil
+	}
+
+	city := c.Query("city")
+	if city == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "city is required", nil)
+	}
+	location := dto.NewCityLocation(city)
+	return &location, nil
+}
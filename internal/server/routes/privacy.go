@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/service/privacy"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	log     *logger.Logger
+	service *privacy.Service
+}
+
+func NewPrivacyHandler(log *logger.Logger, privacyService *privacy.Service) *PrivacyHandler {
+	return &PrivacyHandler{
+		log:     log,
+		service: privacyService,
+	}
+}
+
+func (h *PrivacyHandler) RequestLink(c *gin.Context) {
+	var req dto.PrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := h.service.RequestLink(c.Request.Context(), &req); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusAccepted, "If the email is registered, a verification link has been sent to it.")
+}
+
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	export, err := h.service.Export(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="weather-subscription-data.json"`)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, export)
+}
+
+func (h *PrivacyHandler) Erase(c *gin.Context) {
+	if err := h.service.Erase(c.Request.Context(), c.Param("token")); err != nil {
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, "Your data has been deleted")
+}
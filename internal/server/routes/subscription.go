@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"weatherApi/internal/logger"
 
@@ -32,6 +34,7 @@ func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
+	req.ClientIP = c.ClientIP()
 	if err := h.service.Subscribe(c.Request.Context(), &req); err != nil {
 		log.Error().Err(err).Msgf("Failed to handle subscribe request for %s: %s", req.Email, req.City)
 		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
@@ -54,6 +57,18 @@ func (h *SubscriptionHandler) ConfirmSubscription(c *gin.Context) {
 	c.JSON(http.StatusOK, "Subscription confirmed successfully")
 }
 
+// UnsubscribePreview doesn't change anything, so links prefetched by mail scanners are harmless
+func (h *SubscriptionHandler) UnsubscribePreview(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	settings, err := h.service.UnsubscribePreview(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load subscription for unsubscribe")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
 func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
 	log := h.log.FromContext(c.Request.Context())
 	token := c.Param("token")
@@ -65,3 +80,70 @@ func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, "Unsubscribed successfully")
 }
+
+func (h *SubscriptionHandler) Settings(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	settings, err := h.service.Settings(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load subscription settings")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *SubscriptionHandler) RequestEmailChange(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	var req dto.EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	req.ClientIP = c.ClientIP()
+	if err := h.service.RequestEmailChange(c.Request.Context(), c.Param("token"), &req); err != nil {
+		log.Error().Err(err).Msgf("Failed to handle email change request to %s", req.Email)
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusAccepted, "Confirmation email sent to the new address.")
+}
+
+// Pause accepts empty body for pause until resumed
+func (h *SubscriptionHandler) Pause(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	var req dto.PauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	settings, err := h.service.Pause(c.Request.Context(), c.Param("token"), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to pause subscription")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *SubscriptionHandler) Resume(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	settings, err := h.service.Resume(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resume subscription")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// OneClickUnsubscribe handles RFC 8058 "List-Unsubscribe=One-Click" POST sent by mail clients
+// to the List-Unsubscribe URL, GET of the same URL renders landing page
+func (h *SubscriptionHandler) OneClickUnsubscribe(c *gin.Context) {
+	log := h.log.FromContext(c.Request.Context())
+	if err := h.service.Unsubscribe(c.Request.Context(), c.Param("token")); err != nil {
+		log.Error().Err(err).Msg("Failed to handle one-click unsubscribe")
+		c.String(err.Code, err.Message)
+		return
+	}
+	c.String(http.StatusOK, "Unsubscribed successfully")
+}
@@ -2,7 +2,11 @@ package routes
 
 import (
 	"net/http"
+	"strings"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
 
 	"weatherApi/internal/service/weather"
 
@@ -10,30 +14,42 @@ import (
 )
 
 type WeatherHandler struct {
-	log     *logger.Logger
-	service *weather.Service
+	log        *logger.Logger
+	service    *weather.Service
+	geoLocator provider.GeoLocatorInterface
 }
 
-func NewWeatherHandler(log *logger.Logger, weatherService *weather.Service) *WeatherHandler {
+// NewWeatherHandler creates weather handler, geoLocator is optional and only required for ?auto=ip mode
+func NewWeatherHandler(log *logger.Logger, weatherService *weather.Service, geoLocator provider.GeoLocatorInterface) *WeatherHandler {
 	return &WeatherHandler{
-		log:     log,
-		service: weatherService,
+		log:        log,
+		service:    weatherService,
+		geoLocator: geoLocator,
 	}
 }
 
 func (h *WeatherHandler) GetWeather(c *gin.Context) {
 	log := h.log.FromContext(c.Request.Context())
 
-	city := c.Query("city")
-	log.Info().Msgf("Handling get weather for %s", city)
-	if city == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "city is required"})
+	location, err := resolveLocation(c, h.geoLocator)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve location")
+		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
+		return
+	}
+	options := dto.WeatherOptions{
+		Units: constants.Units(c.Query("units")),
+		Lang:  strings.ToLower(c.Query("lang")),
+	}.Normalize()
+	if !options.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "units must be one of metric, imperial, standard and lang must be a language code"})
 		return
 	}
+	log.Info().Msgf("Handling get weather for %s", location)
 
-	response, err := h.service.GetWeather(c.Request.Context(), city)
+	response, err := h.service.GetWeather(c.Request.Context(), *location, options)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed to get weather for %s", city)
+		log.Error().Err(err).Msgf("Failed to get weather for %s", location)
 		c.AbortWithStatusJSON(err.Code, gin.H{"error": err.Message})
 		return
 	}
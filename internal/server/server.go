@@ -4,22 +4,38 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"weatherApi/internal/bootstrap"
 	"weatherApi/internal/broker"
 	"weatherApi/internal/config"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/metrics"
 	"weatherApi/internal/provider"
-	"weatherApi/internal/repository/weather"
+	"weatherApi/internal/repository/apikey"
+	"weatherApi/internal/repository/audit"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/jobrun"
+	"weatherApi/internal/repository/observation"
+	"weatherApi/internal/repository/privacy"
+	"weatherApi/internal/repository/ratelimit"
+	"weatherApi/internal/repository/suppression"
+	"weatherApi/internal/scheduler"
 
 	"github.com/prometheus/client_golang/prometheus"
 
-	"github.com/redis/go-redis/v9"
-
 	repoSubscription "weatherApi/internal/repository/subscription"
 	repoUser "weatherApi/internal/repository/user"
+	serviceAdmin "weatherApi/internal/service/admin"
+	serviceAirQuality "weatherApi/internal/service/airquality"
+	serviceAPIKey "weatherApi/internal/service/apikey"
 	serviceHealthcheck "weatherApi/internal/service/healthcheck"
+	serviceHistory "weatherApi/internal/service/history"
+	servicePrivacy "weatherApi/internal/service/privacy"
+	serviceQuota "weatherApi/internal/service/quota"
+	serviceRateLimit "weatherApi/internal/service/ratelimit"
 	serviceSubscription "weatherApi/internal/service/subscription"
+	serviceSuppression "weatherApi/internal/service/suppression"
 	serviceWeather "weatherApi/internal/service/weather"
 
 	"gorm.io/driver/postgres"
@@ -31,8 +47,21 @@ type Server struct {
 	log                 *logger.Logger
 	config              *config.ApiServiceConfig
 	WeatherService      *serviceWeather.Service
+	AirQualityService   *serviceAirQuality.Service
+	HistoryService      *serviceHistory.Service
+	QuotaService        *serviceQuota.Service
+	APIKeyService       *serviceAPIKey.Service
+	RateLimitService    *serviceRateLimit.Service
 	SubscriptionService *serviceSubscription.SubscriptionService
+	AdminService        *serviceAdmin.Service
+	PrivacyService      *servicePrivacy.Service
+	SuppressionService  *serviceSuppression.Service
+	Dispatcher          *scheduler.Dispatcher
+	Maintenance         scheduler.Maintenance
+	LeaderElector       *scheduler.LeaderElector
+	JobRuns             *jobrun.JobRunRepository
 	HealthCheckService  serviceHealthcheck.HealthCheckService
+	geoLocator          *provider.MaxMindGeoLocator
 	httpServer          *http.Server
 }
 
@@ -50,46 +79,118 @@ func NewServer(log *logger.Logger, cfg *config.ApiServiceConfig, broker broker.E
 		log.Base().Fatal().Err(err).Msg("Failed to get sql.DB")
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisURL,
-		Password: cfg.RedisPassword,
-		DB:       0,
-	})
+	rdb := bootstrap.NewRedisClient(&cfg.WeatherConfig)
 
 	userRepo := repoUser.NewUserRepository(gormDB)
 	subscriptionRepo := repoSubscription.NewSubscriptionRepository(gormDB)
-	cacheMetrics := metrics.NewCacheMetrics()
-	cacheMetrics.Register(prometheus.DefaultRegisterer)
-	cacheRepo := weather.NewWeatherRepository(&weather.RepositoryOptions{
-		Client:       rdb,
-		CacheTTL:     cfg.CacheTTL,
-		LockTTL:      cfg.LockTTL,
-		LockRetryDur: cfg.LockRetryDur,
-		LockMaxWait:  cfg.LockMaxWait,
-		Metrics:      cacheMetrics,
-	})
-
-	weatherService := serviceWeather.NewWeatherService(
-		log,
-		cacheRepo,
-		provider.NewOpenWeatherApiProvider(log, cfg.OpenWeatherAPIkey, cfg.OpenWeatherAPIEndpoint),
-		provider.NewWeatherApiProvider(log, cfg.WeatherApiAPIkey, cfg.WeatherApiAPIEndpoint),
-	)
+	observationRepo := observation.NewObservationRepository(gormDB)
+	weatherStack := bootstrap.NewWeatherStack(log, &cfg.WeatherConfig, rdb, observationRepo)
+	weatherService := weatherStack.Weather
+	airQualityService := weatherStack.AirQuality
+	quotaService := weatherStack.Quota
+	historyService := serviceHistory.NewHistoryService(log, observationRepo)
+	signer := bootstrap.NewTokenSigner(log, &cfg.LinkTokenConfig)
 	subscriptionService := serviceSubscription.NewSubscriptionService(
 		log,
 		subscriptionRepo,
 		userRepo,
 		broker,
 		cfg.TokenLifetimeMinutes,
+		signer,
 	)
 	healthcheckService := serviceHealthcheck.New(log, sqlDB)
+	apiKeyService := serviceAPIKey.NewAPIKeyService(log, apikey.NewAPIKeyRepository(gormDB), cfg.RateLimitKeyPerMinute)
+	rateLimitService := serviceRateLimit.NewRateLimitService(log, ratelimit.NewRateLimitRepository(rdb))
+	subscriptionService.WithAbuseProtection(serviceSubscription.AbuseProtection{
+		Limiter:           rateLimitService,
+		Captcha:           newCaptchaVerifier(cfg),
+		DisposableDomains: strings.Split(cfg.DisposableEmailDomains, ","),
+		EmailSendsPerHour: cfg.SubscribeEmailSendsPerHour,
+		IPSendsPerHour:    cfg.SubscribeIPSendsPerHour,
+		ResendCooldown:    cfg.ConfirmationResendCooldown,
+	})
+
+	dispatcher := scheduler.NewDispatcher(log, subscriptionRepo, broker, weatherService, airQualityService, signer)
+	jobRuns := jobrun.NewJobRunRepository(gormDB)
+	adminService := serviceAdmin.NewAdminService(
+		log,
+		userRepo,
+		subscriptionRepo,
+		audit.NewAuditLogRepository(gormDB),
+		subscriptionService,
+		dispatcher,
+	).WithJobRuns(jobRuns)
+	privacyService := servicePrivacy.NewPrivacyService(
+		log,
+		userRepo,
+		subscriptionRepo,
+		privacy.NewErasureRepository(gormDB),
+		delivery.NewDeliveryRepository(gormDB),
+		privacy.NewTokenRepository(rdb),
+		broker,
+		servicePrivacy.Options{
+			TokenTTL:             cfg.PrivacyTokenTTL,
+			RequestsPerHour:      cfg.PrivacyRequestsPerHour,
+			UnconfirmedRetention: time.Duration(cfg.UnconfirmedUserRetentionDays) * 24 * time.Hour,
+			Limiter:              rateLimitService,
+		},
+	)
+	suppressionService := serviceSuppression.NewSuppressionService(
+		log,
+		suppression.NewSuppressionRepository(gormDB),
+		subscriptionRepo,
+		cfg.HardBouncePauseThreshold,
+	)
+
+	maintenanceMetrics := metrics.NewMaintenanceMetrics()
+	maintenanceMetrics.Register(prometheus.DefaultRegisterer)
+	maintenance := scheduler.Maintenance{
+		Subscriptions:  subscriptionRepo,
+		Users:          privacy.NewErasureRepository(gormDB),
+		Reminders:      subscriptionService,
+		Metrics:        maintenanceMetrics,
+		Interval:       cfg.MaintenanceInterval,
+		ExpiredGrace:   cfg.ExpiredSubscriptionGrace,
+		ReminderBefore: cfg.ConfirmationReminderBefore,
+		BatchSize:      cfg.MaintenanceBatchSize,
+	}
+
+	var leaderElector *scheduler.LeaderElector
+	if cfg.SchedulerLeaderElection {
+		leaseName := bootstrap.MaintenanceLeaseName
+		if cfg.SchedulerEmbedded {
+			leaseName = bootstrap.NotificationsLeaseName
+		}
+		leaderElector = bootstrap.NewLeaderElector(log, &cfg.SchedulerConfig, rdb, leaseName)
+	}
+
+	var geoLocator *provider.MaxMindGeoLocator
+	if cfg.GeoIPDatabasePath != "" {
+		geoLocator, err = provider.NewMaxMindGeoLocator(log, cfg.GeoIPDatabasePath)
+		if err != nil {
+			log.Base().Error().Err(err).Msg("GeoIP database is not loaded, ip based location is disabled")
+		}
+	}
 
 	server := &Server{
 		log:                 log,
 		config:              cfg,
 		WeatherService:      weatherService,
+		AirQualityService:   airQualityService,
+		HistoryService:      historyService,
+		QuotaService:        quotaService,
+		APIKeyService:       apiKeyService,
+		RateLimitService:    rateLimitService,
 		SubscriptionService: subscriptionService,
+		AdminService:        adminService,
+		PrivacyService:      privacyService,
+		SuppressionService:  suppressionService,
+		Dispatcher:          dispatcher,
+		Maintenance:         maintenance,
+		LeaderElector:       leaderElector,
+		JobRuns:             jobRuns,
 		HealthCheckService:  healthcheckService,
+		geoLocator:          geoLocator,
 	}
 
 	server.httpServer = &http.Server{
@@ -108,5 +209,10 @@ func (s *Server) ListenAndServe() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.geoLocator != nil {
+		if err := s.geoLocator.Close(); err != nil {
+			s.log.Base().Error().Err(err).Msg("Failed to close GeoIP database")
+		}
+	}
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,337 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/audit"
+	"weatherApi/internal/repository/base"
+	"weatherApi/internal/repository/jobrun"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/admin/errors"
+)
+
+const (
+	maxSearchResults = 50
+	minSearchLength  = 3
+)
+
+// Audited admin actions
+const (
+	ActionSearchUsers        = "search_users"
+	ActionForceConfirm       = "force_confirm"
+	ActionForceUnsubscribe   = "force_unsubscribe"
+	ActionResendConfirmation = "resend_confirmation"
+	ActionSendNow            = "send_now"
+)
+
+type UserRepositoryInterface interface {
+	SearchByEmail(ctx context.Context, query string, limit int) ([]user.UserModel, error)
+}
+
+type SubscriptionRepositoryInterface interface {
+	FindAllByUserIDs(ctx context.Context, userIDs []uint) ([]subscription.SubscriptionModel, error)
+	FindOneWithUser(ctx context.Context, id uint) (*subscription.SubscriptionModel, error)
+	Update(ctx context.Context, entity *subscription.SubscriptionModel) error
+	Delete(ctx context.Context, entity *subscription.SubscriptionModel) error
+}
+
+type ConfirmationSenderInterface interface {
+	ResendConfirmation(ctx context.Context, sub *subscription.SubscriptionModel) *appErrors.AppError
+}
+
+type DispatcherInterface interface {
+	Dispatch(ctx context.Context, subs []subscription.SubscriptionModel) error
+}
+
+// Service lets operators inspect and fix subscriptions, every call is written to audit log
+// once it is performed together with its outcome
+type Service struct {
+	log              *logger.Logger
+	userRepo         UserRepositoryInterface
+	subscriptionRepo SubscriptionRepositoryInterface
+	auditRepo        audit.AuditLogRepositoryInterface
+	confirmations    ConfirmationSenderInterface
+	dispatcher       DispatcherInterface
+	jobRuns          jobrun.JobRunRepositoryInterface
+	now              func() time.Time
+}
+
+func NewAdminService(
+	log *logger.Logger,
+	userRepo UserRepositoryInterface,
+	subscriptionRepo SubscriptionRepositoryInterface,
+	auditRepo audit.AuditLogRepositoryInterface,
+	confirmations ConfirmationSenderInterface,
+	dispatcher DispatcherInterface,
+) *Service {
+	return &Service{
+		log:              log,
+		userRepo:         userRepo,
+		subscriptionRepo: subscriptionRepo,
+		auditRepo:        auditRepo,
+		confirmations:    confirmations,
+		dispatcher:       dispatcher,
+		now:              time.Now,
+	}
+}
+
+// WithJobRuns exposes history of scheduled notification runs
+func (s *Service) WithJobRuns(jobRuns jobrun.JobRunRepositoryInterface) *Service {
+	s.jobRuns = jobRuns
+	return s
+}
+
+// SearchUsers returns users whose email contains query together with all their subscriptions
+func (s *Service) SearchUsers(ctx context.Context, actor dto.AdminActor, query string) ([]dto.AdminUser, *appErrors.AppError) {
+	query = strings.TrimSpace(query)
+	if len(query) < minSearchLength {
+		return nil, serviceErrors.ErrInvalidInput
+	}
+	result, appErr := s.searchUsers(ctx, query)
+	s.audit(ctx, actor, ActionSearchUsers, nil, fmt.Sprintf("query=%q", redactQuery(query)), appErr)
+	return result, appErr
+}
+
+func (s *Service) searchUsers(ctx context.Context, query string) ([]dto.AdminUser, *appErrors.AppError) {
+	log := s.log.FromContext(ctx)
+	users, err := s.userRepo.SearchByEmail(ctx, query, maxSearchResults)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to search users")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	result := make([]dto.AdminUser, len(users))
+	if len(users) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, len(users))
+	byUser := make(map[uint]*dto.AdminUser, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+		result[i] = dto.AdminUser{ID: u.ID, Email: u.Email, CreatedAt: u.CreatedAt, Subscriptions: []dto.AdminSubscription{}}
+		byUser[u.ID] = &result[i]
+	}
+	subs, err := s.subscriptionRepo.FindAllByUserIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find users subscriptions")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	for i := range subs {
+		if owner, ok := byUser[subs[i].UserID]; ok {
+			owner.Subscriptions = append(owner.Subscriptions, s.toDTO(&subs[i]))
+		}
+	}
+	return result, nil
+}
+
+// ForceConfirm confirms subscription without token, e.g. when confirmation email never arrived
+func (s *Service) ForceConfirm(ctx context.Context, actor dto.AdminActor, id uint) (*dto.AdminSubscription, *appErrors.AppError) {
+	sub, appErr := s.performAudited(ctx, actor, ActionForceConfirm, id, func(sub *subscription.SubscriptionModel) *appErrors.AppError {
+		if sub.IsConfirmed {
+			return serviceErrors.ErrAlreadyConfirmed
+		}
+		now := s.now()
+		sub.IsConfirmed = true
+		sub.ConfirmedAt = &now
+		if err := s.subscriptionRepo.Update(ctx, sub); err != nil {
+			s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to confirm subscription %d", id)
+			return serviceErrors.ErrInternalServerError
+		}
+		return nil
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+	result := s.toDTO(sub)
+	return &result, nil
+}
+
+// ForceUnsubscribe removes subscription the same way unsubscribe link does
+func (s *Service) ForceUnsubscribe(ctx context.Context, actor dto.AdminActor, id uint) *appErrors.AppError {
+	_, appErr := s.performAudited(ctx, actor, ActionForceUnsubscribe, id, func(sub *subscription.SubscriptionModel) *appErrors.AppError {
+		if err := s.subscriptionRepo.Delete(ctx, sub); err != nil {
+			s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to unsubscribe subscription %d", id)
+			return serviceErrors.ErrInternalServerError
+		}
+		return nil
+	})
+	return appErr
+}
+
+// ResendConfirmation queues confirmation email again, ignoring subscribe throttling
+func (s *Service) ResendConfirmation(ctx context.Context, actor dto.AdminActor, id uint) *appErrors.AppError {
+	_, appErr := s.performAudited(ctx, actor, ActionResendConfirmation, id, func(sub *subscription.SubscriptionModel) *appErrors.AppError {
+		if sub.IsConfirmed {
+			return serviceErrors.ErrAlreadyConfirmed
+		}
+		return s.confirmations.ResendConfirmation(ctx, sub)
+	})
+	return appErr
+}
+
+// SendNow queues weather email for a single confirmed subscription outside of schedule
+func (s *Service) SendNow(ctx context.Context, actor dto.AdminActor, id uint) *appErrors.AppError {
+	_, appErr := s.performAudited(ctx, actor, ActionSendNow, id, func(sub *subscription.SubscriptionModel) *appErrors.AppError {
+		if !sub.IsConfirmed {
+			return serviceErrors.ErrSubscriptionNotActive
+		}
+		if err := s.dispatcher.Dispatch(ctx, []subscription.SubscriptionModel{*sub}); err != nil {
+			var providerErr *appErrors.AppError
+			if errors.As(err, &providerErr) {
+				return providerErr
+			}
+			return serviceErrors.ErrDeliveryFailed
+		}
+		return nil
+	})
+	return appErr
+}
+
+// AuditLog returns up to limit latest admin actions
+func (s *Service) AuditLog(ctx context.Context, limit int) ([]dto.AuditLogEntry, *appErrors.AppError) {
+	entries, err := s.auditRepo.FindLatest(ctx, limit)
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to read audit log")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	result := make([]dto.AuditLogEntry, len(entries))
+	for i := range entries {
+		result[i] = entries[i].ToDTO()
+	}
+	return result, nil
+}
+
+// JobRuns returns up to limit latest runs of scheduled job, of all jobs when job is empty
+func (s *Service) JobRuns(ctx context.Context, job string, limit int) ([]dto.JobRun, *appErrors.AppError) {
+	switch constants.Frequency(job) {
+	case "", constants.FrequencyHourly, constants.FrequencyDaily:
+	default:
+		return nil, serviceErrors.ErrInvalidInput
+	}
+	if s.jobRuns == nil {
+		return []dto.JobRun{}, nil
+	}
+	runs, err := s.jobRuns.FindLatest(ctx, job, limit)
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to read job runs")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	result := make([]dto.JobRun, len(runs))
+	for i := range runs {
+		result[i] = runs[i].ToDTO()
+	}
+	return result, nil
+}
+
+// performAudited runs action on subscription id and writes it to audit log with its outcome,
+// lookups of missing subscriptions are not audited
+func (s *Service) performAudited(
+	ctx context.Context,
+	actor dto.AdminActor,
+	action string,
+	id uint,
+	perform func(sub *subscription.SubscriptionModel) *appErrors.AppError,
+) (*subscription.SubscriptionModel, *appErrors.AppError) {
+	sub, err := s.subscriptionRepo.FindOneWithUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return nil, serviceErrors.ErrSubscriptionNotFound
+		}
+		s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to find subscription %d", id)
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	// details are taken before the action, as unsubscribe doesn't keep subscription loaded as it was
+	details := fmt.Sprintf("email=%s city=%s", sub.User.Email, sub.City)
+	appErr := perform(sub)
+	s.audit(ctx, actor, action, sub, details, appErr)
+	return sub, appErr
+}
+
+// audit writes performed action, failure to store entry doesn't undo the action and is only logged
+func (s *Service) audit(
+	ctx context.Context,
+	actor dto.AdminActor,
+	action string,
+	sub *subscription.SubscriptionModel,
+	details string,
+	actionErr *appErrors.AppError,
+) {
+	log := s.log.FromContext(ctx)
+	entry := &audit.AuditLogModel{
+		Actor:    actor.Name,
+		RemoteIP: actor.RemoteIP,
+		Action:   action,
+		Details:  details,
+		Outcome:  constants.AuditOutcomeSuccess,
+	}
+	if actionErr != nil {
+		entry.Outcome = constants.AuditOutcomeError
+		entry.Error = actionErr.Message
+	}
+	if sub != nil {
+		entry.UserID = &sub.UserID
+		entry.SubscriptionID = &sub.ID
+	}
+	if err := s.auditRepo.CreateOne(ctx, entry); err != nil {
+		log.Error().Err(err).Msgf("Failed to write audit log for %s by %s from %s: %s %s (%s)",
+			action, actor.Name, actor.RemoteIP, details, entry.Outcome, entry.Error)
+		return
+	}
+	log.Info().Msgf("Admin %s from %s: %s %s (%s)", actor.Name, actor.RemoteIP, action, details, entry.Outcome)
+}
+
+// redactQuery keeps first character and domain of searched email, audit log entry of a search isn't tied
+// to any user, so full query would survive erasure of the person searched for
+func redactQuery(query string) string {
+	local, domain, hasDomain := strings.Cut(query, "@")
+	redacted := "***"
+	if local != "" {
+		redacted = local[:1] + redacted
+	}
+	if hasDomain {
+		redacted += "@" + domain
+	}
+	return redacted
+}
+
+func (s *Service) toDTO(sub *subscription.SubscriptionModel) dto.AdminSubscription {
+	result := dto.AdminSubscription{
+		ID:                sub.ID,
+		City:              sub.City,
+		Frequency:         sub.Frequency,
+		Units:             sub.Units,
+		Lang:              sub.Lang,
+		IncludeAirQuality: sub.IncludeAirQuality,
+		AQIAlertThreshold: sub.AQIAlertThreshold,
+		CreatedAt:         sub.CreatedAt,
+		TokenExpires:      sub.TokenExpires,
+		ConfirmedAt:       sub.ConfirmedAt,
+		LastSentAt:        sub.LastSentAt,
+		PausedAt:          sub.PausedAt,
+		PauseReason:       sub.PauseReason,
+		ResumeAt:          sub.ResumeAt,
+	}
+	switch {
+	case sub.DeletedAt.Valid:
+		result.Status = constants.SubscriptionUnsubscribed
+		result.UnsubscribedAt = &sub.DeletedAt.Time
+	case sub.IsPaused(s.now()):
+		result.Status = constants.SubscriptionPaused
+	case sub.IsConfirmed:
+		result.Status = constants.SubscriptionActive
+	case s.now().After(sub.TokenExpires):
+		result.Status = constants.SubscriptionExpired
+	default:
+		result.Status = constants.SubscriptionPending
+	}
+	return result
+}
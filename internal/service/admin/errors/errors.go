@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"net/http"
+
+	"weatherApi/internal/common/errors"
+)
+
+var (
+	ErrInvalidInput          = errors.New(http.StatusBadRequest, "Invalid input", nil)
+	ErrSubscriptionNotFound  = errors.New(http.StatusNotFound, "Subscription not found", nil)
+	ErrAlreadyConfirmed      = errors.New(http.StatusConflict, "Subscription is already confirmed", nil)
+	ErrSubscriptionNotActive = errors.New(http.StatusConflict, "Subscription is not confirmed", nil)
+	ErrDeliveryFailed        = errors.New(http.StatusBadGateway, "Failed to queue weather email", nil)
+	ErrInternalServerError   = errors.New(http.StatusInternalServerError, "Internal server error", nil)
+)
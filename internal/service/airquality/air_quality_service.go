@@ -0,0 +1,44 @@
+package airquality
+
+import (
+	"context"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/airquality"
+	"weatherApi/internal/service/cached"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+type Service struct {
+	log       *logger.Logger
+	provider  provider.AirQualityProviderInterface
+	cacheRepo airquality.CacheRepoInterface
+}
+
+func NewAirQualityService(log *logger.Logger, cacheRepo airquality.CacheRepoInterface, providers ...provider.AirQualityProviderInterface) *Service {
+	if len(providers) == 0 {
+		panic("At least one provider required!")
+	}
+	for i := 0; i < len(providers)-1; i++ {
+		providers[i].SetNext(providers[i+1])
+	}
+	return &Service{log: log, provider: providers[0], cacheRepo: cacheRepo}
+}
+
+func (service *Service) GetAirQuality(
+	ctx context.Context,
+	location dto.Location,
+) (*dto.AirQualityResponse, *appErrors.AppError) {
+	log := service.log.FromContext(ctx)
+
+	if err := location.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid location")
+		return nil, serviceErrors.ErrInvalidRequest
+	}
+	return cached.Fetch(ctx, log, service.cacheRepo, location.Key(), func(ctx context.Context) (*dto.AirQualityResponse, *appErrors.AppError) {
+		return service.provider.GetAirQuality(ctx, location)
+	})
+}
@@ -0,0 +1,124 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/apikey"
+	"weatherApi/internal/repository/base"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/apikey/errors"
+)
+
+const (
+	keyPrefix      = "wk_"
+	keySecretBytes = 24
+	// displayPrefixLen is part of the key kept in plain text, so admins can tell keys apart
+	displayPrefixLen = 10
+)
+
+type Service struct {
+	log              *logger.Logger
+	repo             apikey.APIKeyRepositoryInterface
+	defaultRateLimit int
+	now              func() time.Time
+}
+
+// NewAPIKeyService creates service issuing keys with defaultRateLimit requests per minute unless set explicitly
+func NewAPIKeyService(log *logger.Logger, repo apikey.APIKeyRepositoryInterface, defaultRateLimit int) *Service {
+	return &Service{log: log, repo: repo, defaultRateLimit: defaultRateLimit, now: time.Now}
+}
+
+// Issue creates a new key, plain key is returned only here
+func (s *Service) Issue(ctx context.Context, request *dto.CreateAPIKeyRequest) (*dto.CreatedAPIKey, *appErrors.AppError) {
+	log := s.log.FromContext(ctx)
+
+	secret := make([]byte, keySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		log.Error().Err(err).Msg("Failed to generate API key")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	key := keyPrefix + hex.EncodeToString(secret)
+
+	rateLimit := request.RateLimitPerMinute
+	if rateLimit == 0 {
+		rateLimit = s.defaultRateLimit
+	}
+	model := &apikey.APIKeyModel{
+		Name:               request.Name,
+		Prefix:             key[:displayPrefixLen],
+		KeyHash:            hashKey(key),
+		RateLimitPerMinute: rateLimit,
+	}
+	if err := s.repo.CreateOne(ctx, model); err != nil {
+		log.Error().Err(err).Msg("Failed to store API key")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	log.Info().Msgf("API key %s issued for %s", model.Prefix, model.Name)
+	return &dto.CreatedAPIKey{APIKey: model.ToDTO(), Key: key}, nil
+}
+
+// Authenticate returns active key matching plain key
+func (s *Service) Authenticate(ctx context.Context, key string) (*dto.APIKey, *appErrors.AppError) {
+	model, err := s.repo.FindOneOrNone(ctx, "key_hash = ?", hashKey(key))
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return nil, serviceErrors.ErrInvalidAPIKey
+		}
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to find API key")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	if model.RevokedAt != nil {
+		return nil, serviceErrors.ErrInvalidAPIKey
+	}
+	result := model.ToDTO()
+	return &result, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]dto.APIKey, *appErrors.AppError) {
+	models, err := s.repo.FindAll(ctx, nil)
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to list API keys")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	result := make([]dto.APIKey, len(models))
+	for i := range models {
+		result[i] = models[i].ToDTO()
+	}
+	return result, nil
+}
+
+// Revoke disables key, revoking already revoked key is a no-op
+func (s *Service) Revoke(ctx context.Context, id uint) *appErrors.AppError {
+	log := s.log.FromContext(ctx)
+	model, err := s.repo.FindOneOrNone(ctx, "id = ?", id)
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return serviceErrors.ErrAPIKeyNotFound
+		}
+		log.Error().Err(err).Msg("Failed to find API key")
+		return serviceErrors.ErrInternalServerError
+	}
+	if model.RevokedAt != nil {
+		return nil
+	}
+	now := s.now()
+	model.RevokedAt = &now
+	if err := s.repo.Update(ctx, model); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke API key")
+		return serviceErrors.ErrInternalServerError
+	}
+	log.Info().Msgf("API key %s revoked", model.Prefix)
+	return nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package errors
+
+import (
+	"net/http"
+
+	"weatherApi/internal/common/errors"
+)
+
+var (
+	ErrAPIKeyRequired      = errors.New(http.StatusUnauthorized, "API key required", nil)
+	ErrInvalidAPIKey       = errors.New(http.StatusUnauthorized, "Invalid API key", nil)
+	ErrAPIKeyNotFound      = errors.New(http.StatusNotFound, "API key not found", nil)
+	ErrInternalServerError = errors.New(http.StatusInternalServerError, "Internal server error", nil)
+)
@@ -0,0 +1,59 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"weatherApi/internal/repository/cache"
+
+	"github.com/rs/zerolog"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+// Fetch returns cached value of key, on miss value is fetched by a single caller across instances holding
+// cache lock while the others wait for it to be stored. Redis failures fall back to fetching without cache
+func Fetch[T any](
+	ctx context.Context,
+	log *zerolog.Logger,
+	cacheRepo cache.CacheRepoInterface[T],
+	key string,
+	fetch func(ctx context.Context) (*T, *appErrors.AppError),
+) (*T, *appErrors.AppError) {
+	resp, err := cacheRepo.Get(ctx, key)
+	if err != nil && !errors.Is(err, cache.ErrCacheIsEmpty) {
+		log.Error().Err(err).Msg("Redis error, caching is skipped!")
+		return fetch(ctx)
+	}
+	if resp != nil {
+		return resp, nil
+	}
+
+	locked, err := cacheRepo.AcquireLock(ctx, key)
+	if err != nil {
+		log.Error().Err(err).Msg("Redis failed to acquire lock")
+	}
+	if !locked {
+		response, err := cacheRepo.WaitForUnlock(ctx, key)
+		if err != nil {
+			return nil, serviceErrors.ErrInternalServerError
+		}
+		if response != nil {
+			return response, nil
+		}
+	} else {
+		defer func() {
+			if err := cacheRepo.ReleaseLock(ctx, key); err != nil {
+				log.Error().Err(err).Msg("Failed to release lock")
+			}
+		}()
+	}
+
+	result, appErr := fetch(ctx)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	_ = cacheRepo.Set(ctx, key, result)
+	return result, nil
+}
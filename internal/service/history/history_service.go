@@ -0,0 +1,75 @@
+package history
+
+import (
+	"context"
+	"math"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/observation"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+// maxRange limits number of buckets returned in a single response
+var maxRange = map[constants.Granularity]time.Duration{
+	constants.GranularityHour: 31 * 24 * time.Hour,
+	constants.GranularityDay:  366 * 24 * time.Hour,
+}
+
+type Service struct {
+	log             *logger.Logger
+	observationRepo observation.ObservationRepositoryInterface
+}
+
+func NewHistoryService(log *logger.Logger, observationRepo observation.ObservationRepositoryInterface) *Service {
+	return &Service{log: log, observationRepo: observationRepo}
+}
+
+func (s *Service) GetHistory(ctx context.Context, query dto.HistoryQuery) (*dto.HistoryResponse, *appErrors.AppError) {
+	log := s.log.FromContext(ctx)
+
+	if err := query.Location.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid location")
+		return nil, serviceErrors.ErrInvalidRequest
+	}
+	if !query.Granularity.IsValid() {
+		return nil, serviceErrors.ErrInvalidRequest
+	}
+	if !query.From.Before(query.To) || query.To.Sub(query.From) > maxRange[query.Granularity] {
+		return nil, serviceErrors.ErrInvalidHistoryRange
+	}
+
+	rows, err := s.observationRepo.Aggregate(ctx, query.Location.StorageKey(), query.From, query.To, query.Granularity)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to aggregate observations for %s", query.Location)
+		return nil, serviceErrors.ErrInternalServerError
+	}
+
+	points := make([]dto.HistoryPoint, len(rows))
+	for i, row := range rows {
+		points[i] = dto.HistoryPoint{
+			Time:        row.Bucket.UTC(),
+			Samples:     row.Samples,
+			Temperature: stats(row.MinTemperature, row.MaxTemperature, row.AvgTemperature),
+			Humidity:    stats(row.MinHumidity, row.MaxHumidity, row.AvgHumidity),
+			Pressure:    stats(row.MinPressure, row.MaxPressure, row.AvgPressure),
+			WindSpeed:   stats(row.MinWindSpeed, row.MaxWindSpeed, row.AvgWindSpeed),
+		}
+	}
+
+	return &dto.HistoryResponse{
+		Location:    query.Location.String(),
+		From:        query.From,
+		To:          query.To,
+		Granularity: query.Granularity,
+		Units:       constants.UnitsMetric,
+		Points:      points,
+	}, nil
+}
+
+func stats(minValue, maxValue, avgValue float64) dto.HistoryStats {
+	return dto.HistoryStats{Min: minValue, Max: maxValue, Avg: math.Round(avgValue*100) / 100}
+}
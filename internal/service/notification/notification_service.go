@@ -20,6 +20,10 @@ type Service struct {
 	SMTPClient provider.SMTPClientInterface
 	Publisher  broker.EventPublisher
 	Subscriber broker.EventSubscriber
+	// Suppressions is optional, emails are sent to every address when it's nil
+	Suppressions worker.SuppressionCheckerInterface
+	// Deliveries is optional, sent weather emails are not logged when it's nil
+	Deliveries worker.DeliveryRecorderInterface
 	SignalChan <-chan os.Signal
 }
 
@@ -47,15 +51,25 @@ func Run(ctx context.Context, service Service) error {
 	}()
 
 	go func() {
-		if err := worker.StartConfirmationWorker(service.Log, ctx, service.Subscriber, service.SMTPClient); err != nil {
+		if err := worker.StartConfirmationWorker(service.Log, ctx, service.Subscriber, service.SMTPClient, service.Suppressions); err != nil {
 			log.Fatal().Err(err).Msg("ConfirmationWorker error")
 		}
 	}()
 	go func() {
-		if err := worker.StartSubscriptionWorker(service.Log, ctx, service.Subscriber, service.SMTPClient); err != nil {
+		if err := worker.StartSubscriptionWorker(service.Log, ctx, service.Subscriber, service.SMTPClient, service.Suppressions, service.Deliveries); err != nil {
 			log.Fatal().Err(err).Msg("SubscriptionWorker error")
 		}
 	}()
+	go func() {
+		if err := worker.StartDigestWorker(service.Log, ctx, service.Subscriber, service.SMTPClient, service.Suppressions, service.Deliveries); err != nil {
+			log.Fatal().Err(err).Msg("DigestWorker error")
+		}
+	}()
+	go func() {
+		if err := worker.StartPrivacyWorker(service.Log, ctx, service.Subscriber, service.SMTPClient); err != nil {
+			log.Fatal().Err(err).Msg("PrivacyWorker error")
+		}
+	}()
 
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,14 @@
+package errors
+
+import (
+	"net/http"
+
+	"weatherApi/internal/common/errors"
+)
+
+var (
+	ErrInvalidInput        = errors.New(http.StatusBadRequest, "Invalid input", nil)
+	ErrInvalidToken        = errors.New(http.StatusNotFound, "Link is invalid or expired", nil)
+	ErrTooManyRequests     = errors.New(http.StatusTooManyRequests, "Too many privacy requests, try again later", nil)
+	ErrInternalServerError = errors.New(http.StatusInternalServerError, "Internal server error", nil)
+)
@@ -0,0 +1,290 @@
+package privacy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/base"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/privacy"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/privacy/errors"
+)
+
+const (
+	tokenBytes = 32
+	purgeBatch = 500
+)
+
+type UserRepositoryInterface interface {
+	FindOneOrNone(ctx context.Context, query any, args ...any) (*user.UserModel, error)
+}
+
+type SubscriptionRepositoryInterface interface {
+	FindAllByUserIDs(ctx context.Context, userIDs []uint) ([]subscription.SubscriptionModel, error)
+}
+
+type DeliveryRepositoryInterface interface {
+	FindByEmail(ctx context.Context, email string) ([]delivery.DeliveryModel, error)
+}
+
+type RateLimiterInterface interface {
+	AllowPer(ctx context.Context, bucket string, limit int, period time.Duration) dto.RateLimit
+}
+
+// Options configures privacy requests, zero UnconfirmedRetention disables purging of unconfirmed users
+// and nil Limiter disables per email throttling of emailed links
+type Options struct {
+	TokenTTL             time.Duration
+	RequestsPerHour      int
+	UnconfirmedRetention time.Duration
+	Limiter              RateLimiterInterface
+}
+
+// Service handles data export and erasure requests verified by a link sent to the email in question
+type Service struct {
+	log              *logger.Logger
+	userRepo         UserRepositoryInterface
+	subscriptionRepo SubscriptionRepositoryInterface
+	erasureRepo      privacy.ErasureRepositoryInterface
+	deliveryRepo     DeliveryRepositoryInterface
+	tokenRepo        privacy.TokenRepoInterface
+	publisher        broker.EventPublisher
+	options          Options
+	now              func() time.Time
+}
+
+func NewPrivacyService(
+	log *logger.Logger,
+	userRepo UserRepositoryInterface,
+	subscriptionRepo SubscriptionRepositoryInterface,
+	erasureRepo privacy.ErasureRepositoryInterface,
+	deliveryRepo DeliveryRepositoryInterface,
+	tokenRepo privacy.TokenRepoInterface,
+	publisher broker.EventPublisher,
+	options Options,
+) *Service {
+	return &Service{
+		log:              log,
+		userRepo:         userRepo,
+		subscriptionRepo: subscriptionRepo,
+		erasureRepo:      erasureRepo,
+		deliveryRepo:     deliveryRepo,
+		tokenRepo:        tokenRepo,
+		publisher:        publisher,
+		options:          options,
+		now:              time.Now,
+	}
+}
+
+// RequestLink emails verification link for export or erasure, unknown emails are accepted silently
+// so the endpoint can't be used to find out who is subscribed
+func (s *Service) RequestLink(ctx context.Context, request *dto.PrivacyRequest) *appErrors.AppError {
+	log := s.log.FromContext(ctx)
+	if !request.Action.IsValid() {
+		return serviceErrors.ErrInvalidInput
+	}
+	if !s.allow(ctx, request) {
+		log.Warn().Msgf("Privacy request for %s throttled", request.Email)
+		return serviceErrors.ErrTooManyRequests
+	}
+
+	found, appErr := s.findUser(ctx, request.Email)
+	if appErr != nil {
+		return appErr
+	}
+	if found == nil {
+		log.Info().Msgf("Privacy %s requested for unknown email %s", request.Action, request.Email)
+		return nil
+	}
+
+	secret := make([]byte, tokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		log.Error().Err(err).Msg("Failed to generate privacy token")
+		return serviceErrors.ErrInternalServerError
+	}
+	token := hex.EncodeToString(secret)
+	if err := s.tokenRepo.Save(ctx, hashToken(token), privacy.PrivacyToken{Email: found.Email, Action: request.Action}, s.options.TokenTTL); err != nil {
+		log.Error().Err(err).Msg("Failed to store privacy token")
+		return serviceErrors.ErrInternalServerError
+	}
+
+	payload, err := json.Marshal(dto.PrivacyLinkTask{Email: found.Email, Token: token, Action: request.Action})
+	if err != nil {
+		log.Error().Err(err).Msg("Error marshaling privacy link event")
+		return serviceErrors.ErrInternalServerError
+	}
+	traceID, _ := ctx.Value(constants.TraceID).(string)
+	if err := s.publisher.Publish(
+		broker.PrivacyLinkTasks,
+		payload,
+		broker.WithHeaders(amqp.Table{constants.HdrTraceID: traceID}),
+	); err != nil {
+		log.Error().Err(err).Msgf("Error publishing privacy link event for %s", found.Email)
+		return serviceErrors.ErrInternalServerError
+	}
+	log.Info().Msgf("Privacy %s link for %s is published", request.Action, found.Email)
+	return nil
+}
+
+// Export returns all data stored about user, export link can be used until it expires
+func (s *Service) Export(ctx context.Context, token string) (*dto.DataExport, *appErrors.AppError) {
+	log := s.log.FromContext(ctx)
+	pending, appErr := s.verify(ctx, token, constants.PrivacyExport)
+	if appErr != nil {
+		return nil, appErr
+	}
+	found, appErr := s.findUser(ctx, pending.Email)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if found == nil {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+
+	subs, err := s.subscriptionRepo.FindAllByUserIDs(ctx, []uint{found.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find subscriptions for export")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	deliveries, err := s.deliveryRepo.FindByEmail(ctx, found.Email)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find deliveries for export")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	export := &dto.DataExport{
+		GeneratedAt:   s.now().UTC(),
+		User:          dto.ExportUser{Email: found.Email, CreatedAt: found.CreatedAt},
+		Subscriptions: make([]dto.ExportSubscription, len(subs)),
+		Deliveries:    make([]dto.ExportDelivery, len(deliveries)),
+	}
+	for i := range deliveries {
+		export.Deliveries[i] = dto.ExportDelivery{
+			Kind:   deliveries[i].Kind,
+			Cities: deliveries[i].CityList(),
+			SentAt: deliveries[i].SentAt,
+		}
+	}
+	for i, sub := range subs {
+		export.Subscriptions[i] = dto.ExportSubscription{
+			City:              sub.City,
+			Frequency:         sub.Frequency,
+			Units:             sub.Units,
+			Lang:              sub.Lang,
+			IncludeAirQuality: sub.IncludeAirQuality,
+			AQIAlertThreshold: sub.AQIAlertThreshold,
+			CreatedAt:         sub.CreatedAt,
+			ConfirmedAt:       sub.ConfirmedAt,
+			LastSentAt:        sub.LastSentAt,
+		}
+		if sub.DeletedAt.Valid {
+			export.Subscriptions[i].UnsubscribedAt = &sub.DeletedAt.Time
+		}
+	}
+	log.Info().Msgf("Data export for %s generated", found.Email)
+	return export, nil
+}
+
+// Erase permanently deletes user with subscriptions and delivery log, link is single use and erasing twice is a no-op
+func (s *Service) Erase(ctx context.Context, token string) *appErrors.AppError {
+	log := s.log.FromContext(ctx)
+	pending, appErr := s.verify(ctx, token, constants.PrivacyErase)
+	if appErr != nil {
+		return appErr
+	}
+	found, appErr := s.findUser(ctx, pending.Email)
+	if appErr != nil {
+		return appErr
+	}
+	if found != nil {
+		if err := s.erasureRepo.EraseUsers(ctx, []uint{found.ID}); err != nil {
+			log.Error().Err(err).Msgf("Failed to erase user %d", found.ID)
+			return serviceErrors.ErrInternalServerError
+		}
+		log.Info().Msgf("User %d erased on request", found.ID)
+	}
+	if err := s.tokenRepo.Delete(ctx, hashToken(token)); err != nil {
+		log.Error().Err(err).Msg("Failed to delete used privacy token")
+	}
+	return nil
+}
+
+// PurgeUnconfirmed erases users who never confirmed a subscription within retention period,
+// returns number of erased users
+func (s *Service) PurgeUnconfirmed(ctx context.Context) (int, error) {
+	if s.options.UnconfirmedRetention <= 0 {
+		return 0, nil
+	}
+	cutoff := s.now().Add(-s.options.UnconfirmedRetention)
+	purged := 0
+	for {
+		ids, err := s.erasureRepo.FindUnconfirmedUserIDs(ctx, cutoff, purgeBatch)
+		if err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+		if err := s.erasureRepo.EraseUsers(ctx, ids); err != nil {
+			return purged, err
+		}
+		purged += len(ids)
+		if len(ids) < purgeBatch {
+			return purged, nil
+		}
+	}
+}
+
+func (s *Service) verify(ctx context.Context, token string, action constants.PrivacyAction) (*privacy.PrivacyToken, *appErrors.AppError) {
+	pending, err := s.tokenRepo.Get(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, privacy.ErrTokenNotFound) {
+			return nil, serviceErrors.ErrInvalidToken
+		}
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to read privacy token")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	if pending.Action != action {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+	return pending, nil
+}
+
+func (s *Service) findUser(ctx context.Context, email string) (*user.UserModel, *appErrors.AppError) {
+	found, err := s.userRepo.FindOneOrNone(ctx, "LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return nil, nil
+		}
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to find user")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	return found, nil
+}
+
+func (s *Service) allow(ctx context.Context, request *dto.PrivacyRequest) bool {
+	if s.options.Limiter == nil || s.options.RequestsPerHour <= 0 {
+		return true
+	}
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+	return s.options.Limiter.AllowPer(ctx, "privacy:email:"+email, s.options.RequestsPerHour, time.Hour).Allowed
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
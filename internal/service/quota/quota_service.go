@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"context"
+	"slices"
+	"time"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/metrics"
+	"weatherApi/internal/repository/quota"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/weather/errors"
+)
+
+// Service tracks provider calls per UTC day and enforces configured daily limits.
+// Counter is checked before it is incremented, so concurrent requests may overshoot the limit by a few calls.
+type Service struct {
+	log     *logger.Logger
+	repo    quota.QuotaRepoInterface
+	limits  map[string]int64
+	metrics *metrics.QuotaMetrics
+	now     func() time.Time
+}
+
+// NewQuotaService creates quota service, limits are keyed by provider account name, 0 means unlimited
+func NewQuotaService(log *logger.Logger, repo quota.QuotaRepoInterface, limits map[string]int64, quotaMetrics *metrics.QuotaMetrics) *Service {
+	if quotaMetrics != nil {
+		for provider, limit := range limits {
+			quotaMetrics.SetLimit(provider, limit)
+		}
+	}
+	return &Service{log: log, repo: repo, limits: limits, metrics: quotaMetrics, now: time.Now}
+}
+
+// Acquire registers a call to provider, false is returned when daily quota is exhausted.
+// Redis failures don't block providers.
+func (s *Service) Acquire(ctx context.Context, provider string) bool {
+	log := s.log.FromContext(ctx)
+	today := s.now()
+
+	if limit := s.limits[provider]; limit > 0 {
+		used, err := s.repo.Get(ctx, provider, today)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to get %s quota usage", provider)
+		} else if used >= limit {
+			log.Warn().Msgf("%s: daily quota of %d calls is exhausted", provider, limit)
+			if s.metrics != nil {
+				s.metrics.IncExhausted(provider)
+			}
+			return false
+		}
+	}
+
+	used, err := s.repo.Increment(ctx, provider, today)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to track %s call", provider)
+		return true
+	}
+	if s.metrics != nil {
+		s.metrics.ObserveCall(provider, used)
+	}
+	return true
+}
+
+// Usage returns today's usage of all configured providers ordered by name
+func (s *Service) Usage(ctx context.Context) ([]dto.ProviderQuota, *appErrors.AppError) {
+	today := s.now().UTC()
+	resetsAt := time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	providers := make([]string, 0, len(s.limits))
+	for provider := range s.limits {
+		providers = append(providers, provider)
+	}
+	slices.Sort(providers)
+
+	usage := make([]dto.ProviderQuota, len(providers))
+	for i, provider := range providers {
+		used, err := s.repo.Get(ctx, provider, today)
+		if err != nil {
+			s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to get %s quota usage", provider)
+			return nil, serviceErrors.ErrInternalServerError
+		}
+		limit := s.limits[provider]
+		usage[i] = dto.ProviderQuota{
+			Provider: provider,
+			Used:     used,
+			Limit:    limit,
+			ResetsAt: resetsAt,
+		}
+		if limit > 0 {
+			remaining := max(limit-used, 0)
+			usage[i].Remaining = &remaining
+			usage[i].Exhausted = remaining == 0
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/ratelimit"
+)
+
+// Service applies token bucket per client: bucket holds a period worth of requests and is refilled evenly,
+// so short bursts up to the limit are allowed. Redis failures don't block clients.
+type Service struct {
+	log  *logger.Logger
+	repo ratelimit.RateLimitRepoInterface
+	now  func() time.Time
+}
+
+func NewRateLimitService(log *logger.Logger, repo ratelimit.RateLimitRepoInterface) *Service {
+	return &Service{log: log, repo: repo, now: time.Now}
+}
+
+func (s *Service) Allow(ctx context.Context, bucket string, perMinute int) dto.RateLimit {
+	return s.AllowPer(ctx, bucket, perMinute, time.Minute)
+}
+
+// Peek reports whether Allow would let request through now without using up a request, it's used to reject
+// requests before doing work that is charged to the bucket only when it fails
+func (s *Service) Peek(ctx context.Context, bucket string, perMinute int) dto.RateLimit {
+	rate := float64(perMinute) / time.Minute.Seconds()
+	tokens, err := s.repo.Peek(ctx, bucket, perMinute, rate, s.now())
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to check rate limit of %s", bucket)
+		return dto.RateLimit{Allowed: true, Limit: perMinute, Remaining: perMinute}
+	}
+	return rateLimit(tokens >= 1, tokens, perMinute, rate)
+}
+
+// AllowPer is Allow for limits over arbitrary period
+func (s *Service) AllowPer(ctx context.Context, bucket string, limit int, period time.Duration) dto.RateLimit {
+	rate := float64(limit) / period.Seconds()
+	allowed, tokens, err := s.repo.Take(ctx, bucket, limit, rate, s.now())
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msgf("Failed to check rate limit of %s", bucket)
+		return dto.RateLimit{Allowed: true, Limit: limit, Remaining: limit}
+	}
+	return rateLimit(allowed, tokens, limit, rate)
+}
+
+func rateLimit(allowed bool, tokens float64, limit int, rate float64) dto.RateLimit {
+	missing := float64(limit) - tokens
+	if !allowed {
+		missing = 1 - tokens
+	}
+	return dto.RateLimit{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(missing / rate * float64(time.Second)),
+	}
+}
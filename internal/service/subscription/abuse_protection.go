@@ -0,0 +1,117 @@
+package subscription
+
+import (
+	"context"
+	"strings"
+	"time"
+	"weatherApi/internal/dto"
+
+	commonErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/subscription/errors"
+)
+
+type RateLimiterInterface interface {
+	AllowPer(ctx context.Context, bucket string, limit int, period time.Duration) dto.RateLimit
+}
+
+type CaptchaVerifierInterface interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// defaultDisposableDomains are widely used throwaway mailbox services, extended with configured domains
+var defaultDisposableDomains = []string{
+	"10minutemail.com",
+	"discard.email",
+	"dispostable.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"mailinator.com",
+	"maildrop.cc",
+	"sharklasers.com",
+	"temp-mail.org",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// AbuseProtection configures subscribe checks, zero limits disable throttling and nil Captcha disables verification
+type AbuseProtection struct {
+	Limiter           RateLimiterInterface
+	Captcha           CaptchaVerifierInterface
+	DisposableDomains []string
+	EmailSendsPerHour int
+	IPSendsPerHour    int
+	// ResendCooldown is period after token issue when repeated subscribe resends the same token
+	ResendCooldown time.Duration
+}
+
+type abuseGuard struct {
+	AbuseProtection
+	disposable map[string]struct{}
+}
+
+func newAbuseGuard(protection AbuseProtection) *abuseGuard {
+	guard := &abuseGuard{AbuseProtection: protection, disposable: make(map[string]struct{})}
+	for _, domain := range append(defaultDisposableDomains, protection.DisposableDomains...) {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			guard.disposable[domain] = struct{}{}
+		}
+	}
+	return guard
+}
+
+// check runs all checks before confirmation email is sent, captcha goes first so bots don't consume throttling budget
+func (g *abuseGuard) check(ctx context.Context, request *dto.SubscribeRequest) *commonErrors.AppError {
+	if g.Captcha != nil {
+		if request.CaptchaToken == "" {
+			return serviceErrors.ErrCaptchaRequired
+		}
+		ok, err := g.Captcha.Verify(ctx, request.CaptchaToken, request.ClientIP)
+		if err != nil {
+			return commonErrors.New(serviceErrors.ErrInternalServerError.Code, serviceErrors.ErrInternalServerError.Message, err)
+		}
+		if !ok {
+			return serviceErrors.ErrCaptchaFailed
+		}
+	}
+
+	return g.checkSend(ctx, request.Email, request.ClientIP)
+}
+
+// checkSend rejects disposable addresses and throttles confirmation emails per address and client IP,
+// email change requests skip captcha as they are authorized by manage token
+func (g *abuseGuard) checkSend(ctx context.Context, email, clientIP string) *commonErrors.AppError {
+	if g.isDisposable(email) {
+		return serviceErrors.ErrDisposableEmail
+	}
+
+	if g.Limiter == nil {
+		return nil
+	}
+	if g.IPSendsPerHour > 0 && clientIP != "" {
+		if !g.Limiter.AllowPer(ctx, "subscribe:ip:"+clientIP, g.IPSendsPerHour, time.Hour).Allowed {
+			return serviceErrors.ErrTooManyRequests
+		}
+	}
+	if g.EmailSendsPerHour > 0 {
+		if !g.Limiter.AllowPer(ctx, "subscribe:email:"+strings.ToLower(email), g.EmailSendsPerHour, time.Hour).Allowed {
+			return serviceErrors.ErrTooManyRequests
+		}
+	}
+	return nil
+}
+
+func (g *abuseGuard) isDisposable(email string) bool {
+	_, domain, found := strings.Cut(strings.ToLower(email), "@")
+	if !found {
+		return false
+	}
+	// subdomains of disposable services are rejected as well
+	for domain != "" {
+		if _, ok := g.disposable[domain]; ok {
+			return true
+		}
+		_, domain, _ = strings.Cut(domain, ".")
+	}
+	return false
+}
@@ -12,4 +12,10 @@ var (
 	ErrInternalServerError = errors.New(http.StatusInternalServerError, "Internal server error", nil)
 	ErrTokenNotFound       = errors.New(http.StatusNotFound, "Token not found", nil)
 	ErrInvalidToken        = errors.New(http.StatusBadRequest, "Invalid token", nil)
+	ErrDisposableEmail     = errors.New(http.StatusBadRequest, "Disposable email addresses are not allowed", nil)
+	ErrCaptchaRequired     = errors.New(http.StatusBadRequest, "Captcha is required", nil)
+	ErrCaptchaFailed       = errors.New(http.StatusForbidden, "Captcha verification failed", nil)
+	ErrSameEmail           = errors.New(http.StatusBadRequest, "New email is the same as the current one", nil)
+	ErrDeliverySuspended   = errors.New(http.StatusConflict, "Emails to this address are suspended after bounces or spam reports, change email to resume", nil)
+	ErrTooManyRequests     = errors.New(http.StatusTooManyRequests, "Too many confirmation requests, try again later", nil)
 )
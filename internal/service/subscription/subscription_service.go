@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 	"weatherApi/internal/broker"
+	"weatherApi/internal/common/tokens"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/repository/base"
 
@@ -31,7 +33,15 @@ type RepositoryInterface interface {
 	CreateOne(ctx context.Context, entity *subscription.SubscriptionModel) error
 	Update(ctx context.Context, entity *subscription.SubscriptionModel) error
 	Delete(ctx context.Context, entity *subscription.SubscriptionModel) error
-	FindAllSubscriptionsByFrequency(ctx context.Context, frequency constants.Frequency) ([]subscription.SubscriptionModel, error)
+	FindDuePage(
+		ctx context.Context,
+		frequency constants.Frequency,
+		order subscription.DueOrder,
+		after subscription.DueCursor,
+		limit int,
+	) ([]subscription.SubscriptionModel, error)
+	FindLatestDeleted(ctx context.Context, userID uint, city string) (*subscription.SubscriptionModel, error)
+	Restore(ctx context.Context, entity *subscription.SubscriptionModel) error
 }
 
 type SubscriptionService struct {
@@ -40,6 +50,8 @@ type SubscriptionService struct {
 	UserRepo         user.UserRepositoryInterface
 	publisher        broker.EventPublisher
 	tokenLifeMinutes int
+	signer           *tokens.Signer
+	abuseGuard       *abuseGuard
 }
 
 func NewSubscriptionService(
@@ -48,6 +60,7 @@ func NewSubscriptionService(
 	userRepo user.UserRepositoryInterface,
 	publisher broker.EventPublisher,
 	tokenLifeMinutes int,
+	signer *tokens.Signer,
 ) *SubscriptionService {
 	return &SubscriptionService{
 		log:              log,
@@ -55,18 +68,29 @@ func NewSubscriptionService(
 		UserRepo:         userRepo,
 		publisher:        publisher,
 		tokenLifeMinutes: tokenLifeMinutes,
+		signer:           signer,
 	}
 }
 
+// WithAbuseProtection enables captcha, disposable domains and confirmation sends throttling checks on subscribe
+func (s *SubscriptionService) WithAbuseProtection(protection AbuseProtection) *SubscriptionService {
+	s.abuseGuard = newAbuseGuard(protection)
+	return s
+}
+
 func (s *SubscriptionService) Subscribe(ctx context.Context, subscribeRequest *dto.SubscribeRequest) *commonErrors.AppError {
 	log := s.log.FromContext(ctx)
-	traceID, _ := ctx.Value(constants.TraceID).(string)
 	log.Info().Msgf("Handling subscribe request for %s: %s", subscribeRequest.Email, subscribeRequest.City)
-	token, err := s.generateConfirmationToken()
-	if err != nil {
-		return serviceErrors.ErrInternalServerError
+	options := subscribeRequest.WeatherOptions()
+	if !options.IsValid() {
+		return serviceErrors.ErrInvalidInput
+	}
+	if s.abuseGuard != nil {
+		if appErr := s.abuseGuard.check(ctx, subscribeRequest); appErr != nil {
+			log.Warn().Err(appErr).Msgf("Subscribe request for %s from %s rejected", subscribeRequest.Email, subscribeRequest.ClientIP)
+			return appErr
+		}
 	}
-
 	userModel := &user.UserModel{
 		Email: subscribeRequest.Email,
 	}
@@ -80,47 +104,262 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, subscribeRequest *d
 
 	expiry := time.Now().Add(time.Duration(s.tokenLifeMinutes) * time.Minute)
 
-	existing, err := s.SubscriptionRepo.FindOneOrNone(ctx, "user_id = ?", user.ID)
+	// user may subscribe to several cities, one subscription per city
+	created := false
+	existing, err := s.SubscriptionRepo.FindOneOrNone(
+		ctx, "user_id = ? AND LOWER(TRIM(city)) = LOWER(TRIM(?))", user.ID, subscribeRequest.City,
+	)
 	if err != nil {
-		if errors.Is(err, base.ErrNotFound) {
-			existing = &subscription.SubscriptionModel{
-				City:         subscribeRequest.City,
-				Frequency:    constants.Frequency(subscribeRequest.Frequency),
-				UserID:       user.ID,
-				IsConfirmed:  false,
-				ConfirmToken: token,
-				TokenExpires: expiry,
-			}
-
-			if err := s.SubscriptionRepo.CreateOne(ctx, existing); err != nil {
-				log.Error().Err(err).Msg("Error creating new subscription")
-				return serviceErrors.ErrInternalServerError
-			}
-		} else {
+		if !errors.Is(err, base.ErrNotFound) {
 			log.Error().Err(err).Msg("Error perfoming subscription find request")
 			return serviceErrors.ErrInternalServerError
 		}
+		existing, err = s.newSubscription(ctx, user.ID, subscribeRequest, options, expiry)
+		if err != nil {
+			log.Error().Err(err).Msg("Error creating new subscription")
+			return serviceErrors.ErrInternalServerError
+		}
+		created = true
 	}
 
 	if existing.IsConfirmed {
-		log.Error().Msgf("%s already subscribed!", subscribeRequest.Email)
+		log.Error().Msgf("%s already subscribed to %s!", subscribeRequest.Email, subscribeRequest.City)
 		return serviceErrors.ErrAlreadySubscribed
 	}
 
-	existing.ConfirmToken = token
-	existing.TokenExpires = expiry
+	// confirm token is bound to expiry, so moving it revokes links sent before
+	if !created && s.withinResendCooldown(existing) {
+		log.Info().Msgf("Resending existing confirmation token for %s", subscribeRequest.Email)
+	} else {
+		existing.TokenExpires = expiry
+	}
 	existing.Frequency = constants.Frequency(subscribeRequest.Frequency)
+	existing.Units = options.Units
+	existing.Lang = options.Lang
+	existing.IncludeAirQuality = subscribeRequest.IncludeAirQuality
+	existing.AQIAlertThreshold = subscribeRequest.AQIAlertThreshold
 
 	if err := s.SubscriptionRepo.Update(ctx, existing); err != nil {
 		log.Error().Err(err).Msg("Error perfoming subscription update request")
 		return serviceErrors.ErrInternalServerError
 	}
 
-	task := dto.ConfirmationEmailTask{
-		Email: subscribeRequest.Email,
-		Token: token,
-		City:  subscribeRequest.City,
+	return s.publishConfirmation(ctx, subscribeRequest.Email, existing)
+}
+
+// newSubscription restores the latest unsubscribed subscription of user to the city keeping its id and delivery
+// history, or creates a new one when there is none. Either way it has to be confirmed again
+func (s *SubscriptionService) newSubscription(
+	ctx context.Context,
+	userID uint,
+	request *dto.SubscribeRequest,
+	options dto.WeatherOptions,
+	expiry time.Time,
+) (*subscription.SubscriptionModel, error) {
+	sub, err := s.SubscriptionRepo.FindLatestDeleted(ctx, userID, request.City)
+	if err != nil && !errors.Is(err, base.ErrNotFound) {
+		return nil, err
+	}
+	restore := err == nil
+	if !restore {
+		sub = &subscription.SubscriptionModel{UserID: userID}
+	}
+
+	sub.City = request.City
+	sub.Frequency = constants.Frequency(request.Frequency)
+	sub.Units = options.Units
+	sub.Lang = options.Lang
+	sub.IncludeAirQuality = request.IncludeAirQuality
+	sub.AQIAlertThreshold = request.AQIAlertThreshold
+	sub.IsConfirmed = false
+	sub.ConfirmedAt = nil
+	sub.TokenExpires = expiry
+	sub.ReminderSentAt = nil
+	sub.PausedAt = nil
+	sub.PauseReason = nil
+	sub.ResumeAt = nil
+	sub.PendingEmail = nil
+	sub.EmailChangeExpires = nil
+
+	if restore {
+		// links emailed to the previous subscriber must not manage restored subscription
+		sub.TokenVersion++
+		sub.LegacyConfirmToken = nil
+		s.log.FromContext(ctx).Info().Msgf("Restoring unsubscribed subscription %d", sub.ID)
+		return sub, s.SubscriptionRepo.Restore(ctx, sub)
+	}
+	return sub, s.SubscriptionRepo.CreateOne(ctx, sub)
+}
+
+// RequestEmailChange sends confirmation link to new address of subscription manage token belongs to,
+// subscription keeps current address until the link is opened
+func (s *SubscriptionService) RequestEmailChange(
+	ctx context.Context,
+	token string,
+	request *dto.EmailChangeRequest,
+) *commonErrors.AppError {
+	log := s.log.FromContext(ctx)
+	sub, appErr := s.findByToken(ctx, token, tokens.PurposeManage)
+	if appErr != nil {
+		return appErr
+	}
+	current, err := s.UserRepo.FindOneOrNone(ctx, "id = ?", sub.UserID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error loading user of subscription %d", sub.ID)
+		return serviceErrors.ErrInternalServerError
+	}
+
+	email := strings.TrimSpace(request.Email)
+	if strings.EqualFold(email, current.Email) {
+		return serviceErrors.ErrSameEmail
+	}
+	if s.abuseGuard != nil {
+		if appErr := s.abuseGuard.checkSend(ctx, email, request.ClientIP); appErr != nil {
+			log.Warn().Err(appErr).Msgf("Email change to %s from %s rejected", email, request.ClientIP)
+			return appErr
+		}
+	}
+	if appErr := s.ensureNotSubscribed(ctx, email, sub); appErr != nil {
+		return appErr
+	}
+
+	// new token is bound to new expiry, so links sent for earlier requests stop working
+	expires := time.Now().Add(time.Duration(s.tokenLifeMinutes) * time.Minute)
+	sub.PendingEmail = &email
+	sub.EmailChangeExpires = &expires
+	if err := s.SubscriptionRepo.Update(ctx, sub); err != nil {
+		log.Error().Err(err).Msg("Error perfoming subscription update request")
+		return serviceErrors.ErrInternalServerError
+	}
+
+	changeToken, err := s.signer.Sign(tokens.PurposeEmailChange, sub.ID, sub.TokenVersion, expires)
+	if err != nil {
+		log.Error().Err(err).Msg("Error signing email change token")
+		return serviceErrors.ErrInternalServerError
 	}
+	return s.publishConfirmationTask(ctx, dto.ConfirmationEmailTask{
+		Email:       email,
+		Token:       changeToken,
+		City:        sub.City,
+		EmailChange: true,
+	})
+}
+
+// confirmEmailChange moves subscription to pending address, previous user left without subscriptions
+// is removed by maintenance job
+func (s *SubscriptionService) confirmEmailChange(ctx context.Context, claims *tokens.Claims) *commonErrors.AppError {
+	log := s.log.FromContext(ctx)
+	sub, err := s.SubscriptionRepo.FindOneOrNone(ctx, "id = ? AND is_confirmed = ?", claims.SubscriptionID, true)
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return serviceErrors.ErrTokenNotFound
+		}
+		return serviceErrors.ErrInternalServerError
+	}
+	// token issued before the last email change request or change already confirmed
+	if claims.Version != sub.TokenVersion || sub.PendingEmail == nil || sub.EmailChangeExpires == nil || claims.ExpiresAt != sub.EmailChangeExpires.Unix() {
+		return serviceErrors.ErrInvalidToken
+	}
+
+	email := *sub.PendingEmail
+	if appErr := s.ensureNotSubscribed(ctx, email, sub); appErr != nil {
+		return appErr
+	}
+	previous, err := s.UserRepo.FindOneOrNone(ctx, "id = ?", sub.UserID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error loading user of subscription %d", sub.ID)
+		return serviceErrors.ErrInternalServerError
+	}
+	newUser, err := s.UserRepo.FindOneOrCreate(ctx, map[string]any{"email": email}, &user.UserModel{Email: email})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error creating user for %s", email)
+		return serviceErrors.ErrInternalServerError
+	}
+
+	sub.UserID = newUser.ID
+	sub.AddPreviousEmail(previous.Email)
+	// links emailed to previous address must not manage subscription any more
+	sub.TokenVersion++
+	sub.LegacyConfirmToken = nil
+	sub.PendingEmail = nil
+	sub.EmailChangeExpires = nil
+	// delivery problems of previous address don't apply to the new one
+	if sub.PauseReason != nil && (*sub.PauseReason == constants.PauseBounced || *sub.PauseReason == constants.PauseComplaint) {
+		sub.PausedAt = nil
+		sub.PauseReason = nil
+	}
+	if err := s.SubscriptionRepo.Update(ctx, sub); err != nil {
+		log.Error().Err(err).Msg("Error perfoming subscription update request")
+		return serviceErrors.ErrInternalServerError
+	}
+	log.Info().Msgf("Subscription %d moved to %s", sub.ID, email)
+	return nil
+}
+
+// ensureNotSubscribed rejects moving subscription to address which already has another subscription to its city,
+// addresses are compared case insensitively
+func (s *SubscriptionService) ensureNotSubscribed(
+	ctx context.Context,
+	email string,
+	sub *subscription.SubscriptionModel,
+) *commonErrors.AppError {
+	existingUser, err := s.UserRepo.FindOneOrNone(ctx, "LOWER(email) = LOWER(?)", email)
+	if errors.Is(err, base.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return serviceErrors.ErrInternalServerError
+	}
+	_, err = s.SubscriptionRepo.FindOneOrNone(
+		ctx, "user_id = ? AND LOWER(TRIM(city)) = LOWER(TRIM(?)) AND id <> ?", existingUser.ID, sub.City, sub.ID,
+	)
+	if err == nil {
+		return serviceErrors.ErrAlreadySubscribed
+	}
+	if !errors.Is(err, base.ErrNotFound) {
+		return serviceErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// ResendConfirmation sends confirmation email for not yet confirmed subscription bypassing throttling,
+// expired token is reissued, sub must have User loaded
+func (s *SubscriptionService) ResendConfirmation(ctx context.Context, sub *subscription.SubscriptionModel) *commonErrors.AppError {
+	log := s.log.FromContext(ctx)
+	if sub.IsConfirmed {
+		return serviceErrors.ErrAlreadySubscribed
+	}
+	if !time.Now().Before(sub.TokenExpires) {
+		sub.TokenExpires = time.Now().Add(time.Duration(s.tokenLifeMinutes) * time.Minute)
+		if err := s.SubscriptionRepo.Update(ctx, sub); err != nil {
+			log.Error().Err(err).Msg("Error perfoming subscription update request")
+			return serviceErrors.ErrInternalServerError
+		}
+	}
+	return s.publishConfirmation(ctx, sub.User.Email, sub)
+}
+
+func (s *SubscriptionService) publishConfirmation(
+	ctx context.Context,
+	email string,
+	sub *subscription.SubscriptionModel,
+) *commonErrors.AppError {
+	log := s.log.FromContext(ctx)
+	token, err := s.signer.Sign(tokens.PurposeConfirm, sub.ID, sub.TokenVersion, sub.TokenExpires)
+	if err != nil {
+		log.Error().Err(err).Msg("Error signing confirmation token")
+		return serviceErrors.ErrInternalServerError
+	}
+	return s.publishConfirmationTask(ctx, dto.ConfirmationEmailTask{
+		Email: email,
+		Token: token,
+		City:  sub.City,
+	})
+}
+
+func (s *SubscriptionService) publishConfirmationTask(ctx context.Context, task dto.ConfirmationEmailTask) *commonErrors.AppError {
+	log := s.log.FromContext(ctx)
+	traceID, _ := ctx.Value(constants.TraceID).(string)
 	payload, err := json.Marshal(task)
 	if err != nil {
 		log.Error().Err(err).Msg("Error marshaling confirmation event")
@@ -131,19 +370,28 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, subscribeRequest *d
 		payload,
 		broker.WithHeaders(amqp.Table{constants.HdrTraceID: traceID}),
 	); err != nil {
-		log.Error().Err(err).Msgf("Error publishing confirmation event for %s", subscribeRequest.Email)
+		log.Error().Err(err).Msgf("Error publishing confirmation event for %s", task.Email)
 		return serviceErrors.ErrInternalServerError
 	}
-	log.Info().Msgf("Send confirmation letter task for %s is published!", subscribeRequest.Email)
+	log.Info().Msgf("Send confirmation letter task for %s is published!", task.Email)
 	return nil
 }
 
+// ConfirmSubscription confirms new subscription or email change, both links lead to the same confirm page
 func (s *SubscriptionService) ConfirmSubscription(ctx context.Context, token string) *commonErrors.AppError {
-	subscription, err := s.SubscriptionRepo.FindOneOrNone(
-		ctx,
-		"confirm_token = ? AND deleted_at IS NULL",
-		token,
-	)
+	claims, err := s.signer.Verify(token, tokens.PurposeConfirm)
+	if errors.Is(err, tokens.ErrInvalidToken) {
+		if changeClaims, changeErr := s.signer.Verify(token, tokens.PurposeEmailChange); changeErr == nil {
+			return s.confirmEmailChange(ctx, changeClaims)
+		}
+	}
+	if err != nil {
+		if isLegacyToken(token) {
+			return s.confirmLegacy(ctx, token)
+		}
+		return serviceErrors.ErrInvalidToken
+	}
+	subscription, err := s.SubscriptionRepo.FindOneOrNone(ctx, "id = ?", claims.SubscriptionID)
 	if err != nil {
 		if errors.Is(err, base.ErrNotFound) {
 			return serviceErrors.ErrTokenNotFound
@@ -155,7 +403,8 @@ func (s *SubscriptionService) ConfirmSubscription(ctx context.Context, token str
 		return serviceErrors.ErrAlreadySubscribed
 	}
 
-	if time.Now().After(subscription.TokenExpires) {
+	// token issued before the last resend or resubscribe
+	if claims.Version != subscription.TokenVersion || claims.ExpiresAt != subscription.TokenExpires.Unix() {
 		return serviceErrors.ErrInvalidToken
 	}
 	now := time.Now()
@@ -170,30 +419,186 @@ func (s *SubscriptionService) ConfirmSubscription(ctx context.Context, token str
 }
 
 func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) *commonErrors.AppError {
-	subscription, err := s.SubscriptionRepo.FindOneOrNone(
-		ctx,
-		"confirm_token = ? AND is_confirmed = ?",
-		token,
-		true,
-	)
+	subscription, appErr := s.findByToken(ctx, token, tokens.PurposeUnsubscribe)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := s.SubscriptionRepo.Delete(ctx, subscription); err != nil {
+		return serviceErrors.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Settings returns subscription settings for manage link from weather emails
+func (s *SubscriptionService) Settings(ctx context.Context, token string) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	return s.settingsByToken(ctx, token, tokens.PurposeManage)
+}
+
+// UnsubscribePreview returns settings of subscription unsubscribe token belongs to without changing it,
+// unsubscribe landing page shows them before asking to confirm
+func (s *SubscriptionService) UnsubscribePreview(ctx context.Context, token string) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	return s.settingsByToken(ctx, token, tokens.PurposeUnsubscribe)
+}
+
+func (s *SubscriptionService) settingsByToken(
+	ctx context.Context,
+	token string,
+	purpose tokens.Purpose,
+) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	sub, appErr := s.findByToken(ctx, token, purpose)
+	if appErr != nil {
+		return nil, appErr
+	}
+	settings := &dto.SubscriptionSettings{
+		City:              sub.City,
+		Frequency:         sub.Frequency,
+		Units:             sub.Units,
+		Lang:              sub.Lang,
+		IncludeAirQuality: sub.IncludeAirQuality,
+		AQIAlertThreshold: sub.AQIAlertThreshold,
+	}
+	if sub.PendingEmail != nil && sub.EmailChangeExpires != nil && time.Now().Before(*sub.EmailChangeExpires) {
+		settings.PendingEmail = sub.PendingEmail
+	}
+	if sub.IsPaused(time.Now()) {
+		settings.Paused = true
+		settings.PauseReason = sub.PauseReason
+		settings.ResumeAt = sub.ResumeAt
+	}
+	return settings, nil
+}
+
+// Pause holds weather emails of subscription manage token belongs to, request with Days resumes them
+// automatically. Repeated pause replaces resume date
+func (s *SubscriptionService) Pause(
+	ctx context.Context,
+	token string,
+	request *dto.PauseRequest,
+) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	return s.updatePause(ctx, token, func(sub *subscription.SubscriptionModel, now time.Time) {
+		reason := constants.PauseUser
+		sub.PausedAt = &now
+		sub.PauseReason = &reason
+		sub.ResumeAt = nil
+		if request.Days > 0 {
+			resumeAt := now.AddDate(0, 0, request.Days)
+			sub.ResumeAt = &resumeAt
+		}
+	})
+}
+
+// Resume lifts pause requested by subscriber, pauses caused by delivery problems stay
+func (s *SubscriptionService) Resume(ctx context.Context, token string) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	return s.updatePause(ctx, token, func(sub *subscription.SubscriptionModel, _ time.Time) {
+		sub.PausedAt = nil
+		sub.PauseReason = nil
+		sub.ResumeAt = nil
+	})
+}
+
+func (s *SubscriptionService) updatePause(
+	ctx context.Context,
+	token string,
+	apply func(sub *subscription.SubscriptionModel, now time.Time),
+) (*dto.SubscriptionSettings, *commonErrors.AppError) {
+	log := s.log.FromContext(ctx)
+	sub, appErr := s.findByToken(ctx, token, tokens.PurposeManage)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if sub.PausedAt != nil && sub.PauseReason != nil && *sub.PauseReason != constants.PauseUser {
+		return nil, serviceErrors.ErrDeliverySuspended
+	}
+
+	apply(sub, time.Now())
+	if err := s.SubscriptionRepo.Update(ctx, sub); err != nil {
+		log.Error().Err(err).Msg("Error perfoming subscription update request")
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	return s.Settings(ctx, token)
+}
+
+// findByToken returns confirmed subscription of link token, token issued for another version of subscription
+// is rejected. UUID tokens of links emailed before tokens were signed are accepted for unsubscribe
+func (s *SubscriptionService) findByToken(
+	ctx context.Context,
+	token string,
+	purpose tokens.Purpose,
+) (*subscription.SubscriptionModel, *commonErrors.AppError) {
+	var sub *subscription.SubscriptionModel
+	var claims *tokens.Claims
+	var err error
+	if purpose == tokens.PurposeUnsubscribe && isLegacyToken(token) {
+		sub, err = s.SubscriptionRepo.FindOneOrNone(ctx, "confirm_token = ? AND is_confirmed = ?", token, true)
+	} else {
+		var appErr *commonErrors.AppError
+		claims, appErr = s.verifyToken(token, purpose)
+		if appErr != nil {
+			return nil, appErr
+		}
+		sub, err = s.SubscriptionRepo.FindOneOrNone(ctx, "id = ? AND is_confirmed = ?", claims.SubscriptionID, true)
+	}
+	if err != nil {
+		if errors.Is(err, base.ErrNotFound) {
+			return nil, serviceErrors.ErrTokenNotFound
+		}
+		return nil, serviceErrors.ErrInternalServerError
+	}
+	if claims != nil && claims.Version != sub.TokenVersion {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+	return sub, nil
+}
+
+// confirmLegacy confirms subscription by UUID token emailed before tokens were signed,
+// it is accepted until its original expiry
+func (s *SubscriptionService) confirmLegacy(ctx context.Context, token string) *commonErrors.AppError {
+	subscription, err := s.SubscriptionRepo.FindOneOrNone(ctx, "confirm_token = ?", token)
 	if err != nil {
 		if errors.Is(err, base.ErrNotFound) {
 			return serviceErrors.ErrTokenNotFound
 		}
 		return serviceErrors.ErrInternalServerError
 	}
-
-	if err := s.SubscriptionRepo.Delete(ctx, subscription); err != nil {
+	if subscription.IsConfirmed {
+		return serviceErrors.ErrAlreadySubscribed
+	}
+	now := time.Now()
+	if !now.Before(subscription.TokenExpires) {
+		return serviceErrors.ErrInvalidToken
+	}
+	subscription.IsConfirmed = true
+	subscription.ConfirmedAt = &now
+	if err := s.SubscriptionRepo.Update(ctx, subscription); err != nil {
 		return serviceErrors.ErrInternalServerError
 	}
-
 	return nil
 }
 
-func (s *SubscriptionService) generateConfirmationToken() (string, error) {
-	token, err := uuid.NewRandom()
+// isLegacyToken reports UUID tokens of links emailed before tokens were signed
+func isLegacyToken(token string) bool {
+	_, err := uuid.Parse(token)
+	return err == nil
+}
+
+// verifyToken checks signature, purpose and expiry of token, reasons are not exposed to client
+func (s *SubscriptionService) verifyToken(token string, purpose tokens.Purpose) (*tokens.Claims, *commonErrors.AppError) {
+	claims, err := s.signer.Verify(token, purpose)
 	if err != nil {
-		return "", err
+		return nil, serviceErrors.ErrInvalidToken
 	}
-	return token.String(), nil
+	return claims, nil
+}
+
+// withinResendCooldown reports whether unexpired token was issued recently enough to be sent again,
+// issue time is derived from token expiry
+func (s *SubscriptionService) withinResendCooldown(existing *subscription.SubscriptionModel) bool {
+	if s.abuseGuard == nil || s.abuseGuard.ResendCooldown <= 0 {
+		return false
+	}
+	issuedAt := existing.TokenExpires.Add(-time.Duration(s.tokenLifeMinutes) * time.Minute)
+	now := time.Now()
+	return now.Before(existing.TokenExpires) && now.Sub(issuedAt) < s.abuseGuard.ResendCooldown
 }
@@ -0,0 +1,12 @@
+package errors
+
+import (
+	"net/http"
+
+	"weatherApi/internal/common/errors"
+)
+
+var (
+	ErrInvalidPayload      = errors.New(http.StatusBadRequest, "Unsupported email event payload", nil)
+	ErrInternalServerError = errors.New(http.StatusInternalServerError, "Internal server error", nil)
+)
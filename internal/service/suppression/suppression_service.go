@@ -0,0 +1,143 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/suppression"
+
+	appErrors "weatherApi/internal/common/errors"
+	serviceErrors "weatherApi/internal/service/suppression/errors"
+)
+
+// maxSourceLength matches email_suppressions.source column
+const maxSourceLength = 32
+
+type SubscriptionRepositoryInterface interface {
+	PauseByEmail(ctx context.Context, email string, reason constants.PauseReason, at time.Time) (int64, error)
+}
+
+// Service records bounce and complaint feedback, address is suppressed on the first complaint or after
+// hardBounceLimit hard bounces and all its subscriptions are paused. Soft bounces are only logged
+type Service struct {
+	log              *logger.Logger
+	suppressionRepo  suppression.SuppressionRepositoryInterface
+	subscriptionRepo SubscriptionRepositoryInterface
+	snsConfirmer     provider.SNSConfirmerInterface
+	hardBounceLimit  int
+	now              func() time.Time
+}
+
+func NewSuppressionService(
+	log *logger.Logger,
+	suppressionRepo suppression.SuppressionRepositoryInterface,
+	subscriptionRepo SubscriptionRepositoryInterface,
+	hardBounceLimit int,
+) *Service {
+	if hardBounceLimit < 1 {
+		hardBounceLimit = 1
+	}
+	return &Service{
+		log:              log,
+		suppressionRepo:  suppressionRepo,
+		subscriptionRepo: subscriptionRepo,
+		snsConfirmer:     provider.NewHTTPSNSConfirmer(nil),
+		hardBounceLimit:  hardBounceLimit,
+		now:              time.Now,
+	}
+}
+
+// WithSNSConfirmer replaces confirmer visiting SubscribeURL of SNS subscription confirmations
+func (s *Service) WithSNSConfirmer(confirmer provider.SNSConfirmerInterface) *Service {
+	s.snsConfirmer = confirmer
+	return s
+}
+
+// RecordWebhook records events from SendGrid or SES (SNS) webhook payload
+// SNS subscription confirmation is confirmed, so topic starts delivering notifications
+func (s *Service) RecordWebhook(ctx context.Context, body []byte) *appErrors.AppError {
+	if subscribeURL, ok := provider.SNSSubscribeURL(body); ok {
+		return s.confirmSNSSubscription(ctx, subscribeURL)
+	}
+	events, err := provider.ParseEmailEvents(body)
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to parse email events webhook")
+		return serviceErrors.ErrInvalidPayload
+	}
+	return s.Record(ctx, events)
+}
+
+func (s *Service) confirmSNSSubscription(ctx context.Context, subscribeURL string) *appErrors.AppError {
+	log := s.log.FromContext(ctx)
+	if err := s.snsConfirmer.Confirm(ctx, subscribeURL); err != nil {
+		log.Error().Err(err).Msgf("Failed to confirm SNS subscription, confirm it manually by visiting %s", subscribeURL)
+		if errors.Is(err, provider.ErrInvalidSubscribeURL) {
+			return serviceErrors.ErrInvalidPayload
+		}
+		return serviceErrors.ErrInternalServerError
+	}
+	log.Info().Msg("SNS subscription confirmed")
+	return nil
+}
+
+// RecordBounceEmail records failed recipients of delivery status notification email
+func (s *Service) RecordBounceEmail(ctx context.Context, message io.Reader) *appErrors.AppError {
+	events, err := provider.ParseDSN(message)
+	if err != nil {
+		s.log.FromContext(ctx).Error().Err(err).Msg("Failed to parse bounce email")
+		return serviceErrors.ErrInvalidPayload
+	}
+	return s.Record(ctx, events)
+}
+
+// Record stores delivery feedback events, events without recipient are skipped
+func (s *Service) Record(ctx context.Context, events []dto.EmailEvent) *appErrors.AppError {
+	log := s.log.FromContext(ctx)
+
+	for _, event := range events {
+		event.Email = strings.TrimSpace(event.Email)
+		if event.Email == "" {
+			continue
+		}
+		if event.Type == constants.EmailSoftBounce {
+			log.Info().Msgf("Soft bounce for %s from %s: %s", event.Email, event.Source, event.Details)
+			continue
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = s.now().UTC()
+		}
+		if len(event.Source) > maxSourceLength {
+			event.Source = event.Source[:maxSourceLength]
+		}
+
+		entry, err := s.suppressionRepo.RecordEvent(ctx, event, s.hardBounceLimit)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to record %s for %s", event.Type, event.Email)
+			return serviceErrors.ErrInternalServerError
+		}
+		if entry.SuppressedAt == nil {
+			log.Warn().Msgf("%s for %s (%d of %d hard bounces)", event.Type, event.Email, entry.HardBounces, s.hardBounceLimit)
+			continue
+		}
+
+		reason := constants.PauseBounced
+		if entry.Complaints > 0 {
+			reason = constants.PauseComplaint
+		}
+		paused, err := s.subscriptionRepo.PauseByEmail(ctx, event.Email, reason, *entry.SuppressedAt)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to pause subscriptions of %s", event.Email)
+			return serviceErrors.ErrInternalServerError
+		}
+		if paused > 0 {
+			log.Warn().Msgf("Address %s is suppressed after %s, paused %d subscriptions", event.Email, event.Type, paused)
+		}
+	}
+	return nil
+}
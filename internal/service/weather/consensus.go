@@ -0,0 +1,119 @@
+package weather
+
+import (
+	"math"
+	"slices"
+	"weatherApi/internal/dto"
+)
+
+// ConsensusProvider is Provider value of merged readings
+const ConsensusProvider = "consensus"
+
+// ConsensusOptions holds max allowed deviation of a provider reading from the median of all readings,
+// non-positive tolerance disables the check for the field.
+type ConsensusOptions struct {
+	TemperatureTolerance float64
+	HumidityTolerance    float64
+	PressureTolerance    float64
+	WindSpeedTolerance   float64
+}
+
+type consensusField struct {
+	name      string
+	tolerance float64
+	value     func(*dto.WeatherResponse) float64
+}
+
+func (o ConsensusOptions) fields() []consensusField {
+	return []consensusField{
+		{"temperature", o.TemperatureTolerance, func(w *dto.WeatherResponse) float64 { return w.Temperature }},
+		{"humidity", o.HumidityTolerance, func(w *dto.WeatherResponse) float64 { return float64(w.Humidity) }},
+		{"pressure", o.PressureTolerance, func(w *dto.WeatherResponse) float64 { return w.Pressure }},
+		{"wind_speed", o.WindSpeedTolerance, func(w *dto.WeatherResponse) float64 { return w.WindSpeed }},
+	}
+}
+
+// disagreement is a reading field deviating from the median more than allowed
+type disagreement struct {
+	provider string
+	field    string
+	value    float64
+	median   float64
+}
+
+type consensusResult struct {
+	weather       *dto.WeatherResponse
+	confidence    float64
+	disagreements []disagreement
+}
+
+// buildConsensus merges readings ordered by provider priority. Readings deviating from the median are outliers
+// and are excluded from the merge, when no reading agrees with the median the primary one is returned as is.
+// Confidence is the share of queried providers whose reading agrees with the consensus.
+func buildConsensus(readings []*dto.WeatherResponse, queried int, options ConsensusOptions) consensusResult {
+	fields := options.fields()
+
+	medians := make(map[string]float64, len(fields))
+	for _, field := range fields {
+		medians[field.name] = median(readings, field.value)
+	}
+
+	var (
+		agreeing      []*dto.WeatherResponse
+		disagreements []disagreement
+	)
+	for _, reading := range readings {
+		agrees := true
+		for _, field := range fields {
+			value := field.value(reading)
+			if field.tolerance > 0 && math.Abs(value-medians[field.name]) > field.tolerance {
+				agrees = false
+				disagreements = append(disagreements, disagreement{
+					provider: reading.Provider,
+					field:    field.name,
+					value:    value,
+					median:   medians[field.name],
+				})
+			}
+		}
+		if agrees {
+			agreeing = append(agreeing, reading)
+		}
+	}
+
+	base := agreeing
+	if len(base) == 0 {
+		base = readings[:1]
+	}
+
+	merged := *base[0]
+	merged.Temperature = median(base, func(w *dto.WeatherResponse) float64 { return w.Temperature })
+	merged.FeelsLike = median(base, func(w *dto.WeatherResponse) float64 { return w.FeelsLike })
+	merged.Humidity = int(math.Round(median(base, func(w *dto.WeatherResponse) float64 { return float64(w.Humidity) })))
+	merged.Pressure = median(base, func(w *dto.WeatherResponse) float64 { return w.Pressure })
+	merged.WindSpeed = median(base, func(w *dto.WeatherResponse) float64 { return w.WindSpeed })
+	merged.Provider = ConsensusProvider
+	merged.Sources = make([]string, len(base))
+	for i, reading := range base {
+		merged.Sources[i] = reading.Provider
+	}
+
+	confidence := math.Round(float64(len(agreeing))/float64(queried)*100) / 100
+	merged.Confidence = &confidence
+
+	return consensusResult{weather: &merged, confidence: confidence, disagreements: disagreements}
+}
+
+func median(readings []*dto.WeatherResponse, value func(*dto.WeatherResponse) float64) float64 {
+	values := make([]float64, len(readings))
+	for i, reading := range readings {
+		values[i] = value(reading)
+	}
+	slices.Sort(values)
+
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
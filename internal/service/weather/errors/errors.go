@@ -10,4 +10,9 @@ var (
 	ErrCityNotFound        = errors.New(http.StatusNotFound, "City not found", nil)
 	ErrInvalidRequest      = errors.New(http.StatusBadRequest, "Invalid Request", nil)
 	ErrInternalServerError = errors.New(http.StatusInternalServerError, "Internal server error", nil)
+	ErrLocationNotResolved = errors.New(http.StatusNotFound, "Unable to determine location", nil)
+	ErrGeoIPNotConfigured  = errors.New(http.StatusNotImplemented, "IP based location is not available", nil)
+	ErrInvalidHistoryRange = errors.New(http.StatusBadRequest, "Invalid history range", nil)
+	ErrProviderUnavailable = errors.New(http.StatusServiceUnavailable, "Weather provider is unavailable", nil)
+	ErrProviderBadResponse = errors.New(http.StatusBadGateway, "Weather provider returned invalid response", nil)
 )
@@ -0,0 +1,38 @@
+package weather
+
+import (
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+)
+
+const (
+	kelvinOffset     = 273.15
+	mpsToMph         = 2.236936
+	kmToMiles        = 0.621371
+	fahrenheitFactor = 9.0 / 5.0
+)
+
+// convertUnits returns copy of metric reading converted to requested units.
+// Pressure stays in hPa and wind direction in degrees for every unit system.
+func convertUnits(metric *dto.WeatherResponse, units constants.Units) *dto.WeatherResponse {
+	result := *metric
+	result.Units = units
+
+	switch units {
+	case constants.UnitsImperial:
+		result.Temperature = celsiusToFahrenheit(metric.Temperature)
+		result.FeelsLike = celsiusToFahrenheit(metric.FeelsLike)
+		result.WindSpeed = metric.WindSpeed * mpsToMph
+		result.Visibility = metric.Visibility * kmToMiles
+	case constants.UnitsStandard:
+		result.Temperature = metric.Temperature + kelvinOffset
+		result.FeelsLike = metric.FeelsLike + kelvinOffset
+	default:
+		result.Units = constants.UnitsMetric
+	}
+	return &result
+}
+
+func celsiusToFahrenheit(c float64) float64 {
+	return c*fahrenheitFactor + 32
+}
@@ -2,10 +2,17 @@ package weather
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
+	"weatherApi/internal/metrics"
+	"weatherApi/internal/repository/observation"
 	"weatherApi/internal/repository/weather"
+	"weatherApi/internal/service/cached"
 
 	appErrors "weatherApi/internal/common/errors"
 	serviceErrors "weatherApi/internal/service/weather/errors"
@@ -14,9 +21,14 @@ import (
 )
 
 type Service struct {
-	log       *logger.Logger
-	provider  provider.WeatherProviderInterface
-	cacheRepo weather.CacheRepoInterface
+	log             *logger.Logger
+	provider        provider.WeatherProviderInterface
+	providers       []provider.WeatherProviderInterface
+	cacheRepo       weather.CacheRepoInterface
+	observationRepo observation.ObservationRepositoryInterface
+
+	consensus        *ConsensusOptions
+	consensusMetrics *metrics.ConsensusMetrics
 }
 
 func NewWeatherService(log *logger.Logger, cacheRepo weather.CacheRepoInterface, providers ...provider.WeatherProviderInterface) *Service {
@@ -26,50 +38,150 @@ func NewWeatherService(log *logger.Logger, cacheRepo weather.CacheRepoInterface,
 	for i := 0; i < len(providers)-1; i++ {
 		providers[i].SetNext(providers[i+1])
 	}
-	return &Service{log: log, provider: providers[0], cacheRepo: cacheRepo}
+	return &Service{log: log, provider: providers[0], providers: providers, cacheRepo: cacheRepo}
+}
+
+// WithObservationRepo enables persisting of every provider reading for history API
+func (service *Service) WithObservationRepo(repo observation.ObservationRepositoryInterface) *Service {
+	service.observationRepo = repo
+	return service
 }
 
+// GetWeather returns weather for location converted to requested units,
+// empty options fields are replaced with defaults (metric, en)
 func (service *Service) GetWeather(
 	ctx context.Context,
-	city string,
+	location dto.Location,
+	options dto.WeatherOptions,
 ) (*dto.WeatherResponse, *appErrors.AppError) {
 	log := service.log.FromContext(ctx)
 
-	resp, err := service.cacheRepo.Get(ctx, city)
-	if err != nil && !errors.Is(err, weather.ErrCacheIsEmpty) {
-		log.Error().Err(err).Msg("Redis error, caching is skipped!")
-		return service.provider.GetWeather(ctx, city)
+	options = options.Normalize()
+	if err := location.Validate(); err != nil {
+		log.Error().Err(err).Msg("Invalid location")
+		return nil, serviceErrors.ErrInvalidRequest
 	}
-	if resp != nil {
-		return resp, nil
+	if !options.IsValid() {
+		log.Error().Msgf("Invalid weather options: %+v", options)
+		return nil, serviceErrors.ErrInvalidRequest
 	}
 
-	locked, err := service.cacheRepo.AcquireLock(ctx, city)
+	result, err := service.getMetricWeather(ctx, location, options.Lang)
 	if err != nil {
-		log.Error().Err(err).Msg("Redis failed to acquire lock")
+		return nil, err
+	}
+	return convertUnits(result, options.Units), nil
+}
+
+// getMetricWeather returns weather in metric units, cache stores only metric readings
+// so the key depends on location and language only.
+func (service *Service) getMetricWeather(
+	ctx context.Context,
+	location dto.Location,
+	lang string,
+) (*dto.WeatherResponse, *appErrors.AppError) {
+	return cached.Fetch(ctx, service.log.FromContext(ctx), service.cacheRepo, cacheKey(location, lang),
+		func(ctx context.Context) (*dto.WeatherResponse, *appErrors.AppError) {
+			return service.fetch(ctx, location, lang)
+		})
+}
+
+// WithConsensus switches service to query all providers at once and merge their readings,
+// metrics are optional
+func (service *Service) WithConsensus(options ConsensusOptions, consensusMetrics *metrics.ConsensusMetrics) *Service {
+	service.consensus = &options
+	service.consensusMetrics = consensusMetrics
+	return service
+}
+
+// fetch requests provider chain and records the reading, failing to record doesn't fail the request
+func (service *Service) fetch(
+	ctx context.Context,
+	location dto.Location,
+	lang string,
+) (*dto.WeatherResponse, *appErrors.AppError) {
+	if service.consensus != nil {
+		return service.fetchConsensus(ctx, location, lang)
+	}
+	result, appErr := service.provider.GetWeather(ctx, location, lang)
+	if appErr != nil {
+		return nil, appErr
 	}
-	if !locked {
-		response, err := service.cacheRepo.WaitForUnlock(ctx, city)
-		if err != nil {
-			return nil, serviceErrors.ErrInternalServerError
+	service.recordObservation(ctx, location, result)
+	return result, nil
+}
+
+func (service *Service) fetchConsensus(
+	ctx context.Context,
+	location dto.Location,
+	lang string,
+) (*dto.WeatherResponse, *appErrors.AppError) {
+	log := service.log.FromContext(ctx)
+
+	readings := make([]*dto.WeatherResponse, len(service.providers))
+	errs := make([]*appErrors.AppError, len(service.providers))
+	var wg sync.WaitGroup
+	for i, p := range service.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readings[i], errs[i] = p.Fetch(ctx, location, lang)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		succeeded []*dto.WeatherResponse
+		notFound  bool
+		lastErr   *appErrors.AppError
+	)
+	for i, p := range service.providers {
+		if errs[i] != nil {
+			log.Error().Err(errs[i]).Msgf("%s: Provider failed in consensus mode", p.Name())
+			notFound = notFound || errs[i].Code == http.StatusNotFound
+			lastErr = errs[i]
+			continue
+		}
+		reading := *readings[i]
+		if reading.Provider == "" {
+			reading.Provider = p.Name()
 		}
-		if response != nil {
-			return response, nil
+		service.recordObservation(ctx, location, &reading)
+		succeeded = append(succeeded, &reading)
+	}
+	if len(succeeded) == 0 {
+		if notFound {
+			return nil, serviceErrors.ErrCityNotFound
 		}
-	} else {
-		defer func(cacheRepo weather.CacheRepoInterface, ctx context.Context, city string) {
-			err := cacheRepo.ReleaseLock(ctx, city)
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to release lock")
-			}
-		}(service.cacheRepo, ctx, city)
+		return nil, provider.ClientError(lastErr)
 	}
 
-	result, appErr := service.provider.GetWeather(ctx, city)
-	if appErr != nil {
-		return nil, appErr
+	result := buildConsensus(succeeded, len(service.providers), *service.consensus)
+	for _, d := range result.disagreements {
+		log.Warn().Msgf("%s disagrees on %s for %s: %.2f, median %.2f", d.provider, d.field, location, d.value, d.median)
+		if service.consensusMetrics != nil {
+			service.consensusMetrics.IncDisagreement(d.provider, d.field)
+		}
+	}
+	if service.consensusMetrics != nil {
+		service.consensusMetrics.ObserveConfidence(result.confidence)
 	}
+	return result.weather, nil
+}
 
-	_ = service.cacheRepo.Set(ctx, city, result)
-	return result, nil
+func (service *Service) recordObservation(ctx context.Context, location dto.Location, result *dto.WeatherResponse) {
+	if service.observationRepo == nil {
+		return
+	}
+	model := observation.NewObservationModel(location.StorageKey(), time.Now().UTC(), result)
+	if err := service.observationRepo.CreateOne(ctx, model); err != nil {
+		service.log.FromContext(ctx).Error().Err(err).Msgf("Failed to store observation for %s", location)
+	}
+}
+
+func cacheKey(location dto.Location, lang string) string {
+	if lang == constants.DefaultLang {
+		return location.Key()
+	}
+	return fmt.Sprintf("%s:lang:%s", location.Key(), lang)
 }
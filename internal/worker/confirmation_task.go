@@ -14,6 +14,7 @@ func StartConfirmationWorker(
 	ctx context.Context,
 	subscriber broker.EventSubscriber,
 	smtpClient provider.SMTPClientInterface,
+	suppressions SuppressionCheckerInterface,
 ) error {
 	err := subscriber.Subscribe(ctx, broker.SubscriptionConfirmationTasks, func(ctx context.Context, data []byte) error {
 		log := log.FromContext(ctx)
@@ -22,6 +23,14 @@ func StartConfirmationWorker(
 			log.Error().Err(err).Msg("Failed to decode task")
 			return err
 		}
+		if isSuppressed(ctx, log, suppressions, task.Email) {
+			log.Warn().Msgf("Skipping confirmation letter to suppressed address %s", task.Email)
+			return nil
+		}
+		if task.EmailChange {
+			log.Info().Msgf("Sending email change confirmation letter to %s for city %s", task.Email, task.City)
+			return smtpClient.SendEmailChangeConfirmation(task.Email, task.Token, task.City)
+		}
 		log.Info().Msgf("Sending subscription confirmation letter to %s for city %s", task.Email, task.City)
 		return smtpClient.SendConfirmationToken(task.Email, task.Token, task.City)
 	})
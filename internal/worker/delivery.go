@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/delivery"
+
+	"github.com/rs/zerolog"
+)
+
+// DeliveryRecorderInterface keeps log of sent weather emails for data export, nil recorder keeps nothing
+type DeliveryRecorderInterface interface {
+	Record(ctx context.Context, delivery *delivery.DeliveryModel) error
+}
+
+// recordDelivery logs failures only, email is already sent and must not be sent again on retry
+func recordDelivery(
+	ctx context.Context,
+	log *zerolog.Logger,
+	deliveries DeliveryRecorderInterface,
+	email string,
+	kind constants.DeliveryKind,
+	cities []string,
+) {
+	if deliveries == nil {
+		return
+	}
+	if err := deliveries.Record(ctx, delivery.NewDelivery(email, kind, cities, time.Now())); err != nil {
+		log.Error().Err(err).Msgf("Failed to record %s delivery to %s", kind, email)
+	}
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+)
+
+func StartDigestWorker(
+	log *logger.Logger,
+	ctx context.Context,
+	subscriber broker.EventSubscriber,
+	smtpClient provider.SMTPClientInterface,
+	suppressions SuppressionCheckerInterface,
+	deliveries DeliveryRecorderInterface,
+) error {
+	err := subscriber.Subscribe(ctx, broker.SendDigestWeatherData, func(ctx context.Context, data []byte) error {
+		log := log.FromContext(ctx)
+
+		var task dto.WeatherDigestTask
+		if err := json.Unmarshal(data, &task); err != nil {
+			log.Error().Err(err).Msg("Failed to decode task")
+			return err
+		}
+		if isSuppressed(ctx, log, suppressions, task.Email) {
+			log.Warn().Msgf("Skipping weather digest to suppressed address %s", task.Email)
+			return nil
+		}
+
+		log.Info().Msgf("Sending weather digest of %d cities to user %s", len(task.Sections), task.Email)
+		if err := smtpClient.SendDigestWeatherData(&task); err != nil {
+			log.Error().Err(err).Msgf("Failed to send weather digest to %s", task.Email)
+			return nil
+		}
+		cities := make([]string, len(task.Sections))
+		for i, section := range task.Sections {
+			cities[i] = section.City
+		}
+		recordDelivery(ctx, log, deliveries, task.Email, constants.DeliveryDigest, cities)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+)
+
+func StartPrivacyWorker(
+	log *logger.Logger,
+	ctx context.Context,
+	subscriber broker.EventSubscriber,
+	smtpClient provider.SMTPClientInterface,
+) error {
+	err := subscriber.Subscribe(ctx, broker.PrivacyLinkTasks, func(ctx context.Context, data []byte) error {
+		log := log.FromContext(ctx)
+		var task dto.PrivacyLinkTask
+		if err := json.Unmarshal(data, &task); err != nil {
+			log.Error().Err(err).Msg("Failed to decode task")
+			return err
+		}
+		log.Info().Msgf("Sending privacy %s link to %s", task.Action, task.Email)
+		return smtpClient.SendPrivacyLink(task.Email, task.Token, task.Action)
+	})
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"sync"
 	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/provider"
@@ -17,6 +18,8 @@ func StartSubscriptionWorker(
 	ctx context.Context,
 	subscriber broker.EventSubscriber,
 	smtpClient provider.SMTPClientInterface,
+	suppressions SuppressionCheckerInterface,
+	deliveries DeliveryRecorderInterface,
 ) error {
 	err := subscriber.Subscribe(ctx, broker.SendSubscriptionWeatherData, func(ctx context.Context, data []byte) error {
 		log := log.FromContext(ctx)
@@ -37,10 +40,20 @@ func StartSubscriptionWorker(
 			go func() {
 				defer wg.Done()
 				defer func() { <-semaphore }()
+				if isSuppressed(ctx, log, suppressions, user.Email) {
+					log.Warn().Msgf("Skipping weather message to suppressed address %s", user.Email)
+					return
+				}
 				log.Info().Msgf("Sending weather message to user %s", user.Email)
-				if err := smtpClient.SendSubscriptionWeatherData(&task.Weather, &user); err != nil {
+				var airQuality *dto.AirQualityResponse
+				if user.IncludeAirQuality || user.IsAirQualityAlert(task.AirQuality) {
+					airQuality = task.AirQuality
+				}
+				if err := smtpClient.SendSubscriptionWeatherData(&task.Weather, airQuality, &user); err != nil {
 					log.Error().Err(err).Msgf("Failed to send weather email to %s", user.Email)
+					return
 				}
+				recordDelivery(ctx, log, deliveries, user.Email, constants.DeliveryWeather, []string{task.City})
 			}()
 		}
 
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// SuppressionCheckerInterface reports addresses which hard-bounced or complained, nil checker suppresses nothing
+type SuppressionCheckerInterface interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+// isSuppressed fails open, failing lookup must not stop delivery to valid addresses
+func isSuppressed(ctx context.Context, log *zerolog.Logger, suppressions SuppressionCheckerInterface, email string) bool {
+	if suppressions == nil {
+		return false
+	}
+	suppressed, err := suppressions.IsSuppressed(ctx, email)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to check suppression list for %s", email)
+		return false
+	}
+	return suppressed
+}
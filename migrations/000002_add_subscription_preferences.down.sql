@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    DROP COLUMN units,
+    DROP COLUMN lang;
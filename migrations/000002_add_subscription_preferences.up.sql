@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN units VARCHAR(10) NOT NULL DEFAULT 'metric',
+    ADD COLUMN lang VARCHAR(8) NOT NULL DEFAULT 'en';
@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    DROP COLUMN include_air_quality,
+    DROP COLUMN aqi_alert_threshold;
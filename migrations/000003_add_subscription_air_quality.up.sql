@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN include_air_quality BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN aqi_alert_threshold INTEGER;
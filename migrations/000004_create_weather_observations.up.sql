@@ -0,0 +1,18 @@
+CREATE TABLE weather_observations (
+    id BIGSERIAL PRIMARY KEY,
+    location VARCHAR(64) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    observed_at TIMESTAMPTZ NOT NULL,
+
+    temperature DOUBLE PRECISION NOT NULL,
+    feels_like DOUBLE PRECISION NOT NULL,
+    humidity INTEGER NOT NULL,
+    pressure DOUBLE PRECISION NOT NULL,
+    wind_speed DOUBLE PRECISION NOT NULL,
+    wind_direction INTEGER NOT NULL,
+    visibility DOUBLE PRECISION NOT NULL,
+    uv_index DOUBLE PRECISION,
+    condition VARCHAR(16)
+);
+
+CREATE INDEX idx_weather_observations_location_time ON weather_observations (location, observed_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS admin_audit_log;
+
+ALTER TABLE subscriptions
+    DROP COLUMN last_sent_at;
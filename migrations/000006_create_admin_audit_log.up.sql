@@ -0,0 +1,16 @@
+ALTER TABLE subscriptions
+    ADD COLUMN last_sent_at TIMESTAMP;
+
+CREATE TABLE admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    actor VARCHAR(64) NOT NULL,
+    remote_ip VARCHAR(64),
+    action VARCHAR(32) NOT NULL,
+    user_id INTEGER,
+    subscription_id INTEGER,
+    details TEXT
+);
+
+CREATE INDEX idx_admin_audit_log_created_at ON admin_audit_log (created_at);
+CREATE INDEX idx_admin_audit_log_user_id ON admin_audit_log (user_id);
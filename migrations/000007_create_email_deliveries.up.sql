@@ -0,0 +1,9 @@
+CREATE TABLE email_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    cities TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_email_deliveries_email ON email_deliveries (email);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_subscriptions_unconfirmed_token_expires;
+
+ALTER TABLE subscriptions
+    DROP COLUMN reminder_sent_at;
@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions
+    ADD COLUMN reminder_sent_at TIMESTAMP;
+
+CREATE INDEX idx_subscriptions_unconfirmed_token_expires ON subscriptions (token_expires) WHERE is_confirmed = FALSE;
@@ -0,0 +1 @@
+COMMENT ON COLUMN subscriptions.confirm_token IS NULL;
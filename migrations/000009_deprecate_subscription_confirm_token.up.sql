@@ -0,0 +1,2 @@
+-- links emailed with the legacy token keep working until they expire, column is dropped in a later release
+COMMENT ON COLUMN subscriptions.confirm_token IS 'Legacy link token, only read';
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    DROP COLUMN pause_reason,
+    DROP COLUMN paused_at;
+
+DROP TABLE IF EXISTS email_suppressions;
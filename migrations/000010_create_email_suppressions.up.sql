@@ -0,0 +1,18 @@
+CREATE TABLE email_suppressions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    email VARCHAR(255) NOT NULL UNIQUE,
+    hard_bounces INTEGER NOT NULL DEFAULT 0,
+    complaints INTEGER NOT NULL DEFAULT 0,
+    last_event VARCHAR(16) NOT NULL,
+    last_event_at TIMESTAMP NOT NULL,
+    source VARCHAR(32) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    suppressed_at TIMESTAMP
+);
+
+ALTER TABLE subscriptions
+    ADD COLUMN paused_at TIMESTAMP,
+    ADD COLUMN pause_reason VARCHAR(32);
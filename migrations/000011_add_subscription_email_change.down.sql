@@ -0,0 +1,6 @@
+ALTER TABLE subscriptions
+    DROP COLUMN token_version,
+    DROP COLUMN email_change_expires,
+    DROP COLUMN pending_email;
+ALTER TABLE users
+    ALTER COLUMN email TYPE VARCHAR(32);
@@ -0,0 +1,7 @@
+-- pending address moves to users.email once confirmed, so both hold any valid address
+ALTER TABLE users
+    ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE subscriptions
+    ADD COLUMN pending_email VARCHAR(255),
+    ADD COLUMN email_change_expires TIMESTAMP,
+    ADD COLUMN token_version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE subscriptions
+    ADD COLUMN resume_at TIMESTAMP;
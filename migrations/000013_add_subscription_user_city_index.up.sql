@@ -0,0 +1,16 @@
+-- duplicates of a city left from before the index are unsubscribed, the confirmed and then the newest one is kept
+UPDATE subscriptions
+SET deleted_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY user_id, LOWER(TRIM(city))
+            ORDER BY is_confirmed DESC, id DESC
+        ) AS position
+        FROM subscriptions
+        WHERE deleted_at IS NULL
+    ) ranked
+    WHERE position > 1
+);
+CREATE UNIQUE INDEX idx_subscriptions_user_city ON subscriptions (user_id, (LOWER(TRIM(city)))) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job VARCHAR(32) NOT NULL,
+    slot TIMESTAMPTZ NOT NULL,
+    catch_up BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(16) NOT NULL,
+    instance VARCHAR(128) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    cities INTEGER NOT NULL DEFAULT 0,
+    recipients INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    UNIQUE (job, slot)
+);
+
+CREATE INDEX idx_job_runs_started_at ON job_runs (started_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_subscriptions_due_user;
+DROP INDEX IF EXISTS idx_subscriptions_due_city;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_subscriptions_due_city ON subscriptions (frequency, (LOWER(TRIM(city))), id) WHERE is_confirmed = TRUE;
+CREATE INDEX idx_subscriptions_due_user ON subscriptions (frequency, user_id, id) WHERE is_confirmed = TRUE;
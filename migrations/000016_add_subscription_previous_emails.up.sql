@@ -0,0 +1,2 @@
+ALTER TABLE subscriptions
+    ADD COLUMN previous_emails TEXT NOT NULL DEFAULT '';
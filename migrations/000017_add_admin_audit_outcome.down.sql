@@ -0,0 +1,3 @@
+ALTER TABLE admin_audit_log
+    DROP COLUMN error,
+    DROP COLUMN outcome;
@@ -0,0 +1,3 @@
+ALTER TABLE admin_audit_log
+    ADD COLUMN outcome VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE job_runs
+    DROP COLUMN heartbeat_at;
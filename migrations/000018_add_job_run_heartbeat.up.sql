@@ -0,0 +1,2 @@
+ALTER TABLE job_runs
+    ADD COLUMN heartbeat_at TIMESTAMPTZ;
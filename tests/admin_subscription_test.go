@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/audit"
+	"weatherApi/internal/repository/base"
+	"weatherApi/internal/repository/jobrun"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/admin"
+	subscriptionService "weatherApi/internal/service/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockDispatcher struct {
+	dispatched []subscription.SubscriptionModel
+	err        error
+}
+
+func (m *mockDispatcher) Dispatch(_ context.Context, subs []subscription.SubscriptionModel) error {
+	m.dispatched = append(m.dispatched, subs...)
+	return m.err
+}
+
+type adminFixture struct {
+	router     *gin.Engine
+	auditRepo  *audit.MockAuditLogRepository
+	jobRuns    *jobrun.MockJobRunRepository
+	publisher  *broker.MockRabbitMQPublisher
+	dispatcher *mockDispatcher
+	subs       map[uint]*subscription.SubscriptionModel
+}
+
+func newAdminFixture() *adminFixture {
+	owner := user.UserModel{Model: gorm.Model{ID: 7}, Email: "jane@example.com"}
+	f := &adminFixture{
+		auditRepo:  &audit.MockAuditLogRepository{},
+		jobRuns:    &jobrun.MockJobRunRepository{},
+		publisher:  broker.NewMockRabbitMQPublisher(),
+		dispatcher: &mockDispatcher{},
+		subs: map[uint]*subscription.SubscriptionModel{
+			1: {Model: gorm.Model{ID: 1}, City: "Kyiv", UserID: 7, User: owner, TokenExpires: time.Now().Add(-time.Hour)},
+			2: {Model: gorm.Model{ID: 2}, City: "Lviv", UserID: 7, User: owner, IsConfirmed: true, TokenExpires: time.Now()},
+		},
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindAllByUserIDsFn: func(_ []uint) ([]subscription.SubscriptionModel, error) {
+			return []subscription.SubscriptionModel{*f.subs[2], *f.subs[1]}, nil
+		},
+		FindOneWithUserFn: func(id uint) (*subscription.SubscriptionModel, error) {
+			if sub, ok := f.subs[id]; ok {
+				found := *sub
+				return &found, nil
+			}
+			return nil, base.ErrNotFound
+		},
+		UpdateFn: func(entity *subscription.SubscriptionModel) error {
+			f.subs[entity.ID] = entity
+			return nil
+		},
+		DeleteFn: func(entity *subscription.SubscriptionModel) error {
+			delete(f.subs, entity.ID)
+			return nil
+		},
+	}
+	userRepo := &user.MockUserRepository{
+		SearchByEmailFn: func(_ string, _ int) ([]user.UserModel, error) {
+			return []user.UserModel{owner}, nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	subscriptions := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, f.publisher, 60, newTestSigner())
+	handler := routes.NewSubscriptionAdminHandler(log,
+		admin.NewAdminService(log, userRepo, subRepo, f.auditRepo, subscriptions, f.dispatcher).WithJobRuns(f.jobRuns))
+
+	gin.SetMode(gin.TestMode)
+	f.router = gin.New()
+	f.router.GET("/admin/users", handler.SearchUsers)
+	f.router.POST("/admin/subscriptions/:id/confirm", handler.ForceConfirm)
+	f.router.DELETE("/admin/subscriptions/:id", handler.ForceUnsubscribe)
+	f.router.POST("/admin/subscriptions/:id/resend-confirmation", handler.ResendConfirmation)
+	f.router.POST("/admin/subscriptions/:id/send", handler.SendNow)
+	f.router.GET("/admin/audit-log", handler.AuditLog)
+	f.router.GET("/admin/jobs/runs", handler.JobRuns)
+	return f
+}
+
+func (f *adminFixture) do(method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(routes.AdminActorHeader, "oncall")
+	resp := httptest.NewRecorder()
+	f.router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestAdmin_SearchUsersReturnsSubscriptionState(t *testing.T) {
+	f := newAdminFixture()
+
+	resp := f.do(http.MethodGet, "/admin/users?email=jane")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Users []dto.AdminUser `json:"users"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Users, 1)
+	require.Len(t, body.Users[0].Subscriptions, 2)
+	assert.Equal(t, constants.SubscriptionActive, body.Users[0].Subscriptions[0].Status)
+	assert.Equal(t, constants.SubscriptionExpired, body.Users[0].Subscriptions[1].Status)
+
+	require.Len(t, f.auditRepo.Entries, 1)
+	assert.Equal(t, admin.ActionSearchUsers, f.auditRepo.Entries[0].Action)
+	assert.Equal(t, "oncall", f.auditRepo.Entries[0].Actor)
+	assert.Equal(t, `query="j***"`, f.auditRepo.Entries[0].Details)
+}
+
+func TestAdmin_SearchAuditRedactsEmail(t *testing.T) {
+	f := newAdminFixture()
+
+	require.Equal(t, http.StatusOK, f.do(http.MethodGet, "/admin/users?email=jane.doe@example.com").Code)
+
+	require.Len(t, f.auditRepo.Entries, 1)
+	assert.Nil(t, f.auditRepo.Entries[0].UserID)
+	assert.Equal(t, `query="j***@example.com"`, f.auditRepo.Entries[0].Details)
+	assert.NotContains(t, f.auditRepo.Entries[0].Details, "jane.doe")
+}
+
+func TestAdmin_SearchRequiresQuery(t *testing.T) {
+	f := newAdminFixture()
+
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/admin/users?email=j").Code)
+	assert.Empty(t, f.auditRepo.Entries)
+}
+
+func TestAdmin_ForceConfirmAndUnsubscribe(t *testing.T) {
+	f := newAdminFixture()
+
+	resp := f.do(http.MethodPost, "/admin/subscriptions/1/confirm")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, f.subs[1].IsConfirmed)
+	assert.NotNil(t, f.subs[1].ConfirmedAt)
+	assert.Equal(t, http.StatusConflict, f.do(http.MethodPost, "/admin/subscriptions/1/confirm").Code)
+
+	assert.Equal(t, http.StatusNoContent, f.do(http.MethodDelete, "/admin/subscriptions/1").Code)
+	assert.NotContains(t, f.subs, uint(1))
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodDelete, "/admin/subscriptions/1").Code)
+
+	require.Len(t, f.auditRepo.Entries, 3)
+	assert.Equal(t, admin.ActionForceUnsubscribe, f.auditRepo.Entries[2].Action)
+	assert.Equal(t, uint(1), *f.auditRepo.Entries[2].SubscriptionID)
+}
+
+func TestAdmin_ResendConfirmationRefreshesExpiredToken(t *testing.T) {
+	f := newAdminFixture()
+
+	resp := f.do(http.MethodPost, "/admin/subscriptions/1/resend-confirmation")
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	require.Len(t, f.publisher.Calls, 1)
+	assert.Equal(t, broker.SubscriptionConfirmationTasks, f.publisher.Calls[0].Topic)
+
+	var task dto.ConfirmationEmailTask
+	require.NoError(t, json.Unmarshal(f.publisher.Calls[0].Payload, &task))
+	assert.Equal(t, "jane@example.com", task.Email)
+	assert.NotEqual(t, "old", task.Token)
+	assert.True(t, f.subs[1].TokenExpires.After(time.Now()))
+
+	assert.Equal(t, http.StatusConflict, f.do(http.MethodPost, "/admin/subscriptions/2/resend-confirmation").Code)
+}
+
+func TestAdmin_SendNow(t *testing.T) {
+	f := newAdminFixture()
+
+	assert.Equal(t, http.StatusConflict, f.do(http.MethodPost, "/admin/subscriptions/1/send").Code)
+	assert.Equal(t, http.StatusAccepted, f.do(http.MethodPost, "/admin/subscriptions/2/send").Code)
+	require.Len(t, f.dispatcher.dispatched, 1)
+	assert.Equal(t, uint(2), f.dispatcher.dispatched[0].ID)
+
+	f.dispatcher.err = errors.New("broker is down")
+	assert.Equal(t, http.StatusBadGateway, f.do(http.MethodPost, "/admin/subscriptions/2/send").Code)
+
+	require.Len(t, f.auditRepo.Entries, 3, "refused and failed actions are audited too")
+	outcomes := make([]constants.AuditOutcome, len(f.auditRepo.Entries))
+	for i, entry := range f.auditRepo.Entries {
+		outcomes[i] = entry.Outcome
+	}
+	assert.Equal(t, []constants.AuditOutcome{constants.AuditOutcomeError, constants.AuditOutcomeSuccess, constants.AuditOutcomeError}, outcomes)
+	assert.NotEmpty(t, f.auditRepo.Entries[2].Error)
+	assert.Empty(t, f.auditRepo.Entries[1].Error)
+}
+
+func TestAdmin_ActionIsPerformedWhenAuditFails(t *testing.T) {
+	f := newAdminFixture()
+	f.auditRepo.Err = errors.New("db is down")
+
+	assert.Equal(t, http.StatusNoContent, f.do(http.MethodDelete, "/admin/subscriptions/2").Code)
+	assert.NotContains(t, f.subs, uint(2))
+}
+
+func TestAdmin_AuditLogNewestFirst(t *testing.T) {
+	f := newAdminFixture()
+	f.do(http.MethodGet, "/admin/users?email=jane")
+	f.do(http.MethodPost, "/admin/subscriptions/2/send")
+
+	resp := f.do(http.MethodGet, "/admin/audit-log?limit=1")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var body struct {
+		Entries []dto.AuditLogEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Entries, 1)
+	assert.Equal(t, admin.ActionSendNow, body.Entries[0].Action)
+
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/admin/audit-log?limit=0").Code)
+}
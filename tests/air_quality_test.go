@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	aqCacheRepo "weatherApi/internal/repository/airquality"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/airquality"
+
+	serviceErrors "weatherApi/internal/service/weather/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAirQualityRouter(svc *airquality.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/air-quality", routes.NewAirQualityHandler(logger.NewNoOpLogger(), svc, nil).GetAirQuality)
+	return router
+}
+
+func TestAirQualityHandler_WeatherApiProvider(t *testing.T) {
+	var mockResp dto.WeatherAPIAirQualityResponse
+	mockResp.Current.AirQuality.PM25 = 20
+	mockResp.Current.AirQuality.PM10 = 30
+	mockResp.Current.AirQuality.O3 = 60.5
+	mockResp.Current.AirQuality.NO2 = 12.3
+
+	var requestedAQI string
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedAQI = r.URL.Query().Get("aqi")
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(mockResp); err != nil {
+			t.Fatalf("failed to encode mock response: %v", err)
+		}
+	}))
+	defer mockAPI.Close()
+	log := logger.NewNoOpLogger()
+
+	svc := airquality.NewAirQualityService(
+		log,
+		aqCacheRepo.NewMockCacheRepo(),
+		provider.NewWeatherApiAirQualityProvider(log, "test", mockAPI.URL),
+	)
+	router := setupAirQualityRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/air-quality?city=Kyiv", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "yes", requestedAQI)
+
+	var data dto.AirQualityResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+	assert.Equal(t, 71, data.AQI)
+	assert.Equal(t, constants.AirQualityModerate, data.Category)
+	assert.Equal(t, 20.0, data.PM25)
+	assert.Equal(t, 30.0, data.PM10)
+	assert.Equal(t, 60.5, data.O3)
+	assert.Equal(t, 12.3, data.NO2)
+}
+
+func TestAirQualityHandler_FallbackAndCache(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	failing := &provider.MockAirQualityProvider{Err: serviceErrors.ErrInternalServerError}
+	fallback := &provider.MockAirQualityProvider{Response: &dto.AirQualityResponse{AQI: 160, Category: constants.AirQualityUnhealthy}}
+
+	svc := airquality.NewAirQualityService(log, aqCacheRepo.NewMockCacheRepo(), failing, fallback)
+	router := setupAirQualityRouter(svc)
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/air-quality?lat=50.45&lon=30.52", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var data dto.AirQualityResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+		assert.Equal(t, 160, data.AQI)
+	}
+	assert.Equal(t, 1, failing.GetAirQualityCallCount)
+	assert.Equal(t, 1, fallback.GetAirQualityCallCount)
+}
+
+func TestAirQualityHandler_MissingCity(t *testing.T) {
+	svc := airquality.NewAirQualityService(
+		logger.NewNoOpLogger(),
+		aqCacheRepo.NewMockCacheRepo(),
+		&provider.MockAirQualityProvider{},
+	)
+	router := setupAirQualityRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/air-quality", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
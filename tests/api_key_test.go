@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/middleware"
+	apiKeyRepo "weatherApi/internal/repository/apikey"
+	rateLimitRepo "weatherApi/internal/repository/ratelimit"
+	"weatherApi/internal/server"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/apikey"
+	"weatherApi/internal/service/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRouter(apiKeyService *apikey.Service, limiter *ratelimit.Service, required bool, ipPerMinute int) *gin.Engine {
+	return newProxiedRateLimitedRouter(apiKeyService, limiter, required, ipPerMinute, "")
+}
+
+func newProxiedRateLimitedRouter(
+	apiKeyService *apikey.Service,
+	limiter *ratelimit.Service,
+	required bool,
+	ipPerMinute int,
+	trustedProxies string,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router, err := server.NewEngine(trustedProxies)
+	if err != nil {
+		panic(err)
+	}
+	limited := router.Group("",
+		middleware.APIKeyMiddleware(apiKeyService, required, limiter, ipPerMinute),
+		middleware.RateLimitMiddleware(limiter, ipPerMinute),
+	)
+	limited.GET("/weather", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func getWeather(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+	if key != "" {
+		req.Header.Set(middleware.APIKeyHeader, key)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestAPIKey_IssueAuthenticateRevoke(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+
+	created, err := svc.Issue(context.Background(), &dto.CreateAPIKeyRequest{Name: "partner"})
+	require.Nil(t, err)
+	assert.Equal(t, 100, created.RateLimitPerMinute)
+	assert.True(t, len(created.Key) > len(created.Prefix))
+
+	authenticated, err := svc.Authenticate(context.Background(), created.Key)
+	require.Nil(t, err)
+	assert.Equal(t, created.ID, authenticated.ID)
+
+	_, err = svc.Authenticate(context.Background(), created.Key+"x")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.Code)
+
+	require.Nil(t, svc.Revoke(context.Background(), created.ID))
+	_, err = svc.Authenticate(context.Background(), created.Key)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.Code)
+}
+
+func TestAPIKey_RequiredKeyIsEnforced(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+	created, err := svc.Issue(context.Background(), &dto.CreateAPIKeyRequest{Name: "partner"})
+	require.Nil(t, err)
+	router := newRateLimitedRouter(svc, ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo()), true, 10)
+
+	assert.Equal(t, http.StatusUnauthorized, getWeather(router, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, getWeather(router, "wk_unknown").Code)
+	assert.Equal(t, http.StatusOK, getWeather(router, created.Key).Code)
+}
+
+func TestRateLimit_AnonymousLimitedPerIP(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+	router := newRateLimitedRouter(svc, ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo()), false, 2)
+
+	first := getWeather(router, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+
+	require.Equal(t, http.StatusOK, getWeather(router, "").Code)
+
+	limited := getWeather(router, "")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "0", limited.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_ForgedForwardedForDoesNotResetBucket(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+	router := newRateLimitedRouter(svc, ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo()), false, 1)
+
+	for i, forwarded := range []string{"203.0.113.1", "203.0.113.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+		req.Header.Set("X-Forwarded-For", forwarded)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if i == 0 {
+			require.Equal(t, http.StatusOK, resp.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, resp.Code, "untrusted peer can't pick its own IP")
+		}
+	}
+}
+
+func TestRateLimit_TrustedProxyForwardsClientIP(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+	limiter := ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo())
+	// httptest requests come from 192.0.2.1
+	router := newProxiedRateLimitedRouter(svc, limiter, false, 1, "192.0.2.0/24")
+
+	for _, forwarded := range []string{"203.0.113.1", "203.0.113.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+		req.Header.Set("X-Forwarded-For", forwarded)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code, "clients behind proxy have own buckets")
+	}
+}
+
+func TestRateLimit_KeyHasOwnBucket(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	svc := apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100)
+	created, err := svc.Issue(context.Background(), &dto.CreateAPIKeyRequest{Name: "partner", RateLimitPerMinute: 5})
+	require.Nil(t, err)
+	router := newRateLimitedRouter(svc, ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo()), false, 1)
+
+	require.Equal(t, http.StatusOK, getWeather(router, "").Code)
+	require.Equal(t, http.StatusTooManyRequests, getWeather(router, "").Code)
+
+	resp := getWeather(router, created.Key)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "5", resp.Header().Get("RateLimit-Limit"))
+}
+
+// countingAPIKeyRepo counts key lookups reaching the storage
+type countingAPIKeyRepo struct {
+	apiKeyRepo.MockAPIKeyRepository
+	lookups int
+}
+
+func (r *countingAPIKeyRepo) FindOneOrNone(ctx context.Context, query any, args ...any) (*apiKeyRepo.APIKeyModel, error) {
+	r.lookups++
+	return r.MockAPIKeyRepository.FindOneOrNone(ctx, query, args...)
+}
+
+func TestRateLimit_BadKeyFloodIsLimited(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	repo := &countingAPIKeyRepo{}
+	svc := apikey.NewAPIKeyService(log, repo, 100)
+	router := newRateLimitedRouter(svc, ratelimit.NewRateLimitService(log, rateLimitRepo.NewMockRateLimitRepo()), false, 2)
+
+	assert.Equal(t, http.StatusUnauthorized, getWeather(router, "wk_guess1").Code)
+	assert.Equal(t, http.StatusUnauthorized, getWeather(router, "wk_guess2").Code)
+	for i := 0; i < 5; i++ {
+		limited := getWeather(router, "wk_guess")
+		assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+		assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+	}
+	assert.Equal(t, 2, repo.lookups, "limited requests do not reach key storage")
+	assert.Equal(t, http.StatusOK, getWeather(router, "").Code, "anonymous bucket is separate")
+}
+
+func TestRateLimit_RedisFailureDoesNotBlockClient(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	repo := rateLimitRepo.NewMockRateLimitRepo()
+	repo.Err = errors.New("redis is down")
+	router := newRateLimitedRouter(apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100),
+		ratelimit.NewRateLimitService(log, repo), false, 1)
+
+	assert.Equal(t, http.StatusOK, getWeather(router, "").Code)
+	assert.Equal(t, http.StatusOK, getWeather(router, "").Code)
+}
+
+func TestAdminAPIKeysEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	handler := routes.NewAPIKeyHandler(log, apikey.NewAPIKeyService(log, &apiKeyRepo.MockAPIKeyRepository{}, 100))
+
+	router := gin.New()
+	admin := router.Group("/admin", middleware.AdminAuthMiddleware("secret"))
+	admin.POST("/api-keys", handler.Create)
+	admin.GET("/api-keys", handler.List)
+	admin.DELETE("/api-keys/:id", handler.Revoke)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewBufferString(`{"name":"partner"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Code)
+	var created dto.CreatedAPIKey
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Key)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), created.Key)
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/api-keys/1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/api-keys/42", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -14,10 +14,28 @@ func Test_WeatherService_ShouldOnlyUseCacheRepository(t *testing.T) {
 		)
 }
 
+func Test_AirQualityService_ShouldOnlyUseCacheRepository(t *testing.T) {
+	archtest.Package(t, "weatherApi/internal/service/airquality").
+		ShouldNotDependOn(
+			"weatherApi/internal/repository/subscription",
+			"weatherApi/internal/repository/user",
+		)
+}
+
+func Test_HistoryService_ShouldOnlyUseObservationRepository(t *testing.T) {
+	archtest.Package(t, "weatherApi/internal/service/history").
+		ShouldNotDependOn(
+			"weatherApi/internal/repository/subscription",
+			"weatherApi/internal/repository/user",
+			"weatherApi/internal/repository/weather",
+		)
+}
+
 func Test_SubscriptionService_ShouldOnlyUseSQLRepositories(t *testing.T) {
 	archtest.Package(t, "weatherApi/internal/service/subscription").
 		ShouldNotDependOn(
 			"weatherApi/internal/repository/weather",
+			"weatherApi/internal/repository/airquality",
 		)
 }
 
@@ -68,3 +86,14 @@ func Test_Metrics_ShouldNotDependOnInternalLayer(t *testing.T) {
 			"weatherApi/internal/service/...",
 		)
 }
+
+func Test_SchedulerService_ShouldNotDependOnApiServer(t *testing.T) {
+	archtest.Package(t, "weatherApi/cmd/scheduler_service").
+		ShouldNotDependOn(
+			"weatherApi/internal/server/...",
+		)
+	archtest.Package(t, "weatherApi/internal/bootstrap").
+		ShouldNotDependOn(
+			"weatherApi/internal/server/...",
+		)
+}
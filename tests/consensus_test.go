@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/service/weather"
+
+	serviceErrors "weatherApi/internal/service/weather/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConsensusOptions = weather.ConsensusOptions{
+	TemperatureTolerance: 3,
+	HumidityTolerance:    15,
+	PressureTolerance:    5,
+	WindSpeedTolerance:   3,
+}
+
+func newConsensusService(providers ...provider.WeatherProviderInterface) *weather.Service {
+	return weather.NewWeatherService(logger.NewNoOpLogger(), cacheRepo.NewMockCacheRepo(), providers...).
+		WithConsensus(testConsensusOptions, nil)
+}
+
+func reading(provider string, temperature float64, humidity int) *dto.WeatherResponse {
+	return &dto.WeatherResponse{
+		Temperature: temperature,
+		FeelsLike:   temperature,
+		Humidity:    humidity,
+		Pressure:    1012,
+		WindSpeed:   4,
+		Description: provider + " description",
+		Provider:    provider,
+	}
+}
+
+func TestWeatherConsensus_ExcludesOutlier(t *testing.T) {
+	first := &provider.MockProvider{Response: reading("first", 12, 60)}
+	second := &provider.MockProvider{Response: reading("second", 30, 62)}
+	third := &provider.MockProvider{Response: reading("third", 13, 64)}
+
+	svc := newConsensusService(first, second, third)
+	result, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+	require.Nil(t, err)
+
+	assert.Equal(t, 12.5, result.Temperature)
+	assert.Equal(t, 62, result.Humidity)
+	assert.Equal(t, weather.ConsensusProvider, result.Provider)
+	assert.Equal(t, []string{"first", "third"}, result.Sources)
+	assert.Equal(t, "first description", result.Description)
+	require.NotNil(t, result.Confidence)
+	assert.Equal(t, 0.67, *result.Confidence)
+	assert.Equal(t, 1, second.GetWeatherCallCount)
+}
+
+func TestWeatherConsensus_NoAgreementReturnsPrimary(t *testing.T) {
+	first := &provider.MockProvider{Response: reading("first", 30, 60)}
+	second := &provider.MockProvider{Response: reading("second", 12, 60)}
+
+	svc := newConsensusService(first, second)
+	result, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+	require.Nil(t, err)
+
+	assert.Equal(t, 30.0, result.Temperature)
+	assert.Equal(t, []string{"first"}, result.Sources)
+	require.NotNil(t, result.Confidence)
+	assert.Equal(t, 0.0, *result.Confidence)
+}
+
+func TestWeatherConsensus_ProviderFailureLowersConfidence(t *testing.T) {
+	failing := &provider.MockProvider{Err: serviceErrors.ErrInternalServerError}
+	working := &provider.MockProvider{Response: reading("working", 20, 50)}
+
+	svc := newConsensusService(failing, working)
+	result, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+	require.Nil(t, err)
+
+	assert.Equal(t, 20.0, result.Temperature)
+	require.NotNil(t, result.Confidence)
+	assert.Equal(t, 0.5, *result.Confidence)
+	assert.Equal(t, 1, working.GetWeatherCallCount, "consensus mode must not fall back through chain")
+}
+
+func TestWeatherConsensus_CityNotFound(t *testing.T) {
+	svc := newConsensusService(
+		&provider.MockProvider{Err: serviceErrors.ErrCityNotFound},
+		&provider.MockProvider{Err: serviceErrors.ErrInternalServerError},
+	)
+	_, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Atlantis"), dto.DefaultWeatherOptions())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Code)
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/suppression"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/scheduler"
+	"weatherApi/internal/service/weather"
+	"weatherApi/internal/worker"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// confirmedSubscriptions subscribes and confirms each email and city pair in order through subscription service,
+// so scheduler tests run on state subscribers can actually reach. Subscriptions are returned with user loaded
+func confirmedSubscriptions(t *testing.T, pairs ...[2]string) []subscription.SubscriptionModel {
+	store := newSubscriptionStore()
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	ctx := context.Background()
+	for i, pair := range pairs {
+		request := subscribeRequest(pair[0])
+		request.City = pair[1]
+		require.Nil(t, service.Subscribe(ctx, request))
+		require.Nil(t, service.ConfirmSubscription(ctx, publishedToken(t, publisher.Calls[i])))
+	}
+
+	subs := make([]subscription.SubscriptionModel, 0, len(store.subscriptions))
+	for id := uint(1); id <= uint(len(store.subscriptions)); id++ {
+		sub := *store.subscriptions[id]
+		sub.User = *store.users[sub.UserID]
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func TestSendNotification_DigestGroupsCitiesByUser(t *testing.T) {
+	subs := confirmedSubscriptions(t,
+		[2]string{"first@example.com", "Kyiv"},
+		[2]string{"second@example.com", "Kyiv"},
+		[2]string{"first@example.com", "Lviv"},
+	)
+	var mu sync.Mutex
+	var sent []uint
+	subRepo := &subscription.MockSubscriptionRepository{
+		Due: subs,
+		MarkSentFn: func(ids []uint, at time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, ids...)
+			return nil
+		},
+	}
+
+	log := logger.NewNoOpLogger()
+	weatherProvider := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 21, Description: "Sunny"}}
+	weatherService := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), weatherProvider)
+	publisher := broker.NewMockRabbitMQPublisher()
+	dispatcher := scheduler.NewDispatcher(log, subRepo, publisher, weatherService, nil, newTestSigner())
+	service, err := scheduler.NewService(log, subRepo, dispatcher, context.Background())
+	require.NoError(t, err)
+
+	_, err = service.WithDigest(true).SendNotification(context.Background(), constants.FrequencyDaily)
+	require.NoError(t, err)
+
+	require.Len(t, publisher.Calls, 2, "one digest per user")
+	digests := make(map[string]dto.WeatherDigestTask)
+	for _, call := range publisher.Calls {
+		assert.Equal(t, broker.SendDigestWeatherData, call.Topic)
+		var task dto.WeatherDigestTask
+		require.NoError(t, json.Unmarshal(call.Payload, &task))
+		digests[task.Email] = task
+	}
+
+	first := digests["first@example.com"]
+	require.Len(t, first.Sections, 2)
+	assert.Equal(t, "Kyiv", first.Sections[0].City)
+	assert.Equal(t, "Lviv", first.Sections[1].City)
+	assert.NotEqual(t, first.Sections[0].User.ManageToken, first.Sections[1].User.ManageToken, "each city links its own subscription")
+	assert.Equal(t, 21.0, first.Sections[1].Weather.Temperature)
+	assert.Len(t, digests["second@example.com"].Sections, 1)
+
+	sort.Slice(sent, func(i, j int) bool { return sent[i] < sent[j] })
+	assert.Equal(t, []uint{1, 2, 3}, sent)
+}
+
+func TestStartDigestWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suppressions := &suppression.MockSuppressionRepository{}
+	_, _ = suppressions.RecordEvent(ctx, dto.EmailEvent{Email: "gone@example.com", Type: constants.EmailComplaint}, 1)
+
+	mockSubscriber := broker.NewMockEventSubscriber()
+	mockSMTP := &provider.MockSMTPClient{}
+	deliveries := &delivery.MockDeliveryRepository{}
+	require.NoError(t, worker.StartDigestWorker(logger.NewNoOpLogger(), ctx, mockSubscriber, mockSMTP, suppressions, deliveries))
+
+	for _, email := range []string{"gone@example.com", "ok@example.com"} {
+		data, _ := json.Marshal(dto.WeatherDigestTask{
+			Email: email,
+			Sections: []dto.DigestSection{
+				{City: "Kyiv", User: dto.UserData{Email: email}},
+				{City: "Lviv", User: dto.UserData{Email: email}},
+			},
+		})
+		require.NoError(t, mockSubscriber.SimulateMessage(ctx, broker.SendDigestWeatherData, data))
+	}
+
+	require.Len(t, mockSMTP.SentDigests, 1)
+	assert.Equal(t, "ok@example.com", mockSMTP.SentDigests[0].Email)
+	assert.Len(t, mockSMTP.SentDigests[0].Sections, 2)
+	require.Len(t, deliveries.Deliveries, 1, "suppressed digest is not logged")
+	assert.Equal(t, "ok@example.com", deliveries.Deliveries[0].Email)
+	assert.Equal(t, []string{"Kyiv", "Lviv"}, deliveries.Deliveries[0].CityList())
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/repository/audit"
+	"weatherApi/internal/repository/delivery"
+	"weatherApi/internal/repository/privacy"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/suppression"
+	"weatherApi/internal/repository/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// openTestDB connects to Postgres from TEST_DB_URL, tests needing real queries are skipped without it
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: gormLogger.Default.LogMode(gormLogger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&user.UserModel{},
+		&subscription.SubscriptionModel{},
+		&delivery.DeliveryModel{},
+		&suppression.SuppressionModel{},
+		&audit.AuditLogModel{},
+	))
+	return db
+}
+
+func TestEraseUsers_RemovesDataOfCurrentAndPreviousAddresses(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	run := time.Now().UnixNano()
+	current := fmt.Sprintf("new-%d@example.com", run)
+	previous := fmt.Sprintf("old-%d@example.com", run)
+	other := fmt.Sprintf("other-%d@example.com", run)
+
+	owner := &user.UserModel{Email: current}
+	require.NoError(t, db.Create(owner).Error)
+	sub := &subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true, TokenExpires: time.Now()}
+	sub.AddPreviousEmail(previous)
+	require.NoError(t, db.Create(sub).Error)
+	for _, email := range []string{current, previous, other} {
+		require.NoError(t, db.Create(delivery.NewDelivery(email, constants.DeliveryWeather, []string{"Kyiv"}, time.Now())).Error)
+		require.NoError(t, db.Create(&suppression.SuppressionModel{
+			Email:       email,
+			LastEvent:   constants.EmailHardBounce,
+			LastEventAt: time.Now(),
+			Source:      "test",
+		}).Error)
+	}
+	entry := &audit.AuditLogModel{Actor: "oncall", Action: "force_confirm", UserID: &owner.ID, Details: "email=" + current}
+	require.NoError(t, db.Create(entry).Error)
+
+	require.NoError(t, privacy.NewErasureRepository(db).EraseUsers(ctx, []uint{owner.ID}))
+
+	count := func(model any, email string) int64 {
+		var n int64
+		require.NoError(t, db.Model(model).Where("email = ?", email).Count(&n).Error)
+		return n
+	}
+	for _, email := range []string{current, previous} {
+		assert.Zero(t, count(&delivery.DeliveryModel{}, email), email)
+		assert.Zero(t, count(&suppression.SuppressionModel{}, email), email)
+	}
+	assert.EqualValues(t, 1, count(&delivery.DeliveryModel{}, other), "other addresses are kept")
+	assert.EqualValues(t, 1, count(&suppression.SuppressionModel{}, other), "other addresses are kept")
+
+	var scrubbed audit.AuditLogModel
+	require.NoError(t, db.First(&scrubbed, entry.ID).Error)
+	assert.NotContains(t, scrubbed.Details, current)
+	var users int64
+	require.NoError(t, db.Unscoped().Model(&user.UserModel{}).Where("id = ?", owner.ID).Count(&users).Error)
+	assert.Zero(t, users)
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScenarios(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "scenarios.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFakeWeatherProvider_DeterministicPerCity(t *testing.T) {
+	fake := provider.NewFakeWeatherProvider(logger.NewNoOpLogger(), provider.FakeScenarios{})
+
+	first, err := fake.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+	second, err := fake.GetWeather(context.Background(), dto.NewCityLocation("kyiv"), "en")
+	require.Nil(t, err)
+	other, err := fake.GetWeather(context.Background(), dto.NewCityLocation("Lviv"), "en")
+	require.Nil(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first.Temperature, other.Temperature)
+	assert.Equal(t, "Fake", first.Provider)
+}
+
+func TestFakeWeatherProvider_Scenarios(t *testing.T) {
+	scenarios, loadErr := provider.LoadFakeScenarios(writeScenarios(t, `
+scenarios:
+  - city: London
+    type: outage
+  - city: Atlantis
+    type: not_found
+  - city: Slough
+    type: slow
+    delay: 1s
+  - city: Kyiv
+    type: warming
+    rate_per_hour: 36000000
+`))
+	require.NoError(t, loadErr)
+	log := logger.NewNoOpLogger()
+	fake := provider.NewFakeWeatherProvider(log, scenarios)
+
+	_, err := fake.GetWeather(context.Background(), dto.NewCityLocation("London"), "en")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, err.Code)
+
+	_, err = fake.GetWeather(context.Background(), dto.NewCityLocation("Atlantis"), "en")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = fake.GetWeather(ctx, dto.NewCityLocation("Slough"), "en")
+	require.NotNil(t, err, "slow scenario must respect context deadline")
+
+	before, err := fake.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+	time.Sleep(5 * time.Millisecond)
+	after, err := fake.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+	assert.Greater(t, after.Temperature, before.Temperature)
+}
+
+func TestFakeWeatherProvider_OutageFallsBackToNext(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	fake := provider.NewFakeWeatherProvider(log, provider.FakeScenarios{"*": {City: "*", Type: provider.FakeScenarioOutage}})
+	fallback := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 5}}
+	fake.SetNext(fallback)
+
+	result, err := fake.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+	assert.Equal(t, 5.0, result.Temperature)
+}
+
+func TestLoadFakeScenarios_UnknownType(t *testing.T) {
+	_, err := provider.LoadFakeScenarios(writeScenarios(t, `
+scenarios:
+  - city: Kyiv
+    type: meteor
+`))
+	assert.Error(t, err)
+}
+
+func TestFakeAirQualityProvider_Deterministic(t *testing.T) {
+	fake := provider.NewFakeAirQualityProvider(logger.NewNoOpLogger(), provider.FakeScenarios{})
+
+	first, err := fake.GetAirQuality(context.Background(), dto.NewCityLocation("Kyiv"))
+	require.Nil(t, err)
+	second, err := fake.GetAirQuality(context.Background(), dto.NewCityLocation("Kyiv"))
+	require.Nil(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEmpty(t, first.Category)
+}
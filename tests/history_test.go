@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/observation"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/history"
+	"weatherApi/internal/service/weather"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherService_StoresObservationOnProviderFetch(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	var stored []*observation.ObservationModel
+	repo := &observation.MockObservationRepository{
+		CreateOneFn: func(entity *observation.ObservationModel) error {
+			stored = append(stored, entity)
+			return nil
+		},
+	}
+	mockProvider := &provider.MockProvider{
+		Response: &dto.WeatherResponse{Temperature: 21.5, Humidity: 40, Provider: "MockProvider"},
+	}
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), mockProvider).WithObservationRepo(repo)
+
+	for range 2 {
+		_, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+		require.Nil(t, err)
+	}
+
+	require.Len(t, stored, 1, "cached reading must not be stored twice")
+	assert.Equal(t, "kyiv", stored[0].Location)
+	assert.Equal(t, "MockProvider", stored[0].Provider)
+	assert.Equal(t, 21.5, stored[0].Temperature)
+	assert.Equal(t, 40, stored[0].Humidity)
+	assert.WithinDuration(t, time.Now(), stored[0].ObservedAt, time.Minute)
+}
+
+func setupHistoryRouter(repo observation.ObservationRepositoryInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	router := gin.New()
+	router.GET("/weather/history", routes.NewHistoryHandler(log, history.NewHistoryService(log, repo), nil).GetHistory)
+	return router
+}
+
+func TestHistoryHandler_Success(t *testing.T) {
+	bucket := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var (
+		requestedLocation    string
+		requestedFrom        time.Time
+		requestedTo          time.Time
+		requestedGranularity constants.Granularity
+	)
+	repo := &observation.MockObservationRepository{
+		AggregateFn: func(location string, from, to time.Time, granularity constants.Granularity) ([]observation.AggregatedObservation, error) {
+			requestedLocation, requestedFrom, requestedTo, requestedGranularity = location, from, to, granularity
+			return []observation.AggregatedObservation{{
+				Bucket:         bucket,
+				Samples:        3,
+				MinTemperature: 10,
+				MaxTemperature: 20,
+				AvgTemperature: 15.3333,
+			}}, nil
+		},
+	}
+	router := setupHistoryRouter(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/history?city=Kyiv&from=2025-06-01&to=2025-06-08&granularity=day", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "kyiv", requestedLocation)
+	assert.Equal(t, bucket, requestedFrom)
+	assert.Equal(t, bucket.AddDate(0, 0, 7), requestedTo)
+	assert.Equal(t, constants.GranularityDay, requestedGranularity)
+
+	var data dto.HistoryResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+	require.Len(t, data.Points, 1)
+	assert.Equal(t, bucket, data.Points[0].Time)
+	assert.Equal(t, 3, data.Points[0].Samples)
+	assert.Equal(t, dto.HistoryStats{Min: 10, Max: 20, Avg: 15.33}, data.Points[0].Temperature)
+	assert.Equal(t, constants.UnitsMetric, data.Units)
+}
+
+func TestHistoryHandler_InvalidParams(t *testing.T) {
+	repo := &observation.MockObservationRepository{
+		AggregateFn: func(string, time.Time, time.Time, constants.Granularity) ([]observation.AggregatedObservation, error) {
+			t.Fatal("repository must not be called for invalid request")
+			return nil, nil
+		},
+	}
+	router := setupHistoryRouter(repo)
+
+	for _, query := range []string{
+		"granularity=week",
+		"from=yesterday",
+		"from=2025-06-08&to=2025-06-01",
+		"from=2025-01-01&to=2025-06-01&granularity=hour",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/weather/history?city=Kyiv&"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
@@ -0,0 +1,309 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	appErrors "weatherApi/internal/common/errors"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/jobrun"
+	"weatherApi/internal/repository/subscription"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/scheduler"
+	"weatherApi/internal/service/weather"
+	weatherErrors "weatherApi/internal/service/weather/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJobRunScheduler(
+	t *testing.T,
+	runs *jobrun.MockJobRunRepository,
+	window time.Duration,
+	weatherErr *appErrors.AppError,
+) (*scheduler.Service, *broker.MockRabbitMQPublisher, *subscription.MockSubscriptionRepository) {
+	subs := confirmedSubscriptions(t,
+		[2]string{"first@example.com", "Kyiv"},
+		[2]string{"second@example.com", "Kyiv"},
+		[2]string{"first@example.com", "Lviv"},
+	)
+	subRepo := &subscription.MockSubscriptionRepository{
+		Due: subs,
+		MarkSentFn: func(_ []uint, _ time.Time) error {
+			return nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	weatherProvider := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 21}, Err: weatherErr}
+	weatherService := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), weatherProvider)
+	publisher := broker.NewMockRabbitMQPublisher()
+	dispatcher := scheduler.NewDispatcher(log, subRepo, publisher, weatherService, nil, newTestSigner())
+	service, err := scheduler.NewService(log, subRepo, dispatcher, context.Background())
+	require.NoError(t, err)
+	return service.WithRunHistory(runs, window, 30*time.Minute), publisher, subRepo
+}
+
+func finishedRun(job constants.Frequency, slot time.Time) jobrun.JobRunModel {
+	return jobrun.JobRunModel{ID: 1, Job: string(job), Slot: slot, Status: constants.JobRunSucceeded, StartedAt: slot}
+}
+
+func TestCatchUp_SendsMissedSlotOnce(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)),
+		finishedRun(constants.FrequencyDaily, time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)),
+	}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+	service.CatchUp(context.Background(), now.Add(time.Minute))
+
+	require.Len(t, runs.Runs, 3, "only hourly slot was missed")
+	run := runs.Runs[2]
+	assert.Equal(t, string(constants.FrequencyHourly), run.Job)
+	assert.True(t, run.Slot.Equal(time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local)))
+	assert.True(t, run.CatchUp)
+	assert.Equal(t, constants.JobRunSucceeded, run.Status)
+	assert.NotNil(t, run.FinishedAt)
+	assert.Equal(t, 2, run.Cities)
+	assert.Equal(t, 3, run.Recipients)
+	assert.Zero(t, run.Failures)
+	assert.Len(t, publisher.Calls, 2, "one task per city")
+}
+
+func TestCatchUp_SkipsSlotsOutsideWindow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyDaily, time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)),
+	}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+
+	assert.Len(t, runs.Runs, 1, "daily slot is too old and hourly job never ran")
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestCatchUp_RecordsFailedRun(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 5, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyDaily, time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)),
+	}}
+	service, _, _ := newJobRunScheduler(t, runs, 3*time.Hour, weatherErrors.ErrInternalServerError)
+
+	service.CatchUp(context.Background(), now)
+
+	require.Len(t, runs.Runs, 2)
+	run := runs.Runs[1]
+	assert.True(t, run.Slot.Equal(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)))
+	assert.Equal(t, constants.JobRunFailed, run.Status)
+	assert.Equal(t, 3, run.Failures)
+	assert.NotEmpty(t, run.Error)
+}
+
+func TestCatchUp_RecordsFailedLoad(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)),
+	}}
+	service, publisher, subRepo := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+	subRepo.DueErr = errors.New("db is down")
+
+	service.CatchUp(context.Background(), now)
+
+	require.Len(t, runs.Runs, 2)
+	assert.Equal(t, constants.JobRunFailed, runs.Runs[1].Status)
+	assert.Contains(t, runs.Runs[1].Error, "db is down")
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestCatchUp_TakesOverStaleRunningRun(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	slot := time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local)
+	crashed := jobrun.JobRunModel{
+		ID:        2,
+		Job:       string(constants.FrequencyHourly),
+		Slot:      slot,
+		Status:    constants.JobRunRunning,
+		StartedAt: time.Now().Add(-time.Hour),
+	}
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)),
+		crashed,
+	}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+
+	require.Len(t, runs.Runs, 2, "stale run is taken over instead of adding another one")
+	run := runs.Runs[1]
+	assert.True(t, run.Slot.Equal(slot))
+	assert.True(t, run.CatchUp)
+	assert.Equal(t, constants.JobRunSucceeded, run.Status)
+	assert.NotNil(t, run.FinishedAt)
+	assert.Len(t, publisher.Calls, 2)
+}
+
+func TestCatchUp_WaitsForRunningRun(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)),
+		{
+			ID:        2,
+			Job:       string(constants.FrequencyHourly),
+			Slot:      time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local),
+			Status:    constants.JobRunRunning,
+			StartedAt: time.Now().Add(-5 * time.Minute),
+		},
+	}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+
+	assert.Equal(t, constants.JobRunRunning, runs.Runs[1].Status, "run within timeout may still finish")
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestCatchUp_WaitsForRunWithRecentHeartbeat(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	heartbeat := time.Now().Add(-time.Minute)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)),
+		{
+			ID:          2,
+			Job:         string(constants.FrequencyHourly),
+			Slot:        time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local),
+			Status:      constants.JobRunRunning,
+			StartedAt:   time.Now().Add(-time.Hour),
+			HeartbeatAt: &heartbeat,
+		},
+	}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+
+	assert.Equal(t, constants.JobRunRunning, runs.Runs[1].Status, "long run still sending heartbeat is not taken over")
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestCatchUp_TakenOverRunDoesNotOverwriteNewOwner(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{
+		finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)),
+	}}
+	service, _, subRepo := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+	subRepo.MarkSentFn = func(_ []uint, _ time.Time) error {
+		// run stalled long enough for another instance to take it over
+		runs.SetInstance(2, "other")
+		return nil
+	}
+
+	service.CatchUp(context.Background(), now)
+
+	require.Len(t, runs.Runs, 2)
+	assert.Equal(t, "other", runs.Runs[1].Instance)
+	assert.Equal(t, constants.JobRunRunning, runs.Runs[1].Status, "outcome of taken over run belongs to new owner")
+	assert.Nil(t, runs.Runs[1].FinishedAt)
+}
+
+func TestCatchUp_FailedRunIsNotRetried(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 20, 0, 0, time.Local)
+	failed := finishedRun(constants.FrequencyHourly, time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local))
+	failed.Status = constants.JobRunFailed
+	runs := &jobrun.MockJobRunRepository{Runs: []jobrun.JobRunModel{failed}}
+	service, publisher, _ := newJobRunScheduler(t, runs, 3*time.Hour, nil)
+
+	service.CatchUp(context.Background(), now)
+
+	assert.Len(t, runs.Runs, 1)
+	assert.Empty(t, publisher.Calls, "part of failed run may have been sent already")
+}
+
+func TestSendNotification_PagesKeepCityBatchesTogether(t *testing.T) {
+	subs := confirmedSubscriptions(t,
+		[2]string{"first@example.com", "Kyiv"},
+		[2]string{"second@example.com", "Lviv"},
+		[2]string{"third@example.com", " kyiv"},
+		[2]string{"fourth@example.com", "Odesa"},
+		[2]string{"fifth@example.com", "Lviv"},
+	)
+	subRepo := &subscription.MockSubscriptionRepository{Due: subs}
+	log := logger.NewNoOpLogger()
+	weatherProvider := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 21}}
+	weatherService := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), weatherProvider)
+	publisher := broker.NewMockRabbitMQPublisher()
+	dispatcher := scheduler.NewDispatcher(log, subRepo, publisher, weatherService, nil, newTestSigner())
+	service, err := scheduler.NewService(log, subRepo, dispatcher, context.Background())
+	require.NoError(t, err)
+
+	stats, err := service.WithPageSize(2).SendNotification(context.Background(), constants.FrequencyDaily)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, subRepo.DuePages)
+	assert.Equal(t, scheduler.RunStats{Cities: 3, Recipients: 5}, stats)
+	require.Len(t, publisher.Calls, 3, "city split across pages is sent as one task")
+	batches := make(map[string][]string)
+	for _, call := range publisher.Calls {
+		var task dto.WeatherSubData
+		require.NoError(t, json.Unmarshal(call.Payload, &task))
+		for _, user := range task.Users {
+			batches[task.Users[0].Email] = append(batches[task.Users[0].Email], user.Email)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"first@example.com":  {"first@example.com", "third@example.com"},
+		"second@example.com": {"second@example.com", "fifth@example.com"},
+		"fourth@example.com": {"fourth@example.com"},
+	}, batches)
+}
+
+func TestSendNotification_LoadedBatchIsSentWhenLoadingFails(t *testing.T) {
+	subs := confirmedSubscriptions(t,
+		[2]string{"first@example.com", "Kyiv"},
+		[2]string{"second@example.com", "Lviv"},
+		[2]string{"third@example.com", "Kyiv"},
+	)
+	subRepo := &subscription.MockSubscriptionRepository{Due: subs, DueErr: errors.New("db is down"), DueErrAfter: 1}
+	log := logger.NewNoOpLogger()
+	weatherProvider := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 21}}
+	weatherService := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), weatherProvider)
+	publisher := broker.NewMockRabbitMQPublisher()
+	dispatcher := scheduler.NewDispatcher(log, subRepo, publisher, weatherService, nil, newTestSigner())
+	service, err := scheduler.NewService(log, subRepo, dispatcher, context.Background())
+	require.NoError(t, err)
+
+	stats, err := service.WithPageSize(2).SendNotification(context.Background(), constants.FrequencyDaily)
+	require.Error(t, err)
+
+	assert.Equal(t, scheduler.RunStats{Cities: 1, Recipients: 2}, stats)
+	assert.Len(t, publisher.Calls, 1, "batch loaded before failure is not dropped")
+}
+
+func TestAdmin_JobRuns(t *testing.T) {
+	f := newAdminFixture()
+	slot := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	f.jobRuns.Runs = []jobrun.JobRunModel{
+		{ID: 1, Job: "daily", Slot: slot, Status: constants.JobRunSucceeded, Recipients: 4},
+		{ID: 2, Job: "hourly", Slot: slot.Add(time.Hour), Status: constants.JobRunFailed, Failures: 1},
+	}
+
+	resp := f.do(http.MethodGet, "/admin/jobs/runs?job=daily")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var body struct {
+		Runs []dto.JobRun `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Runs, 1)
+	assert.Equal(t, 4, body.Runs[0].Recipients)
+
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/admin/jobs/runs?job=weekly").Code)
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/admin/jobs/runs?limit=5000").Code)
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/leader"
+	"weatherApi/internal/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElector_SingleLeaderWithFailover(t *testing.T) {
+	ctx := context.Background()
+	leases := leader.NewMockLeaseRepo()
+	log := logger.NewNoOpLogger()
+	first := scheduler.NewLeaderElector(log, leases, "scheduler", "first", time.Minute, nil)
+	second := scheduler.NewLeaderElector(log, leases, "scheduler", "second", time.Minute, nil)
+
+	first.Campaign(ctx)
+	second.Campaign(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader(), "lease is held by the first instance")
+
+	first.Campaign(ctx)
+	assert.True(t, first.IsLeader(), "leader renews its own lease")
+
+	// first instance died without releasing lease
+	leases.Expire("scheduler")
+	second.Campaign(ctx)
+	assert.True(t, second.IsLeader())
+	first.Campaign(ctx)
+	assert.False(t, first.IsLeader(), "revived instance follows the new leader")
+}
+
+func TestLeaderElector_StopHandsLeaseOver(t *testing.T) {
+	ctx := context.Background()
+	leases := leader.NewMockLeaseRepo()
+	log := logger.NewNoOpLogger()
+	first := scheduler.NewLeaderElector(log, leases, "scheduler", "first", time.Minute, nil)
+	second := scheduler.NewLeaderElector(log, leases, "scheduler", "second", time.Minute, nil)
+
+	first.Start(ctx)
+	require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+	require.NoError(t, first.Stop(ctx))
+	assert.False(t, first.IsLeader())
+
+	second.Campaign(ctx)
+	assert.True(t, second.IsLeader(), "released lease is taken without waiting for expiry")
+}
+
+func TestLeaderElector_StepsDownWhenStoreUnavailable(t *testing.T) {
+	ctx := context.Background()
+	leases := leader.NewMockLeaseRepo()
+	elector := scheduler.NewLeaderElector(logger.NewNoOpLogger(), leases, "scheduler", "", time.Minute, nil)
+	assert.NotEmpty(t, elector.Holder())
+
+	elector.Campaign(ctx)
+	require.True(t, elector.IsLeader())
+
+	leases.Err = errors.New("redis is down")
+	elector.Campaign(ctx)
+	assert.False(t, elector.IsLeader())
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	"weatherApi/internal/logger"
+	privacyRepo "weatherApi/internal/repository/privacy"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/scheduler"
+
+	appErrors "weatherApi/internal/common/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeReminderSender struct {
+	sent   []uint
+	failID uint
+}
+
+func (f *fakeReminderSender) ResendConfirmation(_ context.Context, sub *subscription.SubscriptionModel) *appErrors.AppError {
+	if sub.ID == f.failID {
+		return appErrors.New(http.StatusInternalServerError, "smtp down", nil)
+	}
+	f.sent = append(f.sent, sub.ID)
+	return nil
+}
+
+func newMaintenanceScheduler(t *testing.T, maintenance scheduler.Maintenance) *scheduler.Service {
+	service, err := scheduler.NewService(logger.NewNoOpLogger(), nil, nil, context.Background())
+	require.NoError(t, err)
+	return service.WithMaintenance(maintenance)
+}
+
+func TestRunMaintenance_DeletesInBatches(t *testing.T) {
+	remaining := []int64{2, 2, 1}
+	var deleteBefore time.Time
+	subscriptionRepo := &subscription.MockSubscriptionRepository{
+		DeleteExpiredUnconfirmedFn: func(before time.Time, limit int) (int64, error) {
+			deleteBefore = before
+			assert.Equal(t, 2, limit)
+			deleted := remaining[0]
+			remaining = remaining[1:]
+			return deleted, nil
+		},
+	}
+	orphans := [][]uint{{7, 8}, {9}}
+	erasureRepo := &privacyRepo.MockErasureRepository{
+		FindOrphanedUserIDsFn: func(createdBefore time.Time, limit int) ([]uint, error) {
+			if len(orphans) == 0 {
+				return nil, nil
+			}
+			ids := orphans[0]
+			orphans = orphans[1:]
+			return ids, nil
+		},
+	}
+
+	service := newMaintenanceScheduler(t, scheduler.Maintenance{
+		Subscriptions: subscriptionRepo,
+		Users:         erasureRepo,
+		ExpiredGrace:  24 * time.Hour,
+		BatchSize:     2,
+	})
+	result, err := service.RunMaintenance(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, result.DeletedSubscriptions)
+	assert.Equal(t, 3, result.DeletedUsers)
+	assert.Equal(t, 0, result.Reminded)
+	assert.Equal(t, []uint{7, 8, 9}, erasureRepo.ErasedUserIDs)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), deleteBefore, time.Minute)
+}
+
+func TestRunMaintenance_SendsReminderOnce(t *testing.T) {
+	pending := []subscription.SubscriptionModel{
+		{Model: gorm.Model{ID: 1}},
+		{Model: gorm.Model{ID: 2}},
+	}
+	var marked []uint
+	subscriptionRepo := &subscription.MockSubscriptionRepository{
+		FindExpiringUnconfirmedFn: func(from, to time.Time, limit int) ([]subscription.SubscriptionModel, error) {
+			assert.Equal(t, time.Hour, to.Sub(from))
+			result := pending
+			pending = nil
+			return result, nil
+		},
+		MarkRemindedFn: func(ids []uint, at time.Time) error {
+			marked = append(marked, ids...)
+			return nil
+		},
+		DeleteExpiredUnconfirmedFn: func(before time.Time, limit int) (int64, error) {
+			return 0, nil
+		},
+	}
+	erasureRepo := &privacyRepo.MockErasureRepository{
+		FindOrphanedUserIDsFn: func(createdBefore time.Time, limit int) ([]uint, error) {
+			return nil, nil
+		},
+	}
+	sender := &fakeReminderSender{failID: 2}
+
+	service := newMaintenanceScheduler(t, scheduler.Maintenance{
+		Subscriptions:  subscriptionRepo,
+		Users:          erasureRepo,
+		Reminders:      sender,
+		ReminderBefore: time.Hour,
+	})
+	result, err := service.RunMaintenance(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Reminded)
+	assert.Equal(t, []uint{1}, sender.sent)
+	assert.Equal(t, []uint{1, 2}, marked, "failed reminder must not be retried")
+}
+
+func TestRunMaintenance_StopsOnRepositoryError(t *testing.T) {
+	subscriptionRepo := &subscription.MockSubscriptionRepository{
+		DeleteExpiredUnconfirmedFn: func(before time.Time, limit int) (int64, error) {
+			return 0, errors.New("db down")
+		},
+	}
+	erasureRepo := &privacyRepo.MockErasureRepository{
+		FindOrphanedUserIDsFn: func(createdBefore time.Time, limit int) ([]uint, error) {
+			t.Fatal("orphans must not be purged after failure")
+			return nil, nil
+		},
+	}
+
+	service := newMaintenanceScheduler(t, scheduler.Maintenance{Subscriptions: subscriptionRepo, Users: erasureRepo})
+	_, err := service.RunMaintenance(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMaintenance_NonPositiveIntervalDisablesJob(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		service := newMaintenanceScheduler(t, scheduler.Maintenance{Interval: interval}).WithNotifications(false)
+
+		require.NoError(t, service.Start(), "interval %s", interval)
+		require.NoError(t, service.Stop())
+	}
+}
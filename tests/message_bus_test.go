@@ -46,7 +46,7 @@ func TestPublisherCalled(t *testing.T) {
 
 	publisher := broker.NewMockRabbitMQPublisher()
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
@@ -99,7 +99,7 @@ func TestPublisherNotCalledOnInvalidInput(t *testing.T) {
 
 	publisher := broker.NewMockRabbitMQPublisher()
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
@@ -142,7 +142,7 @@ func TestPublisherNotCalledOnInternalError(t *testing.T) {
 	publisher := broker.NewMockRabbitMQPublisher()
 	log := logger.NewNoOpLogger()
 
-	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
@@ -176,12 +176,11 @@ func TestPublisherNotCalled_SubscriptionAlreadyConfirmed(t *testing.T) {
 	subRepo := &subscription.MockSubscriptionRepository{
 		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
 			return &subscription.SubscriptionModel{
-				City:         "Test",
-				Frequency:    constants.FrequencyHourly,
-				UserID:       1,
-				User:         user.UserModel{},
-				IsConfirmed:  true,
-				ConfirmToken: "test",
+				City:        "Test",
+				Frequency:   constants.FrequencyHourly,
+				UserID:      1,
+				User:        user.UserModel{},
+				IsConfirmed: true,
 			}, nil
 		},
 		CreateOneFn: func(_ *subscription.SubscriptionModel) error {
@@ -195,7 +194,7 @@ func TestPublisherNotCalled_SubscriptionAlreadyConfirmed(t *testing.T) {
 	publisher := broker.NewMockRabbitMQPublisher()
 	log := logger.NewNoOpLogger()
 
-	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
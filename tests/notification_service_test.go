@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"testing"
 	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/common/utils"
 	"weatherApi/internal/dto"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/delivery"
 	"weatherApi/internal/worker"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStartConfirmationWorker(t *testing.T) {
@@ -21,7 +24,7 @@ func TestStartConfirmationWorker(t *testing.T) {
 	mockSubscriber := broker.NewMockEventSubscriber()
 	mockSMTP := &provider.MockSMTPClient{}
 	log := logger.NewNoOpLogger()
-	err := worker.StartConfirmationWorker(log, ctx, mockSubscriber, mockSMTP)
+	err := worker.StartConfirmationWorker(log, ctx, mockSubscriber, mockSMTP, nil)
 	assert.NoError(t, err)
 
 	task := dto.ConfirmationEmailTask{
@@ -45,22 +48,24 @@ func TestStartSubscriptionWorker(t *testing.T) {
 
 	mockSubscriber := broker.NewMockEventSubscriber()
 	mockSMTP := &provider.MockSMTPClient{}
+	deliveries := &delivery.MockDeliveryRepository{}
 	log := logger.NewNoOpLogger()
 
-	err := worker.StartSubscriptionWorker(log, ctx, mockSubscriber, mockSMTP)
+	err := worker.StartSubscriptionWorker(log, ctx, mockSubscriber, mockSMTP, nil, deliveries)
 	assert.NoError(t, err)
 
 	randomResponse := utils.RandomWeatherAPIResponse()
 
 	task := dto.WeatherSubData{
+		City: "Kyiv",
 		Weather: dto.WeatherResponse{
 			Temperature: randomResponse.Current.Temperature,
 			Humidity:    randomResponse.Current.Humidity,
 			Description: randomResponse.Current.Condition.Text,
 		},
 		Users: []dto.UserData{
-			{Email: "user1@example.com", Token: "123"},
-			{Email: "user2@example.com", Token: "321"},
+			{Email: "user1@example.com", UnsubscribeToken: "123"},
+			{Email: "user2@example.com", UnsubscribeToken: "321"},
 		},
 	}
 	data, _ := json.Marshal(task)
@@ -74,4 +79,62 @@ func TestStartSubscriptionWorker(t *testing.T) {
 	assert.Equal(t, task.Users[1], mockSMTP.SentUserData[0])
 	assert.Equal(t, task.Weather, mockSMTP.SentWeatherData[1])
 
+	logged, err := deliveries.FindByEmail(ctx, "User1@example.com")
+	require.NoError(t, err)
+	require.Len(t, logged, 1)
+	assert.Equal(t, constants.DeliveryWeather, logged[0].Kind)
+	assert.Equal(t, []string{"Kyiv"}, logged[0].CityList())
+}
+
+func TestStartSubscriptionWorker_AirQuality(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockSubscriber := broker.NewMockEventSubscriber()
+	mockSMTP := &provider.MockSMTPClient{}
+	log := logger.NewNoOpLogger()
+
+	err := worker.StartSubscriptionWorker(log, ctx, mockSubscriber, mockSMTP, nil, nil)
+	assert.NoError(t, err)
+
+	highThreshold := 200
+	task := dto.WeatherSubData{
+		Weather:    dto.WeatherResponse{Temperature: 10},
+		AirQuality: &dto.AirQualityResponse{AQI: 120},
+		Users: []dto.UserData{
+			{Email: "alert@example.com", UnsubscribeToken: "123", AQIAlertThreshold: &highThreshold},
+		},
+	}
+	data, _ := json.Marshal(task)
+
+	err = mockSubscriber.SimulateMessage(ctx, broker.SendSubscriptionWeatherData, data)
+	assert.NoError(t, err)
+	assert.Len(t, mockSMTP.SentAirQuality, 1)
+	assert.Nil(t, mockSMTP.SentAirQuality[0], "air quality below threshold must not be sent")
+
+	lowThreshold := 100
+	task.Users[0].AQIAlertThreshold = &lowThreshold
+	data, _ = json.Marshal(task)
+
+	err = mockSubscriber.SimulateMessage(ctx, broker.SendSubscriptionWeatherData, data)
+	assert.NoError(t, err)
+	assert.Len(t, mockSMTP.SentAirQuality, 2)
+	assert.Equal(t, task.AirQuality, mockSMTP.SentAirQuality[1])
+}
+
+func TestStartPrivacyWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockSubscriber := broker.NewMockEventSubscriber()
+	mockSMTP := &provider.MockSMTPClient{}
+	err := worker.StartPrivacyWorker(logger.NewNoOpLogger(), ctx, mockSubscriber, mockSMTP)
+	assert.NoError(t, err)
+
+	task := dto.PrivacyLinkTask{Email: "test@example.com", Token: "abc123", Action: constants.PrivacyErase}
+	data, _ := json.Marshal(task)
+
+	err = mockSubscriber.SimulateMessage(ctx, broker.PrivacyLinkTasks, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.PrivacyLinkTask{task}, mockSMTP.SentPrivacyLinks)
 }
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/base"
+	"weatherApi/internal/repository/delivery"
+	privacyRepo "weatherApi/internal/repository/privacy"
+	rateLimitRepo "weatherApi/internal/repository/ratelimit"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/privacy"
+	"weatherApi/internal/service/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type privacyFixture struct {
+	router      *gin.Engine
+	service     *privacy.Service
+	publisher   *broker.MockRabbitMQPublisher
+	erasureRepo *privacyRepo.MockErasureRepository
+}
+
+func newPrivacyFixture(options privacy.Options) *privacyFixture {
+	owner := &user.UserModel{Model: gorm.Model{ID: 3, CreatedAt: time.Now().Add(-24 * time.Hour)}, Email: "jane@example.com"}
+	confirmedAt := time.Now().Add(-time.Hour)
+	userRepo := &user.MockUserRepository{
+		FindOneOrNoneFn: func(_ any, args ...any) (*user.UserModel, error) {
+			if args[0] == owner.Email {
+				return owner, nil
+			}
+			return nil, base.ErrNotFound
+		},
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindAllByUserIDsFn: func(_ []uint) ([]subscription.SubscriptionModel, error) {
+			return []subscription.SubscriptionModel{
+				{City: "Kyiv", Frequency: constants.FrequencyDaily, UserID: 3, IsConfirmed: true, ConfirmedAt: &confirmedAt, LastSentAt: &confirmedAt},
+				{City: "Lviv", UserID: 3, Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: confirmedAt, Valid: true}}},
+			}, nil
+		},
+	}
+	if options.TokenTTL == 0 {
+		options.TokenTTL = time.Hour
+	}
+	f := &privacyFixture{
+		publisher:   broker.NewMockRabbitMQPublisher(),
+		erasureRepo: &privacyRepo.MockErasureRepository{},
+	}
+	log := logger.NewNoOpLogger()
+	deliveries := &delivery.MockDeliveryRepository{}
+	_ = deliveries.Record(context.Background(), delivery.NewDelivery(owner.Email, constants.DeliveryWeather, []string{"Kyiv"}, confirmedAt))
+	_ = deliveries.Record(context.Background(), delivery.NewDelivery("john@example.com", constants.DeliveryWeather, []string{"Lviv"}, confirmedAt))
+	f.service = privacy.NewPrivacyService(
+		log, userRepo, subRepo, f.erasureRepo, deliveries, privacyRepo.NewMockTokenRepo(), f.publisher, options,
+	)
+	handler := routes.NewPrivacyHandler(log, f.service)
+
+	gin.SetMode(gin.TestMode)
+	f.router = gin.New()
+	f.router.POST("/privacy/requests", handler.RequestLink)
+	f.router.GET("/privacy/export/:token", handler.Export)
+	f.router.POST("/privacy/erase/:token", handler.Erase)
+	return f
+}
+
+func (f *privacyFixture) request(email string, action constants.PrivacyAction) int {
+	body, _ := json.Marshal(gin.H{"email": email, "action": action})
+	req := httptest.NewRequest(http.MethodPost, "/privacy/requests", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	f.router.ServeHTTP(resp, req)
+	return resp.Code
+}
+
+func (f *privacyFixture) lastToken(t *testing.T) dto.PrivacyLinkTask {
+	require.NotEmpty(t, f.publisher.Calls)
+	call := f.publisher.Calls[len(f.publisher.Calls)-1]
+	require.Equal(t, broker.PrivacyLinkTasks, call.Topic)
+	var task dto.PrivacyLinkTask
+	require.NoError(t, json.Unmarshal(call.Payload, &task))
+	return task
+}
+
+func (f *privacyFixture) do(method, path string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	f.router.ServeHTTP(resp, httptest.NewRequest(method, path, nil))
+	return resp
+}
+
+func TestPrivacy_UnknownEmailIsNotDisclosed(t *testing.T) {
+	f := newPrivacyFixture(privacy.Options{})
+
+	assert.Equal(t, http.StatusAccepted, f.request("nobody@example.com", constants.PrivacyExport))
+	assert.Empty(t, f.publisher.Calls)
+	assert.Equal(t, http.StatusBadRequest, f.request("jane@example.com", "forget"))
+}
+
+func TestPrivacy_ExportWithVerifiedLink(t *testing.T) {
+	f := newPrivacyFixture(privacy.Options{})
+
+	require.Equal(t, http.StatusAccepted, f.request("Jane@Example.com", constants.PrivacyExport))
+	task := f.lastToken(t)
+	assert.Equal(t, "jane@example.com", task.Email)
+	assert.Equal(t, constants.PrivacyExport, task.Action)
+
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodPost, "/privacy/erase/"+task.Token).Code, "export link can't erase")
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, "/privacy/export/forged").Code)
+
+	resp := f.do(http.MethodGet, "/privacy/export/"+task.Token)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "attachment")
+	var export dto.DataExport
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &export))
+	assert.Equal(t, "jane@example.com", export.User.Email)
+	require.Len(t, export.Subscriptions, 2)
+	assert.NotNil(t, export.Subscriptions[0].LastSentAt)
+	assert.NotNil(t, export.Subscriptions[1].UnsubscribedAt)
+	require.Len(t, export.Deliveries, 1, "deliveries of other users are not exported")
+	assert.Equal(t, []string{"Kyiv"}, export.Deliveries[0].Cities)
+	assert.Empty(t, f.erasureRepo.ErasedUserIDs)
+}
+
+func TestPrivacy_EraseLinkIsSingleUse(t *testing.T) {
+	f := newPrivacyFixture(privacy.Options{})
+
+	require.Equal(t, http.StatusAccepted, f.request("jane@example.com", constants.PrivacyErase))
+	task := f.lastToken(t)
+
+	assert.Equal(t, http.StatusOK, f.do(http.MethodPost, "/privacy/erase/"+task.Token).Code)
+	assert.Equal(t, []uint{3}, f.erasureRepo.ErasedUserIDs)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodPost, "/privacy/erase/"+task.Token).Code)
+}
+
+func TestPrivacy_RequestsAreThrottledPerEmail(t *testing.T) {
+	limiter := ratelimit.NewRateLimitService(logger.NewNoOpLogger(), rateLimitRepo.NewMockRateLimitRepo())
+	f := newPrivacyFixture(privacy.Options{Limiter: limiter, RequestsPerHour: 2})
+
+	assert.Equal(t, http.StatusAccepted, f.request("jane@example.com", constants.PrivacyExport))
+	assert.Equal(t, http.StatusAccepted, f.request("jane@example.com", constants.PrivacyErase))
+	assert.Equal(t, http.StatusTooManyRequests, f.request("jane@example.com", constants.PrivacyExport))
+	assert.Len(t, f.publisher.Calls, 2)
+}
+
+func TestPrivacy_PurgeUnconfirmedInBatches(t *testing.T) {
+	f := newPrivacyFixture(privacy.Options{UnconfirmedRetention: 30 * 24 * time.Hour})
+	batches := [][]uint{make([]uint, 500), {501, 502}}
+	var cutoff time.Time
+	f.erasureRepo.FindUnconfirmedUserIDsFn = func(before time.Time, _ int) ([]uint, error) {
+		cutoff = before
+		batch := batches[0]
+		batches = batches[1:]
+		return batch, nil
+	}
+
+	purged, err := f.service.PurgeUnconfirmed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 502, purged)
+	assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), cutoff, time.Minute)
+
+	disabled := newPrivacyFixture(privacy.Options{})
+	purged, err = disabled.service.PurgeUnconfirmed(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weatherApi/internal/common/utils"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/service/weather"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusServer(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+}
+
+func TestProviderErrors_AuthAndQuotaFailuresFallBack(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+		primaryAPI := statusServer(status)
+		mockResp := utils.RandomOpenweatherMapAPIResponse()
+		fallbackAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(mockResp)
+		}))
+		log := logger.NewNoOpLogger()
+
+		svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(),
+			provider.NewOpenWeatherApiProvider(log, "test", primaryAPI.URL),
+			provider.NewOpenWeatherApiProvider(log, "test", fallbackAPI.URL),
+		)
+
+		result, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+		require.Nil(t, err, "status %d must fall back", status)
+		assert.Equal(t, mockResp.Main.Temperature, result.Temperature)
+
+		primaryAPI.Close()
+		fallbackAPI.Close()
+	}
+}
+
+func TestProviderErrors_ExhaustedChainStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected int
+	}{
+		{name: "upstream unavailable", status: http.StatusServiceUnavailable, expected: http.StatusServiceUnavailable},
+		{name: "rate limited", status: http.StatusTooManyRequests, expected: http.StatusServiceUnavailable},
+		{name: "invalid key", status: http.StatusUnauthorized, expected: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := statusServer(tt.status)
+			defer mockAPI.Close()
+			log := logger.NewNoOpLogger()
+
+			options := provider.DefaultHTTPClientOptions()
+			options.MaxRetries = 0
+			svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(),
+				provider.NewOpenWeatherApiProvider(log, "test", mockAPI.URL).WithHTTPClient(provider.NewHTTPClient(options, nil)),
+			)
+
+			_, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+			require.NotNil(t, err)
+			assert.Equal(t, tt.expected, err.Code)
+		})
+	}
+}
+
+func TestProviderErrors_WeatherApiUnknownLocationIsNotFound(t *testing.T) {
+	mockAPI := statusServer(http.StatusBadRequest)
+	defer mockAPI.Close()
+	fallback := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 1}}
+	log := logger.NewNoOpLogger()
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(),
+		provider.NewWeatherApiProvider(log, "test", mockAPI.URL),
+		fallback,
+	)
+
+	_, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Atlantis"), dto.DefaultWeatherOptions())
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.Code)
+	assert.Equal(t, 0, fallback.GetWeatherCallCount)
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weatherApi/internal/common/utils"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	quotaRepo "weatherApi/internal/repository/quota"
+	"weatherApi/internal/service/quota"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHTTPClientOptions() provider.HTTPClientOptions {
+	options := provider.DefaultHTTPClientOptions()
+	options.RetryBaseDelay = time.Millisecond
+	return options
+}
+
+func TestProviderHTTPClient_EncodesCityName(t *testing.T) {
+	var receivedCity string
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedCity = r.URL.Query().Get("q")
+		_ = json.NewEncoder(w).Encode(utils.RandomWeatherAPIResponse())
+	}))
+	defer mockAPI.Close()
+
+	weatherProvider := provider.NewWeatherApiProvider(logger.NewNoOpLogger(), "test", mockAPI.URL).
+		WithHTTPClient(provider.NewHTTPClient(testHTTPClientOptions(), nil))
+
+	_, err := weatherProvider.Fetch(context.Background(), dto.NewCityLocation("São Paulo"), "en")
+	require.Nil(t, err)
+	assert.Equal(t, "São Paulo", receivedCity)
+}
+
+func TestProviderHTTPClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(utils.RandomWeatherAPIResponse())
+	}))
+	defer mockAPI.Close()
+
+	weatherProvider := provider.NewWeatherApiProvider(logger.NewNoOpLogger(), "test", mockAPI.URL).
+		WithHTTPClient(provider.NewHTTPClient(testHTTPClientOptions(), nil))
+
+	_, err := weatherProvider.Fetch(context.Background(), dto.NewCityLocation("New York"), "en")
+	require.Nil(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestProviderHTTPClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer mockAPI.Close()
+
+	client := provider.NewHTTPClient(testHTTPClientOptions(), nil)
+	status, _, err := client.Get(context.Background(), "test", mockAPI.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestProviderHTTPClient_RejectsOversizedBody(t *testing.T) {
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 2048)))
+	}))
+	defer mockAPI.Close()
+
+	options := testHTTPClientOptions()
+	options.MaxBodyBytes = 1024
+	client := provider.NewHTTPClient(options, nil)
+
+	_, _, err := client.Get(context.Background(), "test", mockAPI.URL, nil)
+	assert.Error(t, err)
+}
+
+func TestProviderHTTPClient_RetriesCountAgainstQuota(t *testing.T) {
+	var calls atomic.Int32
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockAPI.Close()
+
+	log := logger.NewNoOpLogger()
+	quotaService := quota.NewQuotaService(log, quotaRepo.NewMockQuotaRepo(), map[string]int64{provider.WeatherApiName: 2}, nil)
+	options := testHTTPClientOptions()
+	options.MaxRetries = 5
+	weatherProvider := provider.NewQuotaLimitedProvider(log,
+		provider.NewWeatherApiProvider(log, "test", mockAPI.URL).WithHTTPClient(provider.NewHTTPClient(options, nil)),
+		quotaService, provider.WeatherApiName)
+
+	_, err := weatherProvider.Fetch(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.NotNil(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "retrying stops once quota is exhausted")
+	assert.False(t, quotaService.Acquire(context.Background(), provider.WeatherApiName))
+}
+
+func TestProviderHTTPClient_NonPositiveTimeoutFallsBackToDefault(t *testing.T) {
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockAPI.Close()
+
+	options := testHTTPClientOptions()
+	options.Timeout = 0
+	options.MaxBodyBytes = 0
+	status, _, err := provider.NewHTTPClient(options, nil).Get(context.Background(), "test", mockAPI.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+}
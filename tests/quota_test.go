@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/middleware"
+	"weatherApi/internal/provider"
+	quotaRepo "weatherApi/internal/repository/quota"
+	cacheRepo "weatherApi/internal/repository/weather"
+	"weatherApi/internal/server/routes"
+	"weatherApi/internal/service/quota"
+	"weatherApi/internal/service/weather"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuota_ExhaustedProviderIsSkipped(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	repo := quotaRepo.NewMockQuotaRepo()
+	repo.Set("primary", time.Now(), 10)
+	quotaService := quota.NewQuotaService(log, repo, map[string]int64{"primary": 10, "fallback": 0}, nil)
+
+	primary := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 1}}
+	fallback := &provider.MockProvider{Response: &dto.WeatherResponse{Temperature: 2}}
+
+	svc := weather.NewWeatherService(
+		log,
+		cacheRepo.NewMockCacheRepo(),
+		provider.NewQuotaLimitedProvider(log, primary, quotaService, "primary"),
+		provider.NewQuotaLimitedProvider(log, fallback, quotaService, "fallback"),
+	)
+
+	result, err := svc.GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), dto.DefaultWeatherOptions())
+	require.Nil(t, err)
+	assert.Equal(t, 2.0, result.Temperature)
+	assert.Equal(t, 0, primary.GetWeatherCallCount)
+	assert.Equal(t, 1, fallback.GetWeatherCallCount)
+
+	usage, err := quotaService.Usage(context.Background())
+	require.Nil(t, err)
+	require.Len(t, usage, 2)
+	assert.Equal(t, "fallback", usage[0].Provider)
+	assert.Equal(t, int64(1), usage[0].Used)
+	assert.Nil(t, usage[0].Remaining)
+	assert.Equal(t, "primary", usage[1].Provider)
+	assert.True(t, usage[1].Exhausted)
+	require.NotNil(t, usage[1].Remaining)
+	assert.Equal(t, int64(0), *usage[1].Remaining)
+}
+
+func TestQuota_AcquireCountsCallsUntilLimit(t *testing.T) {
+	quotaService := quota.NewQuotaService(logger.NewNoOpLogger(), quotaRepo.NewMockQuotaRepo(), map[string]int64{"provider": 2}, nil)
+
+	assert.True(t, quotaService.Acquire(context.Background(), "provider"))
+	assert.True(t, quotaService.Acquire(context.Background(), "provider"))
+	assert.False(t, quotaService.Acquire(context.Background(), "provider"))
+}
+
+func TestQuota_RedisFailureDoesNotBlockProvider(t *testing.T) {
+	repo := quotaRepo.NewMockQuotaRepo()
+	repo.Err = errors.New("redis is down")
+	quotaService := quota.NewQuotaService(logger.NewNoOpLogger(), repo, map[string]int64{"provider": 1}, nil)
+
+	assert.True(t, quotaService.Acquire(context.Background(), "provider"))
+}
+
+func TestAdminQuotaEndpoint_RequiresToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	quotaService := quota.NewQuotaService(log, quotaRepo.NewMockQuotaRepo(), map[string]int64{"provider": 100}, nil)
+
+	router := gin.New()
+	admin := router.Group("/admin", middleware.AdminAuthMiddleware("secret"))
+	admin.GET("/quota", routes.NewAdminHandler(log, quotaService).GetQuota)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/quota", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/quota", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var body struct {
+		Providers []dto.ProviderQuota `json:"providers"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Providers, 1)
+	assert.Equal(t, int64(100), body.Providers[0].Limit)
+	assert.Equal(t, int64(0), body.Providers[0].Used)
+}
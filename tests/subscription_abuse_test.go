@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/tokens"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/base"
+	rateLimitRepo "weatherApi/internal/repository/ratelimit"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+	"weatherApi/internal/service/ratelimit"
+	subscriptionService "weatherApi/internal/service/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatefulSubscriptionService keeps single subscription in memory, so repeated subscribe sees previous state
+func newStatefulSubscriptionService(publisher *broker.MockRabbitMQPublisher) *subscriptionService.SubscriptionService {
+	svc, _ := newSubscriptionServiceWithStore(publisher)
+	return svc
+}
+
+func newSubscriptionServiceWithStore(
+	publisher *broker.MockRabbitMQPublisher,
+) (*subscriptionService.SubscriptionService, **subscription.SubscriptionModel) {
+	var stored *subscription.SubscriptionModel
+	userRepo := &user.MockUserRepository{
+		FindOneOrCreateFn: func(_ map[string]any, e *user.UserModel) (*user.UserModel, error) {
+			e.ID = 1
+			return e, nil
+		},
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			if stored == nil {
+				return nil, base.ErrNotFound
+			}
+			found := *stored
+			return &found, nil
+		},
+		CreateOneFn: func(e *subscription.SubscriptionModel) error {
+			saved := *e
+			stored = &saved
+			return nil
+		},
+		UpdateFn: func(e *subscription.SubscriptionModel) error {
+			saved := *e
+			stored = &saved
+			return nil
+		},
+	}
+	return subscriptionService.NewSubscriptionService(logger.NewNoOpLogger(), subRepo, userRepo, publisher, 60, newTestSigner()), &stored
+}
+
+func subscribeRequest(email string) *dto.SubscribeRequest {
+	return &dto.SubscribeRequest{Email: email, City: "Kyiv", Frequency: "daily", ClientIP: "10.0.0.1"}
+}
+
+func publishedToken(t *testing.T, call broker.PublishCall) string {
+	var task dto.ConfirmationEmailTask
+	require.NoError(t, json.Unmarshal(call.Payload, &task))
+	return task.Token
+}
+
+func TestSubscribeAbuse_DisposableDomainRejected(t *testing.T) {
+	publisher := broker.NewMockRabbitMQPublisher()
+	svc := newStatefulSubscriptionService(publisher).WithAbuseProtection(subscriptionService.AbuseProtection{
+		DisposableDomains: []string{"spam.example"},
+	})
+
+	for _, email := range []string{"bot@mailinator.com", "bot@eu.mailinator.com", "bot@spam.example"} {
+		err := svc.Subscribe(context.Background(), subscribeRequest(email))
+		require.NotNil(t, err, email)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+	}
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestSubscribeAbuse_EmailSendsThrottled(t *testing.T) {
+	publisher := broker.NewMockRabbitMQPublisher()
+	limiter := ratelimit.NewRateLimitService(logger.NewNoOpLogger(), rateLimitRepo.NewMockRateLimitRepo())
+	svc := newStatefulSubscriptionService(publisher).WithAbuseProtection(subscriptionService.AbuseProtection{
+		Limiter:           limiter,
+		EmailSendsPerHour: 2,
+		IPSendsPerHour:    100,
+	})
+
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("user@example.com")))
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("User@Example.com")))
+	err := svc.Subscribe(context.Background(), subscribeRequest("user@example.com"))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, err.Code)
+	assert.Len(t, publisher.Calls, 2)
+}
+
+func TestSubscribeAbuse_CooldownResendsExistingToken(t *testing.T) {
+	publisher := broker.NewMockRabbitMQPublisher()
+	svc := newStatefulSubscriptionService(publisher).WithAbuseProtection(subscriptionService.AbuseProtection{
+		ResendCooldown: 10 * time.Minute,
+	})
+
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("user@example.com")))
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("user@example.com")))
+	require.Len(t, publisher.Calls, 2)
+	assert.Equal(t, publishedToken(t, publisher.Calls[0]), publishedToken(t, publisher.Calls[1]))
+}
+
+func TestSubscribeAbuse_TokenRegeneratedWithoutCooldown(t *testing.T) {
+	publisher := broker.NewMockRabbitMQPublisher()
+	svc, stored := newSubscriptionServiceWithStore(publisher)
+
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("user@example.com")))
+	// pretend the first token was sent a minute ago
+	(*stored).TokenExpires = (*stored).TokenExpires.Add(-time.Minute)
+	oldToken := signTestToken(tokens.PurposeConfirm, (*stored).ID, (*stored).TokenExpires)
+
+	require.Nil(t, svc.Subscribe(context.Background(), subscribeRequest("user@example.com")))
+	require.Len(t, publisher.Calls, 2)
+	newToken := publishedToken(t, publisher.Calls[1])
+	assert.NotEqual(t, oldToken, newToken)
+
+	err := svc.ConfirmSubscription(context.Background(), oldToken)
+	require.NotNil(t, err, "previously sent token must be revoked")
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+	assert.Nil(t, svc.ConfirmSubscription(context.Background(), newToken))
+}
+
+func TestSubscribeAbuse_LocalCaptcha(t *testing.T) {
+	publisher := broker.NewMockRabbitMQPublisher()
+	svc := newStatefulSubscriptionService(publisher).WithAbuseProtection(subscriptionService.AbuseProtection{
+		Captcha: provider.NewLocalCaptchaVerifier("solved"),
+	})
+
+	err := svc.Subscribe(context.Background(), subscribeRequest("user@example.com"))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+
+	request := subscribeRequest("user@example.com")
+	request.CaptchaToken = "wrong"
+	err = svc.Subscribe(context.Background(), request)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	request.CaptchaToken = "solved"
+	assert.Nil(t, svc.Subscribe(context.Background(), request))
+	assert.Len(t, publisher.Calls, 1)
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+	siteVerify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "10.0.0.1", r.PostForm.Get("remoteip"))
+		_ = json.NewEncoder(w).Encode(map[string]bool{"success": r.PostForm.Get("response") == "solved"})
+	}))
+	defer siteVerify.Close()
+	verifier := provider.NewSiteVerifyCaptchaVerifier(siteVerify.URL, "secret")
+
+	ok, err := verifier.Verify(context.Background(), "solved", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = verifier.Verify(context.Background(), "forged", "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
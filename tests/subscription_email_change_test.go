@@ -0,0 +1,333 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/common/tokens"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/repository/base"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/user"
+	subscriptionService "weatherApi/internal/service/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// subscriptionStore keeps users and subscriptions in memory answering queries used by subscription service
+type subscriptionStore struct {
+	users         map[uint]*user.UserModel
+	subscriptions map[uint]*subscription.SubscriptionModel
+}
+
+func newSubscriptionStore() *subscriptionStore {
+	return &subscriptionStore{
+		users:         make(map[uint]*user.UserModel),
+		subscriptions: make(map[uint]*subscription.SubscriptionModel),
+	}
+}
+
+func (s *subscriptionStore) addUser(email string) *user.UserModel {
+	u := &user.UserModel{Model: gorm.Model{ID: uint(len(s.users) + 1)}, Email: email}
+	s.users[u.ID] = u
+	return u
+}
+
+func (s *subscriptionStore) addSubscription(sub subscription.SubscriptionModel) *subscription.SubscriptionModel {
+	sub.ID = uint(len(s.subscriptions) + 1)
+	s.subscriptions[sub.ID] = &sub
+	return &sub
+}
+
+func (s *subscriptionStore) findSubscription(match func(*subscription.SubscriptionModel) bool) (*subscription.SubscriptionModel, error) {
+	for _, sub := range s.subscriptions {
+		if !sub.DeletedAt.Valid && match(sub) {
+			found := *sub
+			return &found, nil
+		}
+	}
+	return nil, base.ErrNotFound
+}
+
+func (s *subscriptionStore) service(publisher *broker.MockRabbitMQPublisher) *subscriptionService.SubscriptionService {
+	userRepo := &user.MockUserRepository{
+		FindOneOrNoneFn: func(query any, args ...any) (*user.UserModel, error) {
+			for _, u := range s.users {
+				if (query == "id = ?" && u.ID == args[0]) || (query == "LOWER(email) = LOWER(?)" && strings.EqualFold(u.Email, args[0].(string))) {
+					found := *u
+					return &found, nil
+				}
+			}
+			return nil, base.ErrNotFound
+		},
+		FindOneOrCreateFn: func(conditions map[string]any, e *user.UserModel) (*user.UserModel, error) {
+			for _, u := range s.users {
+				if u.Email == conditions["email"] {
+					return u, nil
+				}
+			}
+			return s.addUser(e.Email), nil
+		},
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(query any, args ...any) (*subscription.SubscriptionModel, error) {
+			return s.findSubscription(func(sub *subscription.SubscriptionModel) bool {
+				switch query {
+				case "user_id = ? AND LOWER(TRIM(city)) = LOWER(TRIM(?))":
+					return sub.UserID == args[0] && sameCity(sub.City, args[1].(string))
+				case "user_id = ? AND LOWER(TRIM(city)) = LOWER(TRIM(?)) AND id <> ?":
+					return sub.UserID == args[0] && sameCity(sub.City, args[1].(string)) && sub.ID != args[2]
+				case "id = ? AND is_confirmed = ?":
+					return sub.ID == args[0] && sub.IsConfirmed == args[1]
+				case "id = ?":
+					return sub.ID == args[0]
+				}
+				panic("unexpected query " + query.(string))
+			})
+		},
+		CreateOneFn: func(e *subscription.SubscriptionModel) error {
+			*e = *s.addSubscription(*e)
+			return nil
+		},
+		UpdateFn: func(e *subscription.SubscriptionModel) error {
+			saved := *e
+			s.subscriptions[e.ID] = &saved
+			return nil
+		},
+		FindLatestDeletedFn: func(userID uint, city string) (*subscription.SubscriptionModel, error) {
+			for _, sub := range s.subscriptions {
+				if sub.DeletedAt.Valid && sub.UserID == userID && sameCity(sub.City, city) {
+					found := *sub
+					return &found, nil
+				}
+			}
+			return nil, base.ErrNotFound
+		},
+		RestoreFn: func(e *subscription.SubscriptionModel) error {
+			e.DeletedAt = gorm.DeletedAt{}
+			saved := *e
+			s.subscriptions[e.ID] = &saved
+			return nil
+		},
+	}
+	return subscriptionService.NewSubscriptionService(logger.NewNoOpLogger(), subRepo, userRepo, publisher, 60, newTestSigner())
+}
+
+func sameCity(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+func publishedTask(t *testing.T, call broker.PublishCall) dto.ConfirmationEmailTask {
+	var task dto.ConfirmationEmailTask
+	require.NoError(t, json.Unmarshal(call.Payload, &task))
+	return task
+}
+
+func TestResubscribeRestoresUnsubscribedSubscription(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("test@example.com")
+	lastSent := time.Now().Add(-48 * time.Hour)
+	store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Lviv", IsConfirmed: true})
+	old := store.addSubscription(subscription.SubscriptionModel{
+		UserID:      owner.ID,
+		City:        " kyiv",
+		Frequency:   constants.FrequencyHourly,
+		IsConfirmed: true,
+		LastSentAt:  &lastSent,
+	})
+	store.subscriptions[old.ID].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	require.Nil(t, service.Subscribe(context.Background(), subscribeRequest("test@example.com")))
+
+	require.Len(t, store.subscriptions, 2, "unsubscribed row of the city is restored instead of creating new one")
+	restored := store.subscriptions[old.ID]
+	assert.False(t, restored.DeletedAt.Valid)
+	assert.False(t, restored.IsConfirmed, "restored subscription has to be confirmed again")
+	assert.Equal(t, "Kyiv", restored.City)
+	assert.Equal(t, constants.FrequencyDaily, restored.Frequency)
+	assert.Equal(t, &lastSent, restored.LastSentAt)
+
+	require.Len(t, publisher.Calls, 1)
+	assert.Nil(t, service.ConfirmSubscription(context.Background(), publishedToken(t, publisher.Calls[0])))
+	assert.True(t, store.subscriptions[old.ID].IsConfirmed)
+}
+
+func TestEmailChangeKeepsOldAddressUntilConfirmed(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("old@example.com")
+	sub := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	ctx := context.Background()
+
+	require.Nil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "new@example.com"}))
+	assert.Equal(t, owner.ID, store.subscriptions[sub.ID].UserID, "old address stays active until confirmation")
+	settings, appErr := service.Settings(ctx, manageToken)
+	require.Nil(t, appErr)
+	require.NotNil(t, settings.PendingEmail)
+	assert.Equal(t, "new@example.com", *settings.PendingEmail)
+
+	require.Len(t, publisher.Calls, 1)
+	task := publishedTask(t, publisher.Calls[0])
+	assert.True(t, task.EmailChange)
+	assert.Equal(t, "new@example.com", task.Email)
+
+	require.Nil(t, service.ConfirmSubscription(ctx, task.Token))
+	moved := store.subscriptions[sub.ID]
+	assert.Equal(t, "new@example.com", store.users[moved.UserID].Email)
+	assert.True(t, moved.IsConfirmed)
+	assert.Nil(t, moved.PendingEmail)
+	assert.Equal(t, []string{"old@example.com"}, moved.PreviousEmailList(), "erasure reaches emails sent to old address")
+
+	err := service.ConfirmSubscription(ctx, task.Token)
+	require.NotNil(t, err, "email change link is single use")
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+}
+
+func TestEmailChangeRevokesLinksOfPreviousAddress(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("old@example.com")
+	sub := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+	unsubscribeToken := signTestToken(tokens.PurposeUnsubscribe, sub.ID, time.Now().Add(time.Hour))
+	ctx := context.Background()
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	require.Nil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "new@example.com"}))
+	require.Nil(t, service.ConfirmSubscription(ctx, publishedTask(t, publisher.Calls[0]).Token))
+
+	_, err := service.Settings(ctx, manageToken)
+	require.NotNil(t, err, "manage link emailed to previous address is revoked")
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+	_, err = service.Pause(ctx, manageToken, &dto.PauseRequest{})
+	require.NotNil(t, err)
+	_, err = service.Resume(ctx, manageToken)
+	require.NotNil(t, err)
+	require.NotNil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "old@example.com"}))
+	_, err = service.UnsubscribePreview(ctx, unsubscribeToken)
+	require.NotNil(t, err)
+	require.NotNil(t, service.Unsubscribe(ctx, unsubscribeToken))
+	assert.Nil(t, store.subscriptions[sub.ID].PausedAt)
+
+	newManageToken, issueErr := newTestSigner().Issue(tokens.PurposeManage, sub.ID, store.subscriptions[sub.ID].TokenVersion)
+	require.NoError(t, issueErr)
+	settings, err := service.Settings(ctx, newManageToken)
+	require.Nil(t, err, "links emailed to new address work")
+	assert.Equal(t, "Kyiv", settings.City)
+}
+
+func TestResubscribeRevokesLinksOfPreviousSubscriber(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("test@example.com")
+	old := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, old.ID, time.Now().Add(time.Hour))
+	store.subscriptions[old.ID].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	ctx := context.Background()
+	require.Nil(t, service.Subscribe(ctx, subscribeRequest("test@example.com")))
+	require.Nil(t, service.ConfirmSubscription(ctx, publishedToken(t, publisher.Calls[0])))
+
+	_, err := service.Settings(ctx, manageToken)
+	require.NotNil(t, err, "links emailed before unsubscribe don't manage restored subscription")
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+}
+
+func TestEmailChangeNewerRequestRevokesOlderLink(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("old@example.com")
+	sub := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+	ctx := context.Background()
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	require.Nil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "first@example.com"}))
+	first := publishedTask(t, publisher.Calls[0])
+
+	// next request moves expiry, which the previous link is bound to
+	expires := store.subscriptions[sub.ID].EmailChangeExpires.Add(-time.Minute)
+	store.subscriptions[sub.ID].EmailChangeExpires = &expires
+	first.Token = signTestToken(tokens.PurposeEmailChange, sub.ID, expires)
+	require.Nil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "second@example.com"}))
+
+	err := service.ConfirmSubscription(ctx, first.Token)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+	assert.Equal(t, owner.ID, store.subscriptions[sub.ID].UserID)
+}
+
+func TestEmailChangeRejected(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("old@example.com")
+	other := store.addUser("taken@example.com")
+	sub := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	store.addSubscription(subscription.SubscriptionModel{UserID: other.ID, City: "kyiv ", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	ctx := context.Background()
+
+	err := service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "taken@example.com"})
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, err.Code)
+
+	err = service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "Taken@Example.com"})
+	require.NotNil(t, err, "address taken in other case is rejected")
+	assert.Equal(t, http.StatusConflict, err.Code)
+
+	err = service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "OLD@example.com"})
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+
+	unsubscribeToken := signTestToken(tokens.PurposeUnsubscribe, sub.ID, time.Now().Add(time.Hour))
+	err = service.RequestEmailChange(ctx, unsubscribeToken, &dto.EmailChangeRequest{Email: "new@example.com"})
+	require.NotNil(t, err, "only manage token authorizes email change")
+	assert.Empty(t, publisher.Calls)
+}
+
+func TestSubscribeSeveralCities(t *testing.T) {
+	store := newSubscriptionStore()
+	publisher := broker.NewMockRabbitMQPublisher()
+	service := store.service(publisher)
+	ctx := context.Background()
+
+	kyiv := subscribeRequest("test@example.com")
+	require.Nil(t, service.Subscribe(ctx, kyiv))
+	require.Nil(t, service.ConfirmSubscription(ctx, publishedToken(t, publisher.Calls[0])))
+	lviv := subscribeRequest("test@example.com")
+	lviv.City = "Lviv"
+	require.Nil(t, service.Subscribe(ctx, lviv))
+	require.Len(t, store.subscriptions, 2, "each city gets its own subscription")
+	require.Nil(t, service.ConfirmSubscription(ctx, publishedToken(t, publisher.Calls[1])))
+
+	again := subscribeRequest("test@example.com")
+	again.City = " KYIV"
+	err := service.Subscribe(ctx, again)
+	require.NotNil(t, err, "one subscription per city")
+	assert.Equal(t, http.StatusConflict, err.Code)
+
+	// moving Lviv subscription to address subscribed to another city is allowed
+	other := store.addUser("other@example.com")
+	store.addSubscription(subscription.SubscriptionModel{UserID: other.ID, City: "Odesa", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, 2, time.Now().Add(time.Hour))
+	require.Nil(t, service.RequestEmailChange(ctx, manageToken, &dto.EmailChangeRequest{Email: "other@example.com"}))
+	require.Nil(t, service.ConfirmSubscription(ctx, publishedTask(t, publisher.Calls[2]).Token))
+	assert.Equal(t, other.ID, store.subscriptions[2].UserID)
+	assert.Equal(t, uint(1), store.subscriptions[1].UserID, "Kyiv subscription stays with the first address")
+}
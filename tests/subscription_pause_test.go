@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/common/tokens"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/repository/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseAndResumeSubscription(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("test@example.com")
+	sub := store.addSubscription(subscription.SubscriptionModel{UserID: owner.ID, City: "Kyiv", IsConfirmed: true})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+	service := store.service(broker.NewMockRabbitMQPublisher())
+	ctx := context.Background()
+
+	settings, err := service.Pause(ctx, manageToken, &dto.PauseRequest{Days: 7})
+	require.Nil(t, err)
+	assert.True(t, settings.Paused)
+	require.NotNil(t, settings.ResumeAt)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *settings.ResumeAt, time.Minute)
+	assert.Equal(t, constants.PauseUser, *store.subscriptions[sub.ID].PauseReason)
+
+	settings, err = service.Pause(ctx, manageToken, &dto.PauseRequest{})
+	require.Nil(t, err)
+	assert.True(t, settings.Paused)
+	assert.Nil(t, settings.ResumeAt, "pause without days lasts until resumed")
+
+	settings, err = service.Resume(ctx, manageToken)
+	require.Nil(t, err)
+	assert.False(t, settings.Paused)
+	assert.Nil(t, store.subscriptions[sub.ID].PausedAt)
+}
+
+func TestPauseRejectedWhileDeliverySuspended(t *testing.T) {
+	store := newSubscriptionStore()
+	owner := store.addUser("test@example.com")
+	pausedAt := time.Now()
+	reason := constants.PauseBounced
+	sub := store.addSubscription(subscription.SubscriptionModel{
+		UserID:      owner.ID,
+		City:        "Kyiv",
+		IsConfirmed: true,
+		PausedAt:    &pausedAt,
+		PauseReason: &reason,
+	})
+	manageToken := signTestToken(tokens.PurposeManage, sub.ID, time.Now().Add(time.Hour))
+	service := store.service(broker.NewMockRabbitMQPublisher())
+
+	_, err := service.Resume(context.Background(), manageToken)
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, err.Code)
+	assert.Equal(t, constants.PauseBounced, *store.subscriptions[sub.ID].PauseReason)
+
+	_, err = service.Pause(context.Background(), signTestToken(tokens.PurposeUnsubscribe, sub.ID, time.Now().Add(time.Hour)), &dto.PauseRequest{})
+	require.NotNil(t, err, "only manage token pauses subscription")
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+}
+
+func TestSubscriptionIsPaused(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	assert.False(t, (&subscription.SubscriptionModel{}).IsPaused(now))
+	assert.True(t, (&subscription.SubscriptionModel{PausedAt: &past}).IsPaused(now))
+	assert.True(t, (&subscription.SubscriptionModel{PausedAt: &past, ResumeAt: &future}).IsPaused(now))
+	assert.False(t, (&subscription.SubscriptionModel{PausedAt: &past, ResumeAt: &past}).IsPaused(now), "snooze ends at resume date")
+}
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/common/tokens"
 	"weatherApi/internal/logger"
 	"weatherApi/internal/repository/base"
 
@@ -16,17 +19,41 @@ import (
 	"weatherApi/internal/repository/user"
 	"weatherApi/internal/server/routes"
 	subscriptionService "weatherApi/internal/service/subscription"
+	serviceErrors "weatherApi/internal/service/subscription/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
+func newTestSigner() *tokens.Signer {
+	signer, err := tokens.NewSigner("test", map[string][]byte{"test": []byte("secret")}, map[tokens.Purpose]time.Duration{
+		tokens.PurposeUnsubscribe: time.Hour,
+		tokens.PurposeManage:      time.Hour,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return signer
+}
+
+func signTestToken(purpose tokens.Purpose, subscriptionID uint, expiresAt time.Time) string {
+	token, err := newTestSigner().Sign(purpose, subscriptionID, 0, expiresAt)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
 func setupTestRouter(handler *routes.SubscriptionHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	r.POST("/subscribe", handler.Subscribe)
 	r.GET("/confirm/:token", handler.ConfirmSubscription)
-	r.GET("/unsubscribe/:token", handler.Unsubscribe)
+	r.GET("/unsubscribe/:token", handler.UnsubscribePreview)
+	r.POST("/unsubscribe/:token", handler.Unsubscribe)
+	r.POST("/one-click/unsubscribe/:token", handler.OneClickUnsubscribe)
+	r.GET("/manage/:token", handler.Settings)
 	return r
 }
 
@@ -53,7 +80,7 @@ func TestSubscribeSuccess(t *testing.T) {
 	publisher := broker.NewMockRabbitMQPublisher()
 	log := logger.NewNoOpLogger()
 
-	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, publisher, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
@@ -78,7 +105,7 @@ func TestSubscribeSuccess(t *testing.T) {
 
 func TestSubscribeInvalidInput(t *testing.T) {
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, nil, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, nil, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
@@ -97,8 +124,31 @@ func TestSubscribeInvalidInput(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Invalid input")
 }
 
+func TestSubscribeRejectsTooLongEmail(t *testing.T) {
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, nil, nil, nil, 60, newTestSigner())
+	handler := routes.NewSubscriptionHandler(log, service)
+	router := setupTestRouter(handler)
+
+	// users.email holds at most 255 characters
+	body, _ := json.Marshal(gin.H{
+		"email":     strings.Repeat("a", 64) + "@" + strings.Repeat("b", 60) + "." + strings.Repeat("c", 60) + "." + strings.Repeat("d", 60) + "." + strings.Repeat("e", 60) + ".com",
+		"city":      "Kyiv",
+		"frequency": "daily",
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid input")
+}
+
 func TestConfirmSubscriptionSuccess(t *testing.T) {
 	sub := &subscription.SubscriptionModel{
+		Model:        gorm.Model{ID: 1},
 		IsConfirmed:  false,
 		TokenExpires: time.Now().Add(1 * time.Hour),
 	}
@@ -111,13 +161,14 @@ func TestConfirmSubscriptionSuccess(t *testing.T) {
 		},
 	}
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/some-valid-token", nil)
+	token := signTestToken(tokens.PurposeConfirm, 1, sub.TokenExpires)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/"+token, nil)
 
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -133,13 +184,14 @@ func TestConfirmSubscriptionInvalidToken(t *testing.T) {
 		},
 	}
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/some-valid-token", nil)
+	token := signTestToken(tokens.PurposeConfirm, 1, time.Now().Add(time.Hour))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/"+token, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -161,13 +213,14 @@ func TestTokenExpired(t *testing.T) {
 		},
 	}
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/some-valid-token", nil)
+	token := signTestToken(tokens.PurposeConfirm, 1, sub.TokenExpires)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/confirm/"+token, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -188,13 +241,14 @@ func TestUnsubscribeSuccess(t *testing.T) {
 		},
 	}
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/unsubscribe/some-token", nil)
+	token := signTestToken(tokens.PurposeUnsubscribe, 1, time.Now().Add(time.Hour))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/unsubscribe/"+token, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -209,16 +263,249 @@ func TestUnsubscribeSubscriptionTokenNotFound(t *testing.T) {
 		},
 	}
 	log := logger.NewNoOpLogger()
-	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60)
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
 	handler := routes.NewSubscriptionHandler(log, service)
 	router := setupTestRouter(handler)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/unsubscribe/some-token", nil)
+	token := signTestToken(tokens.PurposeUnsubscribe, 1, time.Now().Add(time.Hour))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/unsubscribe/"+token, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	assert.Contains(t, w.Body.String(), "Token not found")
 }
+
+func TestSubscribeStoresWeatherPreferences(t *testing.T) {
+	userRepo := &user.MockUserRepository{
+		FindOneOrCreateFn: func(_ map[string]any, e *user.UserModel) (*user.UserModel, error) {
+			e.ID = 1
+			return e, nil
+		},
+	}
+
+	var created *subscription.SubscriptionModel
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			return nil, base.ErrNotFound
+		},
+		CreateOneFn: func(entity *subscription.SubscriptionModel) error {
+			created = entity
+			return nil
+		},
+		UpdateFn: func(entity *subscription.SubscriptionModel) error {
+			return nil
+		},
+	}
+
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, userRepo, broker.NewMockRabbitMQPublisher(), 60, newTestSigner())
+	router := setupTestRouter(routes.NewSubscriptionHandler(log, service))
+
+	body, _ := json.Marshal(gin.H{
+		"email":     "test@example.com",
+		"city":      "Kyiv",
+		"frequency": "daily",
+		"units":     "imperial",
+		"lang":      "UK",
+
+		"include_air_quality": true,
+		"aqi_alert_threshold": 150,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, created) {
+		assert.Equal(t, constants.UnitsImperial, created.Units)
+		assert.Equal(t, "uk", created.Lang)
+		assert.True(t, created.IncludeAirQuality)
+		if assert.NotNil(t, created.AQIAlertThreshold) {
+			assert.Equal(t, 150, *created.AQIAlertThreshold)
+		}
+	}
+
+	body, _ = json.Marshal(gin.H{
+		"email":     "test@example.com",
+		"city":      "Kyiv",
+		"frequency": "daily",
+		"units":     "kelvin",
+	})
+	req = httptest.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfirmSubscriptionRejectsOtherPurposeAndReissuedTokens(t *testing.T) {
+	sub := &subscription.SubscriptionModel{
+		Model:        gorm.Model{ID: 1},
+		TokenExpires: time.Now().Add(time.Hour),
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			return sub, nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
+	router := setupTestRouter(routes.NewSubscriptionHandler(log, service))
+
+	tokensToReject := map[string]string{
+		"unsubscribe token": signTestToken(tokens.PurposeUnsubscribe, 1, sub.TokenExpires),
+		"reissued token":    signTestToken(tokens.PurposeConfirm, 1, sub.TokenExpires.Add(-time.Minute)),
+		"tampered token":    signTestToken(tokens.PurposeConfirm, 1, sub.TokenExpires) + "x",
+	}
+	for name, token := range tokensToReject {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/confirm/"+token, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.False(t, sub.IsConfirmed)
+		})
+	}
+}
+
+func TestLegacyTokenLinksKeepWorking(t *testing.T) {
+	const legacyToken = "0b6a6c1e-8f3d-4a53-9a55-3b1d1f3c1a2b"
+	token := legacyToken
+	sub := &subscription.SubscriptionModel{
+		Model:              gorm.Model{ID: 1},
+		LegacyConfirmToken: &token,
+		TokenExpires:       time.Now().Add(time.Hour),
+	}
+	deleted := false
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(query any, args ...any) (*subscription.SubscriptionModel, error) {
+			if !strings.HasPrefix(query.(string), "confirm_token = ?") || args[0] != legacyToken {
+				return nil, base.ErrNotFound
+			}
+			if len(args) > 1 && args[1] != sub.IsConfirmed {
+				return nil, base.ErrNotFound
+			}
+			return sub, nil
+		},
+		UpdateFn: func(_ *subscription.SubscriptionModel) error {
+			return nil
+		},
+		DeleteFn: func(_ *subscription.SubscriptionModel) error {
+			deleted = true
+			return nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
+	router := setupTestRouter(routes.NewSubscriptionHandler(log, service))
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/unsubscribe/"+legacyToken), "unconfirmed subscription")
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/confirm/"+legacyToken))
+	assert.True(t, sub.IsConfirmed)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/unsubscribe/"+legacyToken), "unsubscribe page preview")
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/unsubscribe/"+legacyToken))
+	assert.True(t, deleted)
+}
+
+func TestLegacyConfirmTokenExpires(t *testing.T) {
+	const legacyToken = "0b6a6c1e-8f3d-4a53-9a55-3b1d1f3c1a2b"
+	sub := &subscription.SubscriptionModel{
+		Model:        gorm.Model{ID: 1},
+		TokenExpires: time.Now().Add(-time.Minute),
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			return sub, nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
+
+	assert.Equal(t, serviceErrors.ErrInvalidToken, service.ConfirmSubscription(context.Background(), legacyToken))
+	assert.False(t, sub.IsConfirmed)
+}
+
+func TestSubscriptionSettings(t *testing.T) {
+	sub := &subscription.SubscriptionModel{
+		Model:       gorm.Model{ID: 1},
+		City:        "Kyiv",
+		Frequency:   constants.FrequencyDaily,
+		Units:       constants.UnitsMetric,
+		Lang:        "en",
+		IsConfirmed: true,
+	}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			return sub, nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
+	router := setupTestRouter(routes.NewSubscriptionHandler(log, service))
+
+	manageToken, err := newTestSigner().Issue(tokens.PurposeManage, 1, 0)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/manage/"+manageToken, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"city":"Kyiv"`)
+
+	unsubscribeToken, err := newTestSigner().Issue(tokens.PurposeUnsubscribe, 1, 0)
+	assert.NoError(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/manage/"+unsubscribeToken, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUnsubscribeGetDoesNotUnsubscribe(t *testing.T) {
+	deleted := 0
+	sub := &subscription.SubscriptionModel{Model: gorm.Model{ID: 1}, City: "Kyiv", IsConfirmed: true}
+	subRepo := &subscription.MockSubscriptionRepository{
+		FindOneOrNoneFn: func(_ any, _ ...any) (*subscription.SubscriptionModel, error) {
+			return sub, nil
+		},
+		DeleteFn: func(_ *subscription.SubscriptionModel) error {
+			deleted++
+			return nil
+		},
+	}
+	log := logger.NewNoOpLogger()
+	service := subscriptionService.NewSubscriptionService(log, subRepo, nil, nil, 60, newTestSigner())
+	router := setupTestRouter(routes.NewSubscriptionHandler(log, service))
+	token := signTestToken(tokens.PurposeUnsubscribe, 1, time.Now().Add(time.Hour))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/unsubscribe/"+token, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"city":"Kyiv"`)
+	assert.Zero(t, deleted, "GET must not change subscription")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(
+		http.MethodPost,
+		"/one-click/unsubscribe/"+token,
+		strings.NewReader("List-Unsubscribe=One-Click"),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, deleted)
+}
@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weatherApi/internal/broker"
+	"weatherApi/internal/common/constants"
+	"weatherApi/internal/dto"
+	"weatherApi/internal/logger"
+	"weatherApi/internal/middleware"
+	"weatherApi/internal/provider"
+	"weatherApi/internal/repository/subscription"
+	"weatherApi/internal/repository/suppression"
+	"weatherApi/internal/worker"
+
+	serviceSuppression "weatherApi/internal/service/suppression"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sesBounceNotification = `{
+	"notificationType": "Bounce",
+	"bounce": {
+		"bounceType": "Permanent",
+		"bounceSubType": "General",
+		"bouncedRecipients": [{"emailAddress": "gone@example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"}],
+		"timestamp": "2026-01-02T03:04:05.000Z"
+	}
+}`
+
+const dsnBounceEmail = "From: MAILER-DAEMON@example.com\r\n" +
+	"To: weather@example.com\r\n" +
+	"Date: Fri, 02 Jan 2026 03:04:05 +0000\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"BOUNDARY\"\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message could not be delivered.\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; gone@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 user unknown\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; busy@example.com\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"--BOUNDARY--\r\n"
+
+func TestParseEmailEvents_SES(t *testing.T) {
+	events, err := provider.ParseEmailEvents([]byte(sesBounceNotification))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "gone@example.com", events[0].Email)
+	assert.Equal(t, constants.EmailHardBounce, events[0].Type)
+	assert.Equal(t, provider.EmailEventSourceSES, events[0].Source)
+	assert.Equal(t, 2026, events[0].OccurredAt.Year())
+
+	envelope, _ := json.Marshal(map[string]string{"Type": "Notification", "Message": `{
+		"notificationType": "Complaint",
+		"complaint": {"complainedRecipients": [{"emailAddress": "angry@example.com"}], "complaintFeedbackType": "abuse"}
+	}`})
+	events, err = provider.ParseEmailEvents(envelope)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, constants.EmailComplaint, events[0].Type)
+	assert.Equal(t, "angry@example.com", events[0].Email)
+
+	confirmation := []byte(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription"}`)
+	events, err = provider.ParseEmailEvents(confirmation)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	subscribeURL, ok := provider.SNSSubscribeURL(confirmation)
+	require.True(t, ok)
+	assert.Equal(t, "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription", subscribeURL)
+	_, ok = provider.SNSSubscribeURL([]byte(sesBounceNotification))
+	assert.False(t, ok)
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPSNSConfirmer_OnlyVisitsSNSHosts(t *testing.T) {
+	var visited []string
+	confirmer := provider.NewHTTPSNSConfirmer(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		visited = append(visited, req.URL.String())
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})})
+	ctx := context.Background()
+
+	require.NoError(t, confirmer.Confirm(ctx, "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"))
+	require.Len(t, visited, 1)
+
+	for _, subscribeURL := range []string{
+		"http://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription",
+		"https://sns.eu-west-1.amazonaws.com.evil.example/",
+		"https://evil.example/?host=sns.eu-west-1.amazonaws.com",
+		"https://user@sns.eu-west-1.amazonaws.com:8443/",
+		"https://169.254.169.254/latest/meta-data",
+	} {
+		assert.ErrorIs(t, confirmer.Confirm(ctx, subscribeURL), provider.ErrInvalidSubscribeURL, subscribeURL)
+	}
+	assert.Len(t, visited, 1, "non SNS hosts are never requested")
+}
+
+func TestHTTPSNSConfirmer_DoesNotFollowRedirects(t *testing.T) {
+	var visited []string
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		visited = append(visited, req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusFound,
+			Header:     http.Header{"Location": []string{"https://169.254.169.254/latest/meta-data"}},
+			Body:       http.NoBody,
+		}, nil
+	})}
+	confirmer := provider.NewHTTPSNSConfirmer(client)
+
+	assert.Error(t, confirmer.Confirm(context.Background(), "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription"))
+	assert.Len(t, visited, 1, "redirect target is never requested")
+	assert.Nil(t, client.CheckRedirect, "passed client is not modified")
+}
+
+func TestWebhookSecretMiddleware_AcceptsSecretOnlyInHeader(t *testing.T) {
+	router := gin.New()
+	router.POST("/webhook", middleware.WebhookSecretMiddleware("s3cret"), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	post := func(target, header string) int {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if header != "" {
+			req.Header.Set(middleware.WebhookSecretHeader, header)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, post("/webhook", "s3cret"))
+	assert.Equal(t, http.StatusUnauthorized, post("/webhook", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, post("/webhook?secret=s3cret", ""))
+}
+
+func TestParseEmailEvents_SendGrid(t *testing.T) {
+	events, err := provider.ParseEmailEvents([]byte(`[
+		{"email": "gone@example.com", "event": "bounce", "type": "bounce", "status": "5.1.1", "timestamp": 1767323045},
+		{"email": "full@example.com", "event": "bounce", "type": "blocked", "status": "4.2.2"},
+		{"email": "angry@example.com", "event": "spamreport"},
+		{"email": "happy@example.com", "event": "open"}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, constants.EmailHardBounce, events[0].Type)
+	assert.Equal(t, constants.EmailSoftBounce, events[1].Type)
+	assert.Equal(t, constants.EmailComplaint, events[2].Type)
+	assert.Equal(t, time.Unix(1767323045, 0).UTC(), events[0].OccurredAt)
+}
+
+func TestParseEmailEvents_Invalid(t *testing.T) {
+	_, err := provider.ParseEmailEvents([]byte("not json"))
+	assert.ErrorIs(t, err, provider.ErrUnsupportedEmailEvent)
+}
+
+func TestParseDSN(t *testing.T) {
+	events, err := provider.ParseDSN(strings.NewReader(dsnBounceEmail))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "gone@example.com", events[0].Email)
+	assert.Equal(t, constants.EmailHardBounce, events[0].Type)
+	assert.Contains(t, events[0].Details, "user unknown")
+	assert.Equal(t, provider.EmailEventSourceDSN, events[0].Source)
+
+	assert.Equal(t, "busy@example.com", events[1].Email)
+	assert.Equal(t, constants.EmailSoftBounce, events[1].Type)
+
+	_, err = provider.ParseDSN(strings.NewReader("Subject: hi\r\n\r\nplain message"))
+	assert.ErrorIs(t, err, provider.ErrUnsupportedEmailEvent)
+}
+
+type pauseCall struct {
+	email  string
+	reason constants.PauseReason
+}
+
+func newTestSuppressionService(threshold int) (*serviceSuppression.Service, *suppression.MockSuppressionRepository, *[]pauseCall) {
+	suppressionRepo := &suppression.MockSuppressionRepository{}
+	var paused []pauseCall
+	subscriptionRepo := &subscription.MockSubscriptionRepository{
+		PauseByEmailFn: func(email string, reason constants.PauseReason, at time.Time) (int64, error) {
+			paused = append(paused, pauseCall{email: email, reason: reason})
+			return 1, nil
+		},
+	}
+	return serviceSuppression.NewSuppressionService(logger.NewNoOpLogger(), suppressionRepo, subscriptionRepo, threshold),
+		suppressionRepo, &paused
+}
+
+func TestSuppressionService_PausesAfterHardBounceThreshold(t *testing.T) {
+	service, repo, paused := newTestSuppressionService(2)
+	ctx := context.Background()
+	bounce := dto.EmailEvent{Email: "Gone@Example.com", Type: constants.EmailHardBounce, Source: "ses"}
+
+	require.Nil(t, service.Record(ctx, []dto.EmailEvent{bounce}))
+	assert.Empty(t, *paused, "single hard bounce is below threshold")
+	suppressed, _ := repo.IsSuppressed(ctx, "gone@example.com")
+	assert.False(t, suppressed)
+
+	require.Nil(t, service.Record(ctx, []dto.EmailEvent{bounce}))
+	require.Len(t, *paused, 1)
+	assert.Equal(t, constants.PauseBounced, (*paused)[0].reason)
+	suppressed, _ = repo.IsSuppressed(ctx, "gone@example.com")
+	assert.True(t, suppressed)
+}
+
+func TestSuppressionService_ComplaintPausesImmediately(t *testing.T) {
+	service, _, paused := newTestSuppressionService(3)
+
+	err := service.Record(context.Background(), []dto.EmailEvent{
+		{Email: "busy@example.com", Type: constants.EmailSoftBounce},
+		{Email: "angry@example.com", Type: constants.EmailComplaint},
+	})
+	require.Nil(t, err)
+	require.Len(t, *paused, 1, "soft bounces never pause subscriptions")
+	assert.Equal(t, pauseCall{email: "angry@example.com", reason: constants.PauseComplaint}, (*paused)[0])
+}
+
+func TestSuppressionService_RecordWebhookRejectsUnknownPayload(t *testing.T) {
+	service, _, _ := newTestSuppressionService(3)
+
+	err := service.RecordWebhook(context.Background(), []byte("<xml/>"))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+}
+
+func TestSuppressionService_RecordWebhookConfirmsSNSSubscription(t *testing.T) {
+	service, repo, _ := newTestSuppressionService(3)
+	confirmer := &provider.MockSNSConfirmer{}
+	service.WithSNSConfirmer(confirmer)
+	ctx := context.Background()
+
+	body := []byte(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"}`)
+	require.Nil(t, service.RecordWebhook(ctx, body))
+	assert.Equal(t, []string{"https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"}, confirmer.Confirmed)
+	assert.Empty(t, repo.Entries)
+
+	err := service.RecordWebhook(ctx, []byte(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://attacker.example/"}`))
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Code)
+	assert.Len(t, confirmer.Confirmed, 1)
+}
+
+func TestStartSubscriptionWorker_SkipsSuppressedAddresses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suppressions := &suppression.MockSuppressionRepository{}
+	_, _ = suppressions.RecordEvent(ctx, dto.EmailEvent{Email: "gone@example.com", Type: constants.EmailComplaint}, 1)
+
+	mockSubscriber := broker.NewMockEventSubscriber()
+	mockSMTP := &provider.MockSMTPClient{}
+	require.NoError(t, worker.StartSubscriptionWorker(logger.NewNoOpLogger(), ctx, mockSubscriber, mockSMTP, suppressions, nil))
+
+	data, _ := json.Marshal(dto.WeatherSubData{
+		Users: []dto.UserData{{Email: "gone@example.com"}, {Email: "ok@example.com"}},
+	})
+	require.NoError(t, mockSubscriber.SimulateMessage(ctx, broker.SendSubscriptionWeatherData, data))
+	require.Len(t, mockSMTP.SentUserData, 1)
+	assert.Equal(t, "ok@example.com", mockSMTP.SentUserData[0].Email)
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"weatherApi/internal/common/tokens"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_VerifiesOwnTokens(t *testing.T) {
+	signer := newTestSigner()
+
+	token, err := signer.Issue(tokens.PurposeUnsubscribe, 42, 3)
+	require.NoError(t, err)
+	claims, err := signer.Verify(token, tokens.PurposeUnsubscribe)
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), claims.SubscriptionID)
+	assert.Equal(t, uint(3), claims.Version)
+
+	_, err = signer.Verify(token, tokens.PurposeManage)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+
+	expired, err := signer.Sign(tokens.PurposeUnsubscribe, 42, 0, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	_, err = signer.Verify(expired, tokens.PurposeUnsubscribe)
+	assert.ErrorIs(t, err, tokens.ErrExpiredToken)
+}
+
+func TestSigner_KeyRotation(t *testing.T) {
+	oldKeyID, oldKeys, err := tokens.ParseKeys("v1:old-secret")
+	require.NoError(t, err)
+	oldSigner, err := tokens.NewSigner(oldKeyID, oldKeys, nil)
+	require.NoError(t, err)
+	token, err := oldSigner.Sign(tokens.PurposeUnsubscribe, 1, 0, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	keyID, keys, err := tokens.ParseKeys("v2:new-secret, v1:old-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", keyID)
+	signer, err := tokens.NewSigner(keyID, keys, nil)
+	require.NoError(t, err)
+	_, err = signer.Verify(token, tokens.PurposeUnsubscribe)
+	assert.NoError(t, err, "tokens signed with previous key must stay valid")
+
+	retiredKeyID, retiredKeys, err := tokens.ParseKeys("v2:new-secret")
+	require.NoError(t, err)
+	retired, err := tokens.NewSigner(retiredKeyID, retiredKeys, nil)
+	require.NoError(t, err)
+	_, err = retired.Verify(token, tokens.PurposeUnsubscribe)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+}
+
+func TestParseKeys_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "v1", "v1:", "v1:a,v1:b", "v.1:secret"} {
+		_, _, err := tokens.ParseKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"weatherApi/internal/common/constants"
 	"weatherApi/internal/common/utils"
 	"weatherApi/internal/dto"
 
@@ -50,7 +51,7 @@ func TestWeatherHandler_Success(t *testing.T) {
 
 	svc := weather.NewWeatherService(log, mockCacheRepo, mainProvider, mockFallbackProvider)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
@@ -78,7 +79,7 @@ func TestWeatherHandler_MissingCity(t *testing.T) {
 
 	svc := weather.NewWeatherService(log, mockCacheRepo, &provider.MockProvider{}, &provider.MockProvider{})
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
@@ -123,7 +124,7 @@ func TestWeatherHandler_FallbackUsed(t *testing.T) {
 
 	svc := weather.NewWeatherService(log, mockCacheRepo, mainProvider, fallbackProvider)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
@@ -175,7 +176,7 @@ func TestCityNotFound(t *testing.T) {
 
 	svc := weather.NewWeatherService(log, mockCacheRepo, mainProvider, fallbackProvider)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
@@ -206,7 +207,7 @@ func TestWeatherHandler_RealProviderWithMockedAPI(t *testing.T) {
 	mockCacheRepo := cacheRepo.NewMockCacheRepo()
 
 	svc := weather.NewWeatherService(log, mockCacheRepo, prov)
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
@@ -242,7 +243,7 @@ func TestWeatherService_Cache_HTTP(t *testing.T) {
 	mockRepo := cacheRepo.NewMockCacheRepo()
 	svc := weather.NewWeatherService(log, mockRepo, mainProvider)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
 
@@ -294,7 +295,7 @@ func TestWeatherService_WaitForLockedCache(t *testing.T) {
 	mockRepo := cacheRepo.NewMockCacheRepo()
 	svc := weather.NewWeatherService(log, mockRepo, mainProvider)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
 
@@ -359,7 +360,7 @@ func TestWeatherService_ProviderCallCount(t *testing.T) {
 
 	svc := weather.NewWeatherService(log, mockRepo, mockProv)
 
-	handler := routes.NewWeatherHandler(log, svc)
+	handler := routes.NewWeatherHandler(log, svc, nil)
 	router := gin.Default()
 	router.GET("/weather", handler.GetWeather)
 
@@ -391,3 +392,246 @@ func TestWeatherService_ProviderCallCount(t *testing.T) {
 	require.NotNil(t, resp3)
 	require.Equal(t, 1, mockProv.GetWeatherCallCount)
 }
+
+func TestWeatherHandler_Coordinates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var receivedQuery string
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedQuery = r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(utils.RandomWeatherAPIResponse()); err != nil {
+			t.Fatalf("failed to encode mock response: %v", err)
+		}
+	}))
+	defer mockAPI.Close()
+	log := logger.NewNoOpLogger()
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), provider.NewWeatherApiProvider(log, "test", mockAPI.URL))
+	handler := routes.NewWeatherHandler(log, svc, nil)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/weather?lat=50.45&lon=30.52", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "50.450000,30.520000", receivedQuery)
+}
+
+func TestWeatherHandler_InvalidCoordinates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	mockProv := &provider.MockProvider{}
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), mockProv)
+	handler := routes.NewWeatherHandler(log, svc, nil)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	for _, query := range []string{
+		"lat=50.45", "lat=abc&lon=30", "lat=91&lon=30", "lat=50&lon=-181",
+		"lat=NaN&lon=30", "lat=50&lon=nan", "lat=Inf&lon=30", "lat=50&lon=-Infinity",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/weather?"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+	assert.Equal(t, 0, mockProv.GetWeatherCallCount)
+}
+
+func TestWeatherHandler_AutoIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	mockProv := &provider.MockProvider{
+		Response: &dto.WeatherResponse{Temperature: 12, Humidity: 40, Description: "Cloudy"},
+	}
+	resolved := dto.NewCoordinatesLocation(50.45, 30.52)
+	resolved.City = "Kyiv"
+	geoLocator := &provider.MockGeoLocator{Location: &resolved}
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), mockProv)
+	handler := routes.NewWeatherHandler(log, svc, geoLocator)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	req := httptest.NewRequest(http.MethodGet, "/weather?auto=ip", nil)
+	req.RemoteAddr = "93.184.216.34:12345"
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	require.Len(t, geoLocator.RequestedIP, 1)
+	assert.Equal(t, "93.184.216.34", geoLocator.RequestedIP[0])
+	require.True(t, mockProv.LastLocation.HasCoordinates())
+	assert.Equal(t, 50.45, mockProv.LastLocation.Coordinates.Lat)
+	assert.Equal(t, 30.52, mockProv.LastLocation.Coordinates.Lon)
+}
+
+func TestWeatherHandler_AutoIPNotConfigured(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), &provider.MockProvider{})
+	handler := routes.NewWeatherHandler(log, svc, nil)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	req := httptest.NewRequest(http.MethodGet, "/weather?auto=ip", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotImplemented, resp.Code)
+}
+
+func TestWeatherApiProvider_MapsExtendedFields(t *testing.T) {
+	mockResp := utils.RandomWeatherAPIResponse()
+	mockResp.Current.WindKph = 36
+	mockResp.Current.Condition.Code = 1195
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(mockResp); err != nil {
+			t.Fatalf("failed to encode mock response: %v", err)
+		}
+	}))
+	defer mockAPI.Close()
+	log := logger.NewNoOpLogger()
+
+	data, err := provider.NewWeatherApiProvider(log, "test", mockAPI.URL).GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+
+	assert.Equal(t, mockResp.Current.FeelsLike, data.FeelsLike)
+	assert.Equal(t, mockResp.Current.PressureMb, data.Pressure)
+	assert.InDelta(t, 10.0, data.WindSpeed, 0.001)
+	assert.Equal(t, mockResp.Current.WindDegree, data.WindDirection)
+	assert.Equal(t, mockResp.Current.VisKm, data.Visibility)
+	require.NotNil(t, data.UVIndex)
+	assert.Equal(t, mockResp.Current.UV, *data.UVIndex)
+	assert.Equal(t, constants.ConditionRain, data.Condition)
+	assert.Equal(t, "https://cdn.weatherapi.com/weather/64x64/day/116.png", data.Icon)
+}
+
+func TestOpenWeatherMapProvider_MapsExtendedFields(t *testing.T) {
+	mockResp := utils.RandomOpenweatherMapAPIResponse()
+	mockResp.Weather[0].ID = 211
+	mockResp.Weather[0].Icon = "11d"
+	mockResp.Visibility = 7500
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(mockResp); err != nil {
+			t.Fatalf("failed to encode mock response: %v", err)
+		}
+	}))
+	defer mockAPI.Close()
+	log := logger.NewNoOpLogger()
+
+	data, err := provider.NewOpenWeatherApiProvider(log, "test", mockAPI.URL).GetWeather(context.Background(), dto.NewCityLocation("Kyiv"), "en")
+	require.Nil(t, err)
+
+	assert.Equal(t, mockResp.Main.FeelsLike, data.FeelsLike)
+	assert.Equal(t, mockResp.Main.Pressure, data.Pressure)
+	assert.Equal(t, mockResp.Wind.Speed, data.WindSpeed)
+	assert.Equal(t, mockResp.Wind.Deg, data.WindDirection)
+	assert.Equal(t, 7.5, data.Visibility)
+	assert.Nil(t, data.UVIndex)
+	require.NotNil(t, data.Sunrise)
+	require.NotNil(t, data.Sunset)
+	assert.Equal(t, mockResp.Sys.Sunrise, data.Sunrise.Unix())
+	assert.Equal(t, constants.ConditionThunderstorm, data.Condition)
+	assert.Equal(t, "https://openweathermap.org/img/wn/11d@2x.png", data.Icon)
+}
+
+func TestWeatherHandler_UnitsAndLang(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+	mockProv := &provider.MockProvider{
+		Response: &dto.WeatherResponse{
+			Temperature: 20,
+			FeelsLike:   10,
+			WindSpeed:   10,
+			Visibility:  10,
+			Humidity:    40,
+			Description: "Хмарно",
+			Units:       constants.UnitsMetric,
+		},
+	}
+	mockRepo := cacheRepo.NewMockCacheRepo()
+
+	svc := weather.NewWeatherService(log, mockRepo, mockProv)
+	handler := routes.NewWeatherHandler(log, svc, nil)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	req := httptest.NewRequest(http.MethodGet, "/weather?city=Kyiv&units=imperial&lang=uk", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	var data dto.WeatherResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+	assert.Equal(t, constants.UnitsImperial, data.Units)
+	assert.InDelta(t, 68.0, data.Temperature, 0.001)
+	assert.InDelta(t, 50.0, data.FeelsLike, 0.001)
+	assert.InDelta(t, 22.369, data.WindSpeed, 0.001)
+	assert.InDelta(t, 6.214, data.Visibility, 0.001)
+	assert.Equal(t, "uk", mockProv.LastLang)
+
+	// metric reading is cached per language, so switching units does not hit provider again
+	req = httptest.NewRequest(http.MethodGet, "/weather?city=Kyiv&units=standard&lang=uk", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+	assert.InDelta(t, 293.15, data.Temperature, 0.001)
+	assert.Equal(t, 1, mockProv.GetWeatherCallCount)
+
+	// other language is cached separately
+	req = httptest.NewRequest(http.MethodGet, "/weather?city=Kyiv", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, 2, mockProv.GetWeatherCallCount)
+	assert.Equal(t, "en", mockProv.LastLang)
+}
+
+func TestWeatherHandler_InvalidUnits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoOpLogger()
+
+	svc := weather.NewWeatherService(log, cacheRepo.NewMockCacheRepo(), &provider.MockProvider{})
+	handler := routes.NewWeatherHandler(log, svc, nil)
+	router := gin.Default()
+	router.GET("/weather", handler.GetWeather)
+
+	for _, query := range []string{"units=kelvin", "lang=english", "lang=u1"} {
+		req := httptest.NewRequest(http.MethodGet, "/weather?city=Kyiv&"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
+
+func TestCompass_NormalizesBearing(t *testing.T) {
+	cases := map[int]string{
+		0:    "N",
+		44:   "NE",
+		180:  "S",
+		350:  "N",
+		720:  "N",
+		-30:  "NW",
+		-90:  "W",
+		-370: "N",
+	}
+	for degrees, want := range cases {
+		assert.Equal(t, want, provider.Compass(degrees), "bearing %d", degrees)
+	}
+}